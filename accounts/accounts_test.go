@@ -0,0 +1,64 @@
+package accounts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+)
+
+func TestLoadRegistry(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "accounts.json")
+	config := `{
+		"default": "work",
+		"accounts": [
+			{"id": "work", "name": "工作号", "cookie_path": "/data/work.json"},
+			{"id": "life"}
+		]
+	}`
+	require.NoError(t, os.WriteFile(path, []byte(config), 0600))
+
+	r, err := LoadRegistry(path)
+	require.NoError(t, err)
+
+	acc, err := r.Get("")
+	require.NoError(t, err)
+	require.Equal(t, "work", acc.ID)
+	require.Equal(t, "工作号", acc.DisplayName())
+	require.Equal(t, "/data/work.json", acc.CookiePath)
+
+	acc, err = r.Get("life")
+	require.NoError(t, err)
+	require.Equal(t, "life", acc.DisplayName())
+	require.Equal(t, "cookies_life.json", filepath.Base(acc.CookiePath))
+
+	_, err = r.Get("missing")
+	require.ErrorIs(t, err, ErrAccountNotFound)
+
+	require.Len(t, r.List(), 2)
+}
+
+func TestNewRegistryValidation(t *testing.T) {
+	_, err := NewRegistry("")
+	require.Error(t, err)
+
+	_, err = NewRegistry("", &Account{ID: "a"}, &Account{ID: "a"})
+	require.Error(t, err)
+
+	_, err = NewRegistry("b", &Account{ID: "a"})
+	require.ErrorIs(t, err, ErrAccountNotFound)
+
+	_, err = NewRegistry("", &Account{ID: "a", Settings: Settings{Device: &browser.Device{Preset: "iphone"}}})
+	require.Error(t, err)
+}
+
+func TestAccountContext(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, "", FromContext(ctx))
+	require.Equal(t, "", FromContext(WithAccount(ctx, "")))
+	require.Equal(t, "work", FromContext(WithAccount(ctx, "work")))
+}
@@ -16,6 +16,7 @@ import (
 // AppServer 应用服务器结构体，封装所有服务和处理器
 type AppServer struct {
 	xiaohongshuService *XiaohongshuService
+	sessionMonitor     *SessionMonitor
 	mcpServer          *mcp.Server
 	router             *gin.Engine
 	httpServer         *http.Server
@@ -25,6 +26,7 @@ type AppServer struct {
 func NewAppServer(xiaohongshuService *XiaohongshuService) *AppServer {
 	appServer := &AppServer{
 		xiaohongshuService: xiaohongshuService,
+		sessionMonitor:     NewSessionMonitor(xiaohongshuService),
 	}
 
 	// 初始化 MCP Server（需要在创建 appServer 之后，因为工具注册需要访问 appServer）
@@ -42,6 +44,8 @@ func (s *AppServer) Start(port string) error {
 		Handler: s.router,
 	}
 
+	s.sessionMonitor.Start()
+
 	// 启动服务器的 goroutine
 	go func() {
 		logrus.Infof("启动 HTTP 服务器: %s", port)
@@ -67,5 +71,8 @@ func (s *AppServer) Start(port string) error {
 		logrus.Infof("服务器已优雅关闭")
 	}
 
+	s.sessionMonitor.Stop()
+	s.xiaohongshuService.Close()
+
 	return nil
 }
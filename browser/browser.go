@@ -1,13 +1,75 @@
 package browser
 
 import (
+	"net/url"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/devices"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/stealth"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"github.com/xpzouying/headless_browser"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
 )
 
 type browserConfig struct {
-	binPath string
+	binPath    string
+	cookier    cookies.Cookier
+	proxy      string
+	remoteURL  string
+	profileDir string
+	device     *Device
+}
+
+// Browser 浏览器实例：本地启动的 Chrome，或者远程 Chrome 中一个独立的浏览器上下文
+type Browser struct {
+	browser *rod.Browser
+
+	device Device
+	emu    devices.Device // 创建页面时模拟的设备
+
+	local  *localBrowser
+	remote *remoteBrowser
+
+	closeOnce sync.Once
+}
+
+// NewPage 创建新页面，按设备配置设置 UA、视口、区域和时区，默认开启 stealth 模式
+func (b *Browser) NewPage() *rod.Page {
+	var page *rod.Page
+	if b.device.stealth() {
+		page = stealth.MustPage(b.browser)
+	} else {
+		page = b.browser.MustPage()
+	}
+
+	if err := b.setupPage(page); err != nil {
+		_ = page.Close()
+		panic(err)
+	}
+	return page
+}
+
+func (b *Browser) setupPage(page *rod.Page) error {
+	if b.remote != nil {
+		if err := b.remote.setupPage(page); err != nil {
+			return err
+		}
+	}
+	return b.device.apply(page, b.emu)
+}
+
+// Close 关闭浏览器，可以重复调用。连接的远程 Chrome 只销毁本实例的浏览器上下文并断开连接，不会退出。
+func (b *Browser) Close() {
+	b.closeOnce.Do(func() {
+		if b.remote != nil {
+			b.remote.close()
+			return
+		}
+		b.local.close()
+	})
 }
 
 type Option func(*browserConfig)
@@ -18,29 +80,134 @@ func WithBinPath(binPath string) Option {
 	}
 }
 
-func NewBrowser(headless bool, options ...Option) *headless_browser.Browser {
-	cfg := &browserConfig{}
-	for _, opt := range options {
-		opt(cfg)
+// WithCookier 指定加载 cookies 的存储，默认使用全局的 cookies 文件。
+func WithCookier(cookier cookies.Cookier) Option {
+	return func(c *browserConfig) {
+		c.cookier = cookier
+	}
+}
+
+// WithProxy 浏览器通过代理访问网络，支持 http、https 和不带认证的 socks5，为空时不使用代理。
+// http 代理的用户名和密码在收到代理认证请求时自动填写。
+func WithProxy(proxy string) Option {
+	return func(c *browserConfig) {
+		c.proxy = proxy
+	}
+}
+
+// WithRemoteURL 连接已经运行的远程 Chrome，而不是在本地启动。
+// 支持 DevTools websocket 地址（ws://host:9222/devtools/browser/<id>）或 host:9222、http://host:9222 这样的调试地址，为空时在本地启动。
+// 连接远程浏览器时 binPath 和 headless 不生效。
+func WithRemoteURL(remoteURL string) Option {
+	return func(c *browserConfig) {
+		c.remoteURL = remoteURL
 	}
+}
 
-	opts := []headless_browser.Option{
-		headless_browser.WithHeadless(headless),
+// WithDevice 指定设备指纹配置，为空时使用默认设备
+func WithDevice(device *Device) Option {
+	return func(c *browserConfig) {
+		c.device = device
+	}
+}
+
+// WithProfile 使用持久化的 Chrome user data dir，localStorage、IndexedDB、service worker 等状态在重启后保留。
+// 目录在使用期间加锁，同一个目录同时只能被一个浏览器使用，为空时每次使用新的临时目录。
+func WithProfile(dir string) Option {
+	return func(c *browserConfig) {
+		c.profileDir = dir
 	}
-	if cfg.binPath != "" {
-		opts = append(opts, headless_browser.WithChromeBinPath(cfg.binPath))
+}
+
+func NewBrowser(headless bool, options ...Option) *Browser {
+	cfg := &browserConfig{}
+	for _, opt := range options {
+		opt(cfg)
 	}
 
 	// 加载 cookies
-	cookiePath := cookies.GetCookiesFilePath()
-	cookieLoader := cookies.NewLoadCookie(cookiePath)
+	cookieLoader := cfg.cookier
+	if cookieLoader == nil {
+		cookieLoader = cookies.NewCookier(cookies.GetCookiesFilePath())
+	}
 
-	if data, err := cookieLoader.LoadCookies(); err == nil {
-		opts = append(opts, headless_browser.WithCookies(string(data)))
+	data, err := cookieLoader.LoadCookies()
+	if err == nil {
 		logrus.Debugf("loaded cookies from filesuccessfully")
 	} else {
 		logrus.Warnf("failed to load cookies: %v", err)
+		data = nil
+	}
+
+	var proxy *url.URL
+	if cfg.proxy != "" {
+		if proxy, err = parseProxy(cfg.proxy); err != nil {
+			// 不能在代理配置错误时直连，否则会暴露真实出口
+			panic(err)
+		}
+	}
+
+	b := &Browser{device: cfg.device.resolve()}
+	base := devices.LaptopWithMDPIScreen
+
+	if cfg.remoteURL != "" {
+		if cfg.profileDir != "" {
+			logrus.Warnf("profile %s is ignored when connecting to a remote browser", cfg.profileDir)
+		}
+		if b.remote, err = connectRemote(cfg.remoteURL, data, proxy); err != nil {
+			panic(err)
+		}
+		b.browser = b.remote.browser
+		base.UserAgent = b.remote.userAgent
+	} else {
+		if b.local, err = launchLocal(headless, cfg.binPath, cfg.profileDir, data, proxy); err != nil {
+			panic(err)
+		}
+		b.browser = b.local.browser
+	}
+
+	b.emu = b.device.emulation(base)
+	b.browser.DefaultDevice(b.emu)
+	return b
+}
+
+// parseProxy 解析浏览器使用的代理。
+// Chrome 不支持带认证的 SOCKS5 代理，这种配置直接报错，避免流量绕过代理。
+func parseProxy(raw string) (*url.URL, error) {
+	u, err := configs.ParseProxy(raw)
+	if err != nil {
+		return nil, err
 	}
+	if u.Scheme == "socks5" && u.User != nil {
+		return nil, errors.New("invalid proxy: socks5 proxy with authentication is not supported by chrome")
+	}
+	return u, nil
+}
+
+// fetchTarget 可以拦截请求的目标，浏览器级别拦截所有页面，页面级别只拦截该页面
+type fetchTarget interface {
+	proto.Client
+	EachEvent(callbacks ...interface{}) (wait func())
+}
+
+// handleProxyAuth 拦截请求，收到代理的认证请求时填写用户名和密码，其余请求直接放行
+func handleProxyAuth(t fetchTarget, username, password string) error {
+	wait := t.EachEvent(func(e *proto.FetchRequestPaused) {
+		_ = proto.FetchContinueRequest{RequestID: e.RequestID}.Call(t)
+	}, func(e *proto.FetchAuthRequired) {
+		resp := &proto.FetchAuthChallengeResponse{
+			Response: proto.FetchAuthChallengeResponseResponseDefault,
+		}
+		if e.AuthChallenge.Source == proto.FetchAuthChallengeSourceProxy {
+			resp = &proto.FetchAuthChallengeResponse{
+				Response: proto.FetchAuthChallengeResponseResponseProvideCredentials,
+				Username: username,
+				Password: password,
+			}
+		}
+		_ = proto.FetchContinueWithAuth{RequestID: e.RequestID, AuthChallengeResponse: resp}.Call(t)
+	})
+	go wait()
 
-	return headless_browser.New(opts...)
+	return proto.FetchEnable{HandleAuthRequests: true}.Call(t)
 }
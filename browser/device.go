@@ -0,0 +1,144 @@
+package browser
+
+import (
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/devices"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// Device 浏览器的设备指纹配置，未设置的字段使用预设或默认值。
+// 默认所有实例都使用 rod 的 1280x800 笔记本设备，账号之间看起来完全一样，
+// 为不同账号配置不同的设备可以降低被关联的风险。
+type Device struct {
+	Preset string `json:"preset,omitempty"` // 预设设备，见 presets，其余字段覆盖预设
+
+	UserAgent      string  `json:"user_agent,omitempty"`
+	Platform       string  `json:"platform,omitempty"` // navigator.platform，如 MacIntel、Win32
+	Width          int     `json:"width,omitempty"`    // 视口宽度
+	Height         int     `json:"height,omitempty"`   // 视口高度
+	DeviceScale    float64 `json:"device_scale,omitempty"`
+	AcceptLanguage string  `json:"accept_language,omitempty"` // 请求头 Accept-Language 和 navigator.languages
+	Locale         string  `json:"locale,omitempty"`          // Intl 使用的区域，如 zh-CN
+	Timezone       string  `json:"timezone,omitempty"`        // 时区，如 Asia/Shanghai
+	Stealth        *bool   `json:"stealth,omitempty"`         // 是否注入 go-rod/stealth 的反检测脚本，默认开启
+}
+
+// presets 预设设备
+var presets = map[string]Device{
+	"mac_chrome": {
+		UserAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Platform:       "MacIntel",
+		Width:          1440,
+		Height:         900,
+		DeviceScale:    2,
+		AcceptLanguage: "zh-CN,zh;q=0.9",
+		Locale:         "zh-CN",
+		Timezone:       "Asia/Shanghai",
+	},
+	"windows_chrome": {
+		UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Platform:       "Win32",
+		Width:          1920,
+		Height:         1080,
+		DeviceScale:    1,
+		AcceptLanguage: "zh-CN,zh;q=0.9",
+		Locale:         "zh-CN",
+		Timezone:       "Asia/Shanghai",
+	},
+}
+
+// Validate 检查设备配置
+func (d *Device) Validate() error {
+	if d.Preset != "" {
+		if _, ok := presets[d.Preset]; !ok {
+			return errors.Errorf("invalid device: unknown preset %q", d.Preset)
+		}
+	}
+	if d.Width < 0 || d.Height < 0 || d.DeviceScale < 0 {
+		return errors.New("invalid device: width, height and device_scale must not be negative")
+	}
+	if (d.Width == 0) != (d.Height == 0) {
+		return errors.New("invalid device: width and height must be set together")
+	}
+	return nil
+}
+
+// resolve 将配置叠加到预设上，d 为空时返回零值
+func (d *Device) resolve() Device {
+	if d == nil {
+		return Device{}
+	}
+
+	r := presets[d.Preset]
+	if d.UserAgent != "" {
+		r.UserAgent = d.UserAgent
+	}
+	if d.Platform != "" {
+		r.Platform = d.Platform
+	}
+	if d.Width > 0 {
+		r.Width, r.Height = d.Width, d.Height
+	}
+	if d.DeviceScale > 0 {
+		r.DeviceScale = d.DeviceScale
+	}
+	if d.AcceptLanguage != "" {
+		r.AcceptLanguage = d.AcceptLanguage
+	}
+	if d.Locale != "" {
+		r.Locale = d.Locale
+	}
+	if d.Timezone != "" {
+		r.Timezone = d.Timezone
+	}
+	r.Stealth = d.Stealth
+	return r
+}
+
+// stealth 是否注入反检测脚本
+func (d Device) stealth() bool {
+	return d.Stealth == nil || *d.Stealth
+}
+
+// emulation 将配置叠加到 base 上，作为 rod 创建页面时默认模拟的设备
+func (d Device) emulation(base devices.Device) devices.Device {
+	if d.UserAgent != "" {
+		base.UserAgent = d.UserAgent
+	}
+	if d.AcceptLanguage != "" {
+		base.AcceptLanguage = d.AcceptLanguage
+	}
+	if d.Width > 0 {
+		base.Screen.Horizontal = devices.ScreenSize{Width: d.Width, Height: d.Height}
+		base.Screen.Vertical = devices.ScreenSize{Width: d.Height, Height: d.Width}
+	}
+	if d.DeviceScale > 0 {
+		base.Screen.DevicePixelRatio = d.DeviceScale
+	}
+	return base.Landscape()
+}
+
+// apply 设置 rod 设备模拟之外的 platform、区域和时区
+func (d Device) apply(page *rod.Page, emu devices.Device) error {
+	if d.Platform != "" {
+		if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
+			UserAgent:      emu.UserAgent,
+			AcceptLanguage: emu.AcceptLanguage,
+			Platform:       d.Platform,
+		}); err != nil {
+			return errors.Wrap(err, "set platform")
+		}
+	}
+	if d.Locale != "" {
+		if err := (proto.EmulationSetLocaleOverride{Locale: d.Locale}).Call(page); err != nil {
+			return errors.Wrap(err, "set locale")
+		}
+	}
+	if d.Timezone != "" {
+		if err := (proto.EmulationSetTimezoneOverride{TimezoneID: d.Timezone}).Call(page); err != nil {
+			return errors.Wrap(err, "set timezone")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/devices"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceResolve(t *testing.T) {
+	var d *Device
+	require.Equal(t, Device{}, d.resolve())
+	require.True(t, d.resolve().stealth())
+
+	stealthOff := false
+	d = &Device{Preset: "mac_chrome", Width: 1280, Height: 720, Timezone: "Asia/Tokyo", Stealth: &stealthOff}
+	require.NoError(t, d.Validate())
+
+	r := d.resolve()
+	require.Equal(t, presets["mac_chrome"].UserAgent, r.UserAgent)
+	require.Equal(t, 1280, r.Width)
+	require.Equal(t, 720, r.Height)
+	require.Equal(t, 2.0, r.DeviceScale)
+	require.Equal(t, "Asia/Tokyo", r.Timezone)
+	require.False(t, r.stealth())
+
+	emu := r.emulation(devices.LaptopWithMDPIScreen)
+	metrics := emu.MetricsEmulation()
+	require.Equal(t, 1280, metrics.Width)
+	require.Equal(t, 720, metrics.Height)
+	require.Equal(t, "zh-CN,zh;q=0.9", emu.AcceptLanguage)
+
+	require.Error(t, (&Device{Preset: "iphone"}).Validate())
+	require.Error(t, (&Device{Width: 1280}).Validate())
+	require.Error(t, (&Device{DeviceScale: -1}).Validate())
+}
@@ -0,0 +1,4 @@
+package browser
+
+// ResetBrowser 供 browser_test 包中的端到端测试使用
+var ResetBrowser = resetBrowser
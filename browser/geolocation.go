@@ -0,0 +1,98 @@
+package browser
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// Geolocation 页面模拟的地理位置
+type Geolocation struct {
+	Name      string  `json:"name,omitempty"` // 城市名，使用经纬度时为空
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy"` // 精度，单位米
+}
+
+// defaultAccuracy 模拟定位的默认精度
+const defaultAccuracy = 100
+
+// cities 常用城市的市中心坐标
+var cities = map[string]Geolocation{
+	"北京": {Latitude: 39.9042, Longitude: 116.4074},
+	"上海": {Latitude: 31.2304, Longitude: 121.4737},
+	"广州": {Latitude: 23.1291, Longitude: 113.2644},
+	"深圳": {Latitude: 22.5431, Longitude: 114.0579},
+	"杭州": {Latitude: 30.2741, Longitude: 120.1551},
+	"南京": {Latitude: 32.0603, Longitude: 118.7969},
+	"苏州": {Latitude: 31.2990, Longitude: 120.5853},
+	"成都": {Latitude: 30.5728, Longitude: 104.0668},
+	"重庆": {Latitude: 29.5630, Longitude: 106.5516},
+	"武汉": {Latitude: 30.5928, Longitude: 114.3055},
+	"西安": {Latitude: 34.3416, Longitude: 108.9398},
+	"长沙": {Latitude: 28.2282, Longitude: 112.9388},
+	"天津": {Latitude: 39.3434, Longitude: 117.3616},
+	"郑州": {Latitude: 34.7466, Longitude: 113.6254},
+	"厦门": {Latitude: 24.4798, Longitude: 118.0894},
+	"青岛": {Latitude: 36.0671, Longitude: 120.3826},
+	"昆明": {Latitude: 25.0389, Longitude: 102.7183},
+	"香港": {Latitude: 22.3193, Longitude: 114.1694},
+}
+
+// ParseGeolocation 解析地理位置：城市名（如 上海、上海市）或 "纬度,经度"（如 31.23,121.47），为空时返回 nil
+func ParseGeolocation(s string) (*Geolocation, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	if lat, lng, ok := strings.Cut(s, ","); ok {
+		latitude, err1 := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+		longitude, err2 := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+		if err1 != nil || err2 != nil {
+			return nil, errors.Errorf("invalid location %q: expect \"latitude,longitude\"", s)
+		}
+		if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+			return nil, errors.Errorf("invalid location %q: latitude or longitude out of range", s)
+		}
+		return &Geolocation{Latitude: latitude, Longitude: longitude, Accuracy: defaultAccuracy}, nil
+	}
+
+	name := strings.TrimSuffix(s, "市")
+	city, ok := cities[name]
+	if !ok {
+		return nil, errors.Errorf("unknown city %q, use \"latitude,longitude\" instead", s)
+	}
+	city.Name = name
+	city.Accuracy = defaultAccuracy
+	return &city, nil
+}
+
+// SetGeolocation 授予小红书站点定位权限并覆盖页面的地理位置，需要在页面导航之前调用。
+// 定位权限在浏览器级别生效，池中的实例归还时会撤销
+func SetGeolocation(page *rod.Page, geo *Geolocation) error {
+	b := page.Browser()
+	req := proto.BrowserGrantPermissions{
+		Permissions:      []proto.BrowserPermissionType{proto.BrowserPermissionTypeGeolocation},
+		BrowserContextID: b.BrowserContextID,
+	}
+	if u, err := url.Parse(configs.SiteURL("")); err == nil {
+		req.Origin = u.Scheme + "://" + u.Host
+	}
+	// 权限属于浏览器域，只能在浏览器级别设置
+	if err := req.Call(b); err != nil {
+		return errors.Wrap(err, "grant geolocation permission")
+	}
+
+	latitude, longitude, accuracy := geo.Latitude, geo.Longitude, geo.Accuracy
+	return errors.Wrap(proto.EmulationSetGeolocationOverride{
+		Latitude:  &latitude,
+		Longitude: &longitude,
+		Accuracy:  &accuracy,
+	}.Call(page), "set geolocation")
+}
@@ -0,0 +1,29 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGeolocation(t *testing.T) {
+	geo, err := ParseGeolocation("")
+	require.NoError(t, err)
+	require.Nil(t, geo)
+
+	geo, err = ParseGeolocation("上海市")
+	require.NoError(t, err)
+	require.Equal(t, "上海", geo.Name)
+	require.InDelta(t, 31.23, geo.Latitude, 0.01)
+
+	geo, err = ParseGeolocation(" 22.54, 114.05 ")
+	require.NoError(t, err)
+	require.Equal(t, Geolocation{Latitude: 22.54, Longitude: 114.05, Accuracy: defaultAccuracy}, *geo)
+
+	_, err = ParseGeolocation("火星")
+	require.Error(t, err)
+	_, err = ParseGeolocation("91,0")
+	require.Error(t, err)
+	_, err = ParseGeolocation("a,b")
+	require.Error(t, err)
+}
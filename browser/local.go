@@ -0,0 +1,111 @@
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultUserAgent 本地启动的浏览器使用的 UA
+const defaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+// localBrowser 本地启动的 Chrome
+type localBrowser struct {
+	browser  *rod.Browser
+	launcher *launcher.Launcher
+
+	profile *profileLock // 使用持久化 profile 时持有的锁，为空时使用临时目录
+}
+
+// launchLocal 启动本地 Chrome。profileDir 为空时使用临时目录，关闭后删除；
+// 否则加锁后使用该目录作为 user data dir，关闭后保留，下次启动时恢复 localStorage、IndexedDB 等状态。
+func launchLocal(headless bool, binPath, profileDir string, cookies []byte, proxy *url.URL) (lb *localBrowser, err error) {
+	lb = &localBrowser{}
+
+	if profileDir != "" {
+		if lb.profile, err = lockProfile(profileDir); err != nil {
+			return nil, err
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("launch browser panic: %v", r)
+		}
+		if err != nil {
+			lb.close()
+		}
+	}()
+
+	l := launcher.New().
+		Headless(headless).
+		Set("no-sandbox").
+		Set("user-agent", defaultUserAgent)
+	if binPath != "" {
+		l = l.Bin(binPath)
+	}
+	if profileDir != "" {
+		l = l.UserDataDir(profileDir)
+	}
+	if proxy != nil {
+		l = l.Proxy(proxy.Scheme + "://" + proxy.Host)
+	}
+	lb.launcher = l
+
+	u, err := l.Launch()
+	if err != nil {
+		return lb, err
+	}
+
+	lb.browser = rod.New().ControlURL(u)
+	if err := lb.browser.Connect(); err != nil {
+		lb.browser = nil
+		return lb, err
+	}
+
+	if len(cookies) > 0 {
+		var cks []*proto.NetworkCookie
+		if err := json.Unmarshal(cookies, &cks); err != nil {
+			logrus.Warnf("failed to unmarshal cookies: %v", err)
+		} else if err := lb.browser.SetCookies(proto.CookiesToParams(cks)); err != nil {
+			return lb, errors.Wrap(err, "set cookies")
+		}
+	}
+
+	if proxy != nil && proxy.User != nil {
+		password, _ := proxy.User.Password()
+		if err := handleProxyAuth(lb.browser, proxy.User.Username(), password); err != nil {
+			return lb, errors.Wrap(err, "enable proxy auth")
+		}
+	}
+
+	return lb, nil
+}
+
+func (lb *localBrowser) close() {
+	if lb.browser != nil {
+		if err := lb.browser.Close(); err != nil {
+			logrus.Debugf("close browser: %v", err)
+			lb.launcher.Kill()
+		}
+	}
+
+	if lb.launcher != nil && lb.launcher.PID() != 0 {
+		if lb.profile != nil {
+			// Cleanup 会删除 user data dir，持久化 profile 只等待 Chrome 退出
+			lb.launcher.Delete(flags.UserDataDir)
+		}
+		lb.launcher.Cleanup()
+	}
+
+	if lb.profile != nil {
+		lb.profile.release()
+	}
+}
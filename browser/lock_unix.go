@@ -0,0 +1,26 @@
+//go:build !windows
+
+package browser
+
+import (
+	"os"
+	"syscall"
+)
+
+// openLocked 打开文件并加非阻塞的排他锁。
+// flock 作用于打开的文件描述，同一进程中两次打开同一文件也会互斥。
+func openLocked(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = f.Close()
+		if err == syscall.EWOULDBLOCK {
+			return nil, ErrProfileInUse
+		}
+		return nil, err
+	}
+	return f, nil
+}
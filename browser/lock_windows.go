@@ -0,0 +1,34 @@
+//go:build windows
+
+package browser
+
+import (
+	"os"
+	"syscall"
+)
+
+// errSharingViolation ERROR_SHARING_VIOLATION，文件已被其他句柄独占打开
+const errSharingViolation syscall.Errno = 32
+
+// openLocked 以不共享的方式打开文件，句柄关闭或进程退出前其他句柄无法打开该文件
+func openLocked(path string) (*os.File, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := syscall.CreateFile(p,
+		syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+		0, // 不共享
+		nil,
+		syscall.OPEN_ALWAYS,
+		syscall.FILE_ATTRIBUTE_NORMAL,
+		0)
+	if err != nil {
+		if err == errSharingViolation {
+			return nil, ErrProfileInUse
+		}
+		return nil, err
+	}
+	return os.NewFile(uintptr(h), path), nil
+}
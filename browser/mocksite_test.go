@@ -0,0 +1,186 @@
+package browser_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu/mocksite"
+)
+
+// 需要本地 Chrome 的端到端测试，mocksite 依赖 browser 包，所以放在外部测试包中
+
+func TestDeviceEmulation(t *testing.T) {
+	_, b := mocksite.NewBrowser(t, browser.WithDevice(&browser.Device{
+		Preset:   "windows_chrome",
+		Timezone: "America/New_York",
+		Locale:   "en-US",
+	}))
+
+	page := b.NewPage()
+	defer page.Close()
+
+	res := page.MustEval(`() => ({
+		ua: navigator.userAgent,
+		platform: navigator.platform,
+		width: window.innerWidth,
+		language: navigator.language,
+		timezone: Intl.DateTimeFormat().resolvedOptions().timeZone,
+		locale: Intl.DateTimeFormat().resolvedOptions().locale,
+	})`)
+	require.Contains(t, res.Get("ua").Str(), "Windows NT 10.0")
+	require.Equal(t, "Win32", res.Get("platform").Str())
+	require.Equal(t, 1920, res.Get("width").Int())
+	require.Equal(t, "zh-CN", res.Get("language").Str())
+	require.Equal(t, "America/New_York", res.Get("timezone").Str())
+	require.Equal(t, "en-US", res.Get("locale").Str())
+}
+
+func TestSetGeolocation(t *testing.T) {
+	site, b := mocksite.NewBrowser(t)
+
+	page := b.NewPage()
+	defer page.Close()
+
+	geo, err := browser.ParseGeolocation("成都")
+	require.NoError(t, err)
+	require.NoError(t, browser.SetGeolocation(page, geo))
+
+	page.MustNavigate(site.URL() + "/explore").MustWaitLoad()
+	res := page.MustEval(`() => new Promise((resolve, reject) =>
+		navigator.geolocation.getCurrentPosition(
+			p => resolve({lat: p.coords.latitude, lng: p.coords.longitude}),
+			e => reject(new Error(e.message))))`)
+	require.InDelta(t, geo.Latitude, res.Get("lat").Num(), 0.0001)
+	require.InDelta(t, geo.Longitude, res.Get("lng").Num(), 0.0001)
+}
+
+func TestProfilePersistsLocalStorage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "a")
+
+	site, b := mocksite.NewBrowser(t, browser.WithProfile(dir))
+	page := b.NewPage()
+	page.MustNavigate(site.URL() + "/explore").MustWaitLoad()
+	page.MustEval(`() => localStorage.setItem("device", "x")`)
+
+	// 同一个 profile 不能同时被两个浏览器使用
+	require.Panics(t, func() {
+		site.NewBrowser(t, browser.WithProfile(dir))
+	})
+	b.Close()
+
+	b = site.NewBrowser(t, browser.WithProfile(dir))
+	page = b.NewPage()
+	page.MustNavigate(site.URL() + "/explore").MustWaitLoad()
+	require.Equal(t, "x", page.MustEval(`() => localStorage.getItem("device")`).Str())
+}
+
+func TestRemoteBrowser(t *testing.T) {
+	bin, ok := mocksite.LookChrome()
+	if !ok {
+		t.Skip("SKIP: 没有找到本地 Chrome，可以通过 ROD_BROWSER_BIN 指定")
+	}
+
+	l := launcher.New().Bin(bin).Headless(true).Set("no-sandbox")
+	t.Cleanup(l.Cleanup)
+	controlURL := l.MustLaunch()
+
+	cookier := cookies.NewCookier(t.TempDir() + "/cookies.json")
+	require.NoError(t, cookier.SaveCookies([]byte(`[{"name": "web_session", "value": "a", "domain": "127.0.0.1", "path": "/"}]`)))
+
+	site, a := mocksite.NewBrowser(t, browser.WithRemoteURL(controlURL), browser.WithCookier(cookier))
+	b := site.NewBrowser(t, browser.WithRemoteURL(controlURL))
+
+	pa := a.NewPage()
+	cks, err := pa.Browser().GetCookies()
+	require.NoError(t, err)
+	require.Len(t, cks, 1)
+
+	// 每个实例使用独立的浏览器上下文，cookies 互不影响
+	pb := b.NewPage()
+	cks, err = pb.Browser().GetCookies()
+	require.NoError(t, err)
+	require.Empty(t, cks)
+
+	ua := pb.MustEval(`() => navigator.userAgent`).Str()
+	require.NotContains(t, ua, "HeadlessChrome")
+
+	// 重置只清理本实例的页面
+	require.NoError(t, browser.ResetBrowser(b))
+	_, err = pa.Eval(`() => 1`)
+	require.NoError(t, err)
+
+	// 关闭一个实例不会退出远程 Chrome
+	a.Close()
+	b.NewPage().MustNavigate(site.URL() + "/explore").MustWaitLoad()
+}
+
+func TestPoolResetsGeolocation(t *testing.T) {
+	site, _ := mocksite.NewBrowser(t)
+	launched := 0
+	pool := browser.NewPool(browser.PoolConfig{Size: 1}, func() (*browser.Browser, error) {
+		launched++
+		return site.NewBrowser(t), nil
+	})
+	defer pool.Close()
+
+	permission := func(lease *browser.Lease) string {
+		page := lease.Page()
+		page.MustNavigate(site.URL() + "/explore").MustWaitLoad()
+		return page.MustEval(`() => navigator.permissions.query({name: 'geolocation'}).then(p => p.state)`).Str()
+	}
+
+	lease, err := pool.Acquire(context.Background())
+	require.NoError(t, err)
+	geo, err := browser.ParseGeolocation("上海")
+	require.NoError(t, err)
+	require.NoError(t, browser.SetGeolocation(lease.Page(), geo))
+	require.Equal(t, "granted", permission(lease))
+	lease.Release()
+
+	// 归还后同一个实例再次借出，不再带有上一次授予的定位权限
+	lease, err = pool.Acquire(context.Background())
+	require.NoError(t, err)
+	defer lease.Release()
+	require.Equal(t, 1, launched)
+	require.NotEqual(t, "granted", permission(lease))
+}
+
+func TestPoolExclusive(t *testing.T) {
+	site, _ := mocksite.NewBrowser(t)
+	pool := browser.NewPool(browser.PoolConfig{Size: 1}, func() (*browser.Browser, error) {
+		return site.NewBrowser(t), nil
+	})
+	defer pool.Close()
+
+	lease, err := pool.Acquire(context.Background())
+	require.NoError(t, err)
+
+	// 借出的实例归还之前不能独占
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = pool.Exclusive(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	lease.Release()
+	release, err := pool.Exclusive(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, browser.PoolStats{Size: 1}, pool.Stats())
+
+	// 独占期间不再借出
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = pool.Acquire(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release()
+	lease, err = pool.Acquire(context.Background())
+	require.NoError(t, err)
+	lease.Release()
+}
@@ -0,0 +1,390 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrPoolClosed 浏览器池已关闭
+var ErrPoolClosed = errors.New("browser pool is closed")
+
+// Factory 创建一个新的浏览器实例
+type Factory func() (*Browser, error)
+
+// PoolConfig 浏览器池配置
+type PoolConfig struct {
+	Size               int           // 同时可借出的浏览器实例上限
+	IdleTimeout        time.Duration // 空闲超过该时长的实例会被回收，<=0 表示不回收
+	HealthCheckTimeout time.Duration // 借出前健康检查的超时时间
+}
+
+// PoolStats 浏览器池运行状态
+type PoolStats struct {
+	Size  int `json:"size"`
+	Idle  int `json:"idle"`
+	InUse int `json:"in_use"`
+}
+
+// Pool 可复用的浏览器池。
+// 每次借出时会在一个已启动的浏览器上创建新的页面，归还时关闭该浏览器上的所有页面，
+// 从而避免每次调用都重新启动 Chrome 和加载 cookies。
+type Pool struct {
+	cfg     PoolConfig
+	factory Factory
+
+	sem chan struct{}
+
+	// exclusive 串行化 Exclusive，避免两个调用各占一部分名额互相等待
+	exclusive sync.Mutex
+
+	mu     sync.Mutex
+	idle   []*pooledBrowser
+	inUse  int
+	gen    int
+	closed bool
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+type pooledBrowser struct {
+	browser  *Browser
+	gen      int
+	lastUsed time.Time
+}
+
+// NewPool 创建浏览器池
+func NewPool(cfg PoolConfig, factory Factory) *Pool {
+	if cfg.Size <= 0 {
+		cfg.Size = 1
+	}
+	if cfg.HealthCheckTimeout <= 0 {
+		cfg.HealthCheckTimeout = 5 * time.Second
+	}
+
+	p := &Pool{
+		cfg:     cfg,
+		factory: factory,
+		sem:     make(chan struct{}, cfg.Size),
+		stopCh:  make(chan struct{}),
+	}
+
+	if cfg.IdleTimeout > 0 {
+		p.wg.Add(1)
+		go p.evictLoop()
+	}
+
+	return p
+}
+
+// Lease 一次借出的浏览器页面，使用完毕后必须调用 Release
+type Lease struct {
+	pool    *Pool
+	pb      *pooledBrowser
+	page    *rod.Page
+	broken  bool
+	release sync.Once
+}
+
+// Page 返回本次借出的页面
+func (l *Lease) Page() *rod.Page {
+	return l.page
+}
+
+// Discard 标记浏览器实例不可复用，归还时直接关闭
+func (l *Lease) Discard() {
+	l.broken = true
+}
+
+// Stale 浏览器实例是否在 Drain 之前创建，即持有的可能是过期的会话
+func (l *Lease) Stale() bool {
+	l.pool.mu.Lock()
+	defer l.pool.mu.Unlock()
+
+	return l.pb.gen != l.pool.gen
+}
+
+// Release 归还浏览器实例。页面会被关闭，浏览器回到空闲队列。
+func (l *Lease) Release() {
+	l.release.Do(func() {
+		l.pool.put(l.pb, l.broken)
+	})
+}
+
+// Acquire 从池中借出一个页面，池满时阻塞等待直到 ctx 结束
+func (p *Pool) Acquire(ctx context.Context) (*Lease, error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "wait for browser pool")
+	}
+
+	pb, err := p.get()
+	if err != nil {
+		<-p.sem
+		return nil, err
+	}
+
+	page, err := newPage(pb.browser)
+	if err != nil {
+		p.mu.Lock()
+		p.inUse--
+		p.mu.Unlock()
+		closeBrowser(pb.browser)
+		<-p.sem
+		return nil, err
+	}
+
+	return &Lease{pool: p, pb: pb, page: page}, nil
+}
+
+// get 取出一个健康的空闲实例，没有则新建
+func (p *Pool) get() (*pooledBrowser, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrPoolClosed
+		}
+
+		var pb *pooledBrowser
+		if n := len(p.idle); n > 0 {
+			pb = p.idle[n-1]
+			p.idle = p.idle[:n-1]
+		}
+		gen := p.gen
+		p.inUse++
+		p.mu.Unlock()
+
+		if pb == nil {
+			b, err := p.factory()
+			if err != nil {
+				p.mu.Lock()
+				p.inUse--
+				p.mu.Unlock()
+				return nil, errors.Wrap(err, "launch browser")
+			}
+			return &pooledBrowser{browser: b, gen: gen}, nil
+		}
+
+		if err := p.healthCheck(pb.browser); err != nil {
+			logrus.Warnf("pooled browser unhealthy, discard it: %v", err)
+			closeBrowser(pb.browser)
+			p.mu.Lock()
+			p.inUse--
+			p.mu.Unlock()
+			continue
+		}
+
+		return pb, nil
+	}
+}
+
+// put 归还实例：重置页面后放回空闲队列，失效或过期的实例直接关闭
+func (p *Pool) put(pb *pooledBrowser, broken bool) {
+	defer func() { <-p.sem }()
+
+	if !broken {
+		if err := resetBrowser(pb.browser); err != nil {
+			logrus.Warnf("reset pooled browser failed: %v", err)
+			broken = true
+		}
+	}
+
+	p.mu.Lock()
+	p.inUse--
+	if broken || p.closed || pb.gen != p.gen {
+		p.mu.Unlock()
+		closeBrowser(pb.browser)
+		return
+	}
+	pb.lastUsed = time.Now()
+	p.idle = append(p.idle, pb)
+	p.mu.Unlock()
+}
+
+// Drain 关闭所有空闲实例，正在使用的实例归还时也会被关闭。
+// 用于 cookies 变化（登录/登出）后让后续调用使用新的会话。
+func (p *Pool) Drain() {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.gen++
+	p.mu.Unlock()
+
+	for _, pb := range idle {
+		closeBrowser(pb.browser)
+	}
+}
+
+// Exclusive 独占浏览器池：等待借出的实例全部归还后关闭所有空闲实例，在调用 release 之前不再借出，
+// Acquire 会一直等待。用于扫码登录、人工验证等需要使用账号持久化 profile 的浏览器，避免与池中的实例争用 profile。
+func (p *Pool) Exclusive(ctx context.Context) (release func(), err error) {
+	p.exclusive.Lock()
+	defer p.exclusive.Unlock()
+
+	for n := 0; n < p.cfg.Size; n++ {
+		select {
+		case p.sem <- struct{}{}:
+		case <-ctx.Done():
+			for ; n > 0; n-- {
+				<-p.sem
+			}
+			return nil, errors.Wrap(ctx.Err(), "wait for browser pool")
+		}
+	}
+	p.Drain()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			for n := 0; n < p.cfg.Size; n++ {
+				<-p.sem
+			}
+		})
+	}, nil
+}
+
+// Close 关闭浏览器池及所有空闲实例
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	close(p.stopCh)
+	p.wg.Wait()
+	p.Drain()
+}
+
+// Stats 返回池的当前状态
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return PoolStats{
+		Size:  p.cfg.Size,
+		Idle:  len(p.idle),
+		InUse: p.inUse,
+	}
+}
+
+func (p *Pool) evictLoop() {
+	defer p.wg.Done()
+
+	interval := p.cfg.IdleTimeout / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ticker.C:
+			p.evictIdle()
+		}
+	}
+}
+
+// evictIdle 回收空闲超时的实例
+func (p *Pool) evictIdle() {
+	deadline := time.Now().Add(-p.cfg.IdleTimeout)
+
+	p.mu.Lock()
+	var expired []*pooledBrowser
+	kept := p.idle[:0]
+	for _, pb := range p.idle {
+		if pb.lastUsed.Before(deadline) {
+			expired = append(expired, pb)
+			continue
+		}
+		kept = append(kept, pb)
+	}
+	p.idle = kept
+	p.mu.Unlock()
+
+	for _, pb := range expired {
+		logrus.Debugf("evict idle browser, last used at %s", pb.lastUsed.Format(time.RFC3339))
+		closeBrowser(pb.browser)
+	}
+}
+
+// healthCheck 通过打开一个空白页并执行脚本确认浏览器仍然可用
+func (p *Pool) healthCheck(b *Browser) error {
+	page, err := newPage(b)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = page.Close() }()
+
+	_, err = page.Timeout(p.cfg.HealthCheckTimeout).Eval(`() => document.readyState`)
+	return err
+}
+
+// resetBrowser 只保留一个空白页，关闭浏览器上其它所有页面，使下一次借出从干净的页面开始。
+// 保留空白页是为了避免有界面模式下关闭最后一个窗口导致 Chrome 退出。
+func resetBrowser(b *Browser) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("reset browser panic: %v", r)
+		}
+	}()
+
+	blank := b.NewPage()
+	rb := blank.Browser()
+	targets, err := proto.TargetGetTargets{}.Call(rb)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets.TargetInfos {
+		if t.Type != proto.TargetTargetInfoTypePage || t.TargetID == blank.TargetID {
+			continue
+		}
+		// 连接远程浏览器时只清理本实例所在的浏览器上下文，不影响其他账号
+		if rb.BrowserContextID != "" && t.BrowserContextID != rb.BrowserContextID {
+			continue
+		}
+		pg, err := rb.PageFromTarget(t.TargetID)
+		if err != nil {
+			return err
+		}
+		if err := pg.Close(); err != nil {
+			return err
+		}
+	}
+	// 定位等权限授予在浏览器级别，不随页面关闭，归还时撤销，避免下一个借用者继承
+	return proto.BrowserResetPermissions{BrowserContextID: rb.BrowserContextID}.Call(rb)
+}
+
+func newPage(b *Browser) (page *rod.Page, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("create page panic: %v", r)
+		}
+	}()
+
+	return b.NewPage(), nil
+}
+
+func closeBrowser(b *Browser) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Warnf("close browser panic: %v", r)
+		}
+	}()
+
+	b.Close()
+}
@@ -0,0 +1,134 @@
+package browser
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// profileLockFile profile 目录下的锁文件，持有锁的进程才能使用该目录启动 Chrome
+const profileLockFile = "xhs-profile.lock"
+
+// ErrProfileInUse profile 正在被其他浏览器使用
+var ErrProfileInUse = errors.New("browser profile is in use")
+
+// profileLock 持久化 profile 的独占锁。
+// 锁由操作系统在进程退出时自动释放，进程异常退出后不会留下失效的锁。
+type profileLock struct {
+	f *os.File
+}
+
+// lockProfile 创建 profile 目录并加锁，目录已被其他进程或同一进程中的其他浏览器使用时返回 ErrProfileInUse
+func lockProfile(dir string) (*profileLock, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "create profile dir")
+	}
+
+	f, err := openLocked(filepath.Join(dir, profileLockFile))
+	if err != nil {
+		if errors.Is(err, ErrProfileInUse) {
+			return nil, errors.Wrapf(ErrProfileInUse, "profile %s", dir)
+		}
+		return nil, errors.Wrap(err, "lock profile")
+	}
+
+	_ = f.Truncate(0)
+	_, _ = fmt.Fprintf(f, "%d\n", os.Getpid())
+	return &profileLock{f: f}, nil
+}
+
+func (l *profileLock) release() {
+	_ = l.f.Close()
+}
+
+// ProfileInfo 持久化 profile 的信息
+type ProfileInfo struct {
+	Name    string    `json:"name"` // 目录名，即账号 ID
+	Dir     string    `json:"dir"`
+	Size    int64     `json:"size"`
+	InUse   bool      `json:"in_use"`
+	ModTime time.Time `json:"mod_time"` // 目录中最近修改的文件时间
+}
+
+// ListProfiles 列出 root 下所有 profile，按名称排序
+func ListProfiles(root string) ([]ProfileInfo, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var list []ProfileInfo
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		info := ProfileInfo{Name: e.Name(), Dir: filepath.Join(root, e.Name())}
+		if err := filepath.WalkDir(info.Dir, func(_ string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// Chrome 运行时文件可能随时被删除
+				return nil
+			}
+			if fi, err := d.Info(); err == nil {
+				if !d.IsDir() && d.Name() != profileLockFile {
+					info.Size += fi.Size()
+				}
+				if fi.ModTime().After(info.ModTime) {
+					info.ModTime = fi.ModTime()
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		if l, err := lockProfile(info.Dir); err != nil {
+			info.InUse = errors.Is(err, ErrProfileInUse)
+		} else {
+			l.release()
+		}
+		list = append(list, info)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// RemoveProfile 删除 profile 目录，正在使用的 profile 返回 ErrProfileInUse
+func RemoveProfile(dir string) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+
+	l, err := lockProfile(dir)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		l.release()
+		return err
+	}
+	for _, e := range entries {
+		if e.Name() == profileLockFile {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			l.release()
+			return err
+		}
+	}
+
+	// 先释放锁再删除锁文件，Windows 上不能删除打开的文件
+	l.release()
+	return os.RemoveAll(dir)
+}
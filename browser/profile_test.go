@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileLock(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "a")
+
+	l, err := lockProfile(dir)
+	require.NoError(t, err)
+
+	_, err = lockProfile(dir)
+	require.ErrorIs(t, err, ErrProfileInUse)
+	require.ErrorIs(t, RemoveProfile(dir), ErrProfileInUse)
+
+	list, err := ListProfiles(filepath.Dir(dir))
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "a", list[0].Name)
+	require.True(t, list[0].InUse)
+
+	l.release()
+
+	l, err = lockProfile(dir)
+	require.NoError(t, err)
+	l.release()
+}
+
+func TestRemoveProfile(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "a")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "Default"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Default", "Preferences"), []byte("{}"), 0600))
+
+	list, err := ListProfiles(root)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.False(t, list[0].InUse)
+	require.Equal(t, int64(2), list[0].Size)
+
+	require.NoError(t, RemoveProfile(dir))
+	_, err = os.Stat(dir)
+	require.True(t, os.IsNotExist(err))
+
+	// 不存在的 profile 直接忽略
+	require.NoError(t, RemoveProfile(dir))
+
+	list, err = ListProfiles(filepath.Join(root, "none"))
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
@@ -0,0 +1,155 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// remoteConnectAttempts 连接远程浏览器的最大尝试次数
+	remoteConnectAttempts = 3
+	// remoteRetryInterval 第一次重试前的等待时间，之后每次翻倍
+	remoteRetryInterval = time.Second
+	// remoteDialTimeout 建立 websocket 连接的超时时间
+	remoteDialTimeout = 10 * time.Second
+)
+
+// remoteBrowser 远程 Chrome 中的一个浏览器上下文。
+// 每个实例使用独立的连接和上下文，cookies、页面和代理都与其他账号隔离；
+// 连接断开时上下文随之销毁，浏览器池的健康检查会发现失效的实例并重新连接。
+type remoteBrowser struct {
+	browser *rod.Browser
+	ws      *cdp.WebSocket
+
+	userAgent string // 远程 Chrome 的 UA，去掉了无头模式的 HeadlessChrome 标识
+
+	proxyUser *url.Userinfo // 代理的用户名和密码，为空时不需要认证
+}
+
+// connectRemote 连接远程浏览器并创建浏览器上下文，失败时按间隔重试。
+// 每次重试都重新解析地址，远程 Chrome 重启后 websocket 地址会变化。
+func connectRemote(remoteURL string, cookies []byte, proxy *url.URL) (*remoteBrowser, error) {
+	var lastErr error
+	wait := remoteRetryInterval
+
+	for i := 1; i <= remoteConnectAttempts; i++ {
+		rb, err := dialRemote(remoteURL, cookies, proxy)
+		if err == nil {
+			return rb, nil
+		}
+		lastErr = err
+
+		if i < remoteConnectAttempts {
+			logrus.Warnf("connect to remote browser failed (attempt %d/%d), retry in %s: %v", i, remoteConnectAttempts, wait, err)
+			time.Sleep(wait)
+			wait *= 2
+		}
+	}
+	return nil, errors.Wrapf(lastErr, "connect to remote browser %s", remoteURL)
+}
+
+func dialRemote(remoteURL string, cookies []byte, proxy *url.URL) (rb *remoteBrowser, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("connect remote browser panic: %v", r)
+		}
+	}()
+
+	u, err := resolveControlURL(remoteURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolve control url")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteDialTimeout)
+	defer cancel()
+
+	ws := &cdp.WebSocket{}
+	if err := ws.Connect(ctx, u, nil); err != nil {
+		return nil, err
+	}
+
+	b := rod.New().Client(cdp.New().Start(ws))
+	if err := b.Connect(); err != nil {
+		_ = ws.Close()
+		return nil, err
+	}
+
+	rb = &remoteBrowser{browser: b, ws: ws}
+	if err := rb.init(cookies, proxy); err != nil {
+		rb.close()
+		return nil, err
+	}
+	return rb, nil
+}
+
+// init 创建浏览器上下文并加载 cookies
+func (rb *remoteBrowser) init(cookies []byte, proxy *url.URL) error {
+	req := proto.TargetCreateBrowserContext{DisposeOnDetach: true}
+	if proxy != nil {
+		req.ProxyServer = proxy.Scheme + "://" + proxy.Host
+		rb.proxyUser = proxy.User
+	}
+	res, err := req.Call(rb.browser)
+	if err != nil {
+		return errors.Wrap(err, "create browser context")
+	}
+	rb.browser.BrowserContextID = res.BrowserContextID
+
+	version, err := proto.BrowserGetVersion{}.Call(rb.browser)
+	if err != nil {
+		return err
+	}
+	rb.userAgent = strings.ReplaceAll(version.UserAgent, "HeadlessChrome", "Chrome")
+
+	if len(cookies) > 0 {
+		var cks []*proto.NetworkCookie
+		if err := json.Unmarshal(cookies, &cks); err != nil {
+			logrus.Warnf("failed to unmarshal cookies: %v", err)
+		} else if err := rb.browser.SetCookies(proto.CookiesToParams(cks)); err != nil {
+			return errors.Wrap(err, "set cookies")
+		}
+	}
+
+	logrus.Debugf("connected to remote browser %s, context %s", version.Product, res.BrowserContextID)
+	return nil
+}
+
+// setupPage 同一个远程 Chrome 可能被多个账号共用，代理认证只能在页面级别拦截
+func (rb *remoteBrowser) setupPage(page *rod.Page) error {
+	if rb.proxyUser == nil {
+		return nil
+	}
+	password, _ := rb.proxyUser.Password()
+	return errors.Wrap(handleProxyAuth(page, rb.proxyUser.Username(), password), "enable proxy auth")
+}
+
+func (rb *remoteBrowser) close() {
+	// BrowserContextID 为空时 Close 会退出整个远程 Chrome，只能在上下文创建成功后调用
+	if rb.browser.BrowserContextID != "" {
+		if err := rb.browser.Close(); err != nil {
+			logrus.Debugf("dispose remote browser context: %v", err)
+		}
+	}
+	_ = rb.ws.Close()
+}
+
+// resolveControlURL 将远程浏览器地址解析为 DevTools websocket 地址。
+// 完整的 ws://host:9222/devtools/browser/<id> 直接使用，其他地址通过 /json/version 查询。
+func resolveControlURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err == nil && (u.Scheme == "ws" || u.Scheme == "wss") && strings.HasPrefix(u.Path, "/devtools/") {
+		return raw, nil
+	}
+	return launcher.ResolveURL(raw)
+}
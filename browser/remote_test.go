@@ -0,0 +1,26 @@
+package browser
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveControlURL(t *testing.T) {
+	u, err := resolveControlURL("ws://chrome:9222/devtools/browser/abc")
+	require.NoError(t, err)
+	require.Equal(t, "ws://chrome:9222/devtools/browser/abc", u)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/json/version", r.URL.Path)
+		fmt.Fprintf(w, `{"webSocketDebuggerUrl": "ws://%s/devtools/browser/def"}`, r.Host)
+	}))
+	defer srv.Close()
+
+	u, err = resolveControlURL(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, "ws://"+srv.Listener.Addr().String()+"/devtools/browser/def", u)
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+const (
+	// captchaResolveTimeout 人工完成验证的最长等待时间
+	captchaResolveTimeout = 10 * time.Minute
+	// captchaPollInterval 检查验证是否完成的间隔
+	captchaPollInterval = 2 * time.Second
+	// captchaSnapshotTimeout 触发验证时读取页面地址和截图的超时时间
+	captchaSnapshotTimeout = 10 * time.Second
+)
+
+// CaptchaBlock 账号触发验证码后的暂停状态
+type CaptchaBlock struct {
+	Account       string    `json:"account"`
+	Since         time.Time `json:"since"`
+	URL           string    `json:"url,omitempty"`
+	Step          string    `json:"step,omitempty"`
+	DiagnosticsID string    `json:"diagnostics_id,omitempty"`
+	Screenshot    string    `json:"screenshot,omitempty"` // data:image/png;base64,...
+}
+
+// CaptchaSessionStatus 人工验证会话状态
+type CaptchaSessionStatus string
+
+const (
+	CaptchaPending   CaptchaSessionStatus = "pending"   // 等待在浏览器中完成验证
+	CaptchaResolved  CaptchaSessionStatus = "resolved"  // 验证已完成，账号恢复
+	CaptchaExpired   CaptchaSessionStatus = "expired"   // 超时未完成
+	CaptchaFailed    CaptchaSessionStatus = "failed"    // 启动浏览器或保存 cookies 失败
+	CaptchaCancelled CaptchaSessionStatus = "cancelled" // 被取消
+)
+
+// CaptchaSessionInfo 人工验证会话对外展示的信息
+type CaptchaSessionInfo struct {
+	Account   string               `json:"account"`
+	Status    CaptchaSessionStatus `json:"status"`
+	CreatedAt time.Time            `json:"created_at"`
+	ExpiresAt time.Time            `json:"expires_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+	Detected  bool                 `json:"detected"` // 验证页面上是否已经出现过验证码
+	Error     string               `json:"error,omitempty"`
+}
+
+// CaptchaStatusResponse 账号的验证状态
+type CaptchaStatusResponse struct {
+	Account string              `json:"account"`
+	Blocked bool                `json:"blocked"`
+	Block   *CaptchaBlock       `json:"block,omitempty"`
+	Session *CaptchaSessionInfo `json:"session,omitempty"`
+}
+
+type captchaSession struct {
+	info   CaptchaSessionInfo
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// CaptchaManager 记录触发验证码而暂停的账号，并管理人工验证会话。
+// 账号暂停期间所有浏览器操作直接返回 CAPTCHA_REQUIRED，避免继续访问加重风控；
+// 人工验证会话打开有界面的浏览器，在其中完成验证后恢复账号。
+type CaptchaManager struct {
+	service *XiaohongshuService
+
+	// startMu 串行化启动会话，避免同一账号并发打开多个验证浏览器
+	startMu sync.Mutex
+
+	mu       sync.Mutex
+	blocks   map[string]*CaptchaBlock
+	sessions map[string]*captchaSession // 每个账号最近一次的会话
+}
+
+func newCaptchaManager(service *XiaohongshuService) *CaptchaManager {
+	return &CaptchaManager{
+		service:  service,
+		blocks:   make(map[string]*CaptchaBlock),
+		sessions: make(map[string]*captchaSession),
+	}
+}
+
+// Block 将账号标记为暂停，保存触发验证时的页面地址和截图
+func (m *CaptchaManager) Block(acc *accounts.Account, page *rod.Page, err error) {
+	block := &CaptchaBlock{
+		Account:       acc.ID,
+		Since:         time.Now(),
+		Step:          myerrors.StepOf(err),
+		DiagnosticsID: myerrors.DiagnosticsOf(err),
+	}
+
+	pp := page.Timeout(captchaSnapshotTimeout)
+	if info, err := pp.Info(); err == nil {
+		block.URL = info.URL
+	}
+	if img, err := pp.Screenshot(false, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+	}); err != nil {
+		logrus.Warnf("captcha: take screenshot for account %s failed: %v", acc.ID, err)
+	} else {
+		block.Screenshot = "data:image/png;base64," + base64.StdEncoding.EncodeToString(img)
+	}
+
+	m.mu.Lock()
+	m.blocks[acc.ID] = block
+	m.mu.Unlock()
+
+	logrus.Warnf("account %s paused: captcha required at %s", acc.ID, block.URL)
+}
+
+// Blocked 返回账号的暂停状态，未暂停时返回 nil
+func (m *CaptchaManager) Blocked(accountID string) *CaptchaBlock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if b, ok := m.blocks[accountID]; ok {
+		block := *b
+		return &block
+	}
+	return nil
+}
+
+// blockedErr 账号暂停时返回的错误
+func (m *CaptchaManager) blockedErr(accountID string) error {
+	b := m.Blocked(accountID)
+	if b == nil {
+		return nil
+	}
+	return myerrors.New(myerrors.CodeCaptchaRequired,
+		fmt.Sprintf("账号 %s 自 %s 起因触发验证码已暂停，请调用 resolve_captcha 在浏览器中完成验证",
+			accountID, b.Since.Format("2006-01-02 15:04:05")))
+}
+
+func (m *CaptchaManager) unblock(accountID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.blocks, accountID)
+}
+
+// Status 返回账号的暂停状态和最近一次的人工验证会话
+func (m *CaptchaManager) Status(acc *accounts.Account) *CaptchaStatusResponse {
+	resp := &CaptchaStatusResponse{Account: acc.ID, Block: m.Blocked(acc.ID)}
+	resp.Blocked = resp.Block != nil
+
+	m.mu.Lock()
+	if sess, ok := m.sessions[acc.ID]; ok {
+		info := sess.info
+		resp.Session = &info
+	}
+	m.mu.Unlock()
+
+	return resp
+}
+
+// Resolve 为账号启动人工验证会话：打开有界面的浏览器进入触发验证的页面，
+// 验证完成后保存 cookies 并恢复账号。账号已有进行中的会话时直接返回该会话。
+// 使用持久化 profile 时先等待账号进行中的操作结束，会话期间池中不再启动浏览器。
+func (m *CaptchaManager) Resolve(ctx context.Context, acc *accounts.Account) (*CaptchaSessionInfo, error) {
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
+
+	m.mu.Lock()
+	if sess, ok := m.sessions[acc.ID]; ok && sess.info.Status == CaptchaPending {
+		info := sess.info
+		m.mu.Unlock()
+		return &info, nil
+	}
+
+	target := configs.SiteURL("/explore")
+	if b, ok := m.blocks[acc.ID]; ok && b.URL != "" {
+		target = b.URL
+	}
+	m.mu.Unlock()
+
+	release, err := m.service.holdProfile(ctx, acc)
+	if err != nil {
+		return nil, err
+	}
+	b, err := launchResolveBrowser(acc)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	now := time.Now()
+	waitCtx, cancel := context.WithTimeout(context.Background(), captchaResolveTimeout)
+	sess := &captchaSession{
+		info: CaptchaSessionInfo{
+			Account:   acc.ID,
+			Status:    CaptchaPending,
+			CreatedAt: now,
+			ExpiresAt: now.Add(captchaResolveTimeout),
+			UpdatedAt: now,
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.sessions[acc.ID] = sess
+	m.mu.Unlock()
+
+	go func() {
+		defer close(sess.done)
+		defer cancel()
+		defer release()
+		defer closeLoginBrowser(b)
+
+		page, err := newLoginPage(b)
+		if err != nil {
+			m.finish(sess, CaptchaFailed, fmt.Sprintf("打开验证页面失败: %v", err))
+			return
+		}
+		defer func() { _ = page.Close() }()
+
+		m.wait(waitCtx, sess, acc, page, target)
+	}()
+
+	info := sess.info
+	return &info, nil
+}
+
+// Cancel 取消账号进行中的人工验证会话
+func (m *CaptchaManager) Cancel(acc *accounts.Account) *CaptchaStatusResponse {
+	m.mu.Lock()
+	sess, ok := m.sessions[acc.ID]
+	m.mu.Unlock()
+
+	if ok {
+		sess.cancel()
+		<-sess.done
+	}
+	return m.Status(acc)
+}
+
+// Close 取消所有进行中的会话
+func (m *CaptchaManager) Close() {
+	m.mu.Lock()
+	list := make([]*captchaSession, 0, len(m.sessions))
+	for _, sess := range m.sessions {
+		list = append(list, sess)
+	}
+	m.mu.Unlock()
+
+	for _, sess := range list {
+		sess.cancel()
+		<-sess.done
+	}
+}
+
+// wait 打开验证页面，轮询直到验证完成、超时或被取消。
+// 只有检测到验证码之后它又消失才视为人工完成了验证：页面还没渲染出验证码、或者跳转到了普通页面时继续等待。
+// 消失后再回到首页确认没有再次出现验证码且仍处于登录状态，才保存 cookies 并恢复账号。
+func (m *CaptchaManager) wait(ctx context.Context, sess *captchaSession, acc *accounts.Account, page *rod.Page, target string) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.finish(sess, CaptchaFailed, fmt.Sprintf("验证过程发生错误: %v", r))
+		}
+	}()
+
+	pp := page.Context(ctx)
+	if err := pp.Navigate(target); err != nil {
+		m.finish(sess, CaptchaFailed, fmt.Sprintf("打开验证页面失败: %v", err))
+		return
+	}
+
+	ticker := time.NewTicker(captchaPollInterval)
+	defer ticker.Stop()
+
+	detected := false
+	for {
+		select {
+		case <-ctx.Done():
+			switch {
+			case !errors.Is(ctx.Err(), context.DeadlineExceeded):
+				m.finish(sess, CaptchaCancelled, "")
+			case !detected:
+				m.finish(sess, CaptchaExpired, "超时未检测到验证码，请确认验证页面后重新发起")
+			default:
+				m.finish(sess, CaptchaExpired, "超时未完成验证，请重新发起")
+			}
+			return
+		case <-ticker.C:
+		}
+
+		if err := pp.WaitLoad(); err != nil {
+			continue
+		}
+		present, err := xiaohongshu.CaptchaPresent(pp)
+		if err != nil {
+			logrus.Debugf("captcha session for account %s: check page failed: %v", acc.ID, err)
+			continue
+		}
+		if present {
+			if !detected {
+				detected = true
+				m.detect(sess)
+			}
+			continue
+		}
+		if !detected {
+			continue
+		}
+
+		status, err := xiaohongshu.NewLogin(page).CheckLoginStatus(ctx)
+		if err != nil {
+			// 首页再次出现验证码时在首页继续等待验证
+			logrus.Debugf("captcha session for account %s: confirm failed: %v", acc.ID, err)
+			continue
+		}
+		if !status.IsLoggedIn {
+			m.finish(sess, CaptchaFailed, "验证后账号未登录，请重新扫码登录")
+			return
+		}
+
+		if err := saveCookies(page, acc.Cookier()); err != nil {
+			logrus.Errorf("failed to save cookies for account %s: %v", acc.ID, err)
+			m.finish(sess, CaptchaFailed, fmt.Sprintf("保存 cookies 失败: %v", err))
+			return
+		}
+		// 池中的浏览器仍然处于验证前的状态，需要重建
+		m.service.drainPool(acc)
+		m.unblock(acc.ID)
+		m.finish(sess, CaptchaResolved, "")
+		return
+	}
+}
+
+// detect 记录验证页面上已经出现过验证码
+func (m *CaptchaManager) detect(sess *captchaSession) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sess.info.Detected = true
+	sess.info.UpdatedAt = time.Now()
+}
+
+func (m *CaptchaManager) finish(sess *captchaSession, status CaptchaSessionStatus, msg string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sess.info.Status = status
+	sess.info.Error = msg
+	sess.info.UpdatedAt = time.Now()
+
+	logrus.Infof("captcha session for account %s finished: %s", sess.info.Account, status)
+}
+
+// launchResolveBrowser 启动有界面的浏览器，无论全局和账号是否配置了无头模式
+func launchResolveBrowser(acc *accounts.Account) (b *browser.Browser, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("launch browser panic: %v", r)
+		}
+	}()
+
+	return browser.NewBrowser(false,
+		browser.WithBinPath(configs.GetBinPath()),
+		browser.WithCookier(acc.Cookier()),
+		browser.WithProxy(acc.Proxy()),
+		browser.WithRemoteURL(configs.GetBrowserURL()),
+		browser.WithProfile(acc.ProfileDir()),
+		browser.WithDevice(acc.Settings.Device),
+	), nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/quota"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu/mocksite"
+)
+
+// newCaptchaTest 启动模拟站点和已登录的浏览器页面，并将账号标记为因验证码暂停
+func newCaptchaTest(t *testing.T) (*mocksite.Site, *CaptchaManager, *accounts.Account, *rod.Page) {
+	t.Helper()
+
+	site, b := mocksite.NewBrowser(t)
+	page := b.NewPage()
+	page.MustSetCookies(site.Login())
+
+	acc := &accounts.Account{ID: "a", CookiePath: filepath.Join(t.TempDir(), "cookies.json")}
+	registry, err := accounts.NewRegistry("", acc)
+	require.NoError(t, err)
+	s := NewXiaohongshuService(registry, quota.DefaultPolicies())
+	t.Cleanup(s.Close)
+
+	s.captcha.Block(acc, page, myerrors.ErrCaptchaRequired)
+	return site, s.captcha, acc, page
+}
+
+// startCaptchaWait 在后台运行人工验证会话的轮询
+func startCaptchaWait(ctx context.Context, m *CaptchaManager, acc *accounts.Account, page *rod.Page, target string) *captchaSession {
+	ctx, cancel := context.WithCancel(ctx)
+	sess := &captchaSession{
+		info:   CaptchaSessionInfo{Account: acc.ID, Status: CaptchaPending},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	m.mu.Lock()
+	m.sessions[acc.ID] = sess
+	m.mu.Unlock()
+
+	go func() {
+		defer close(sess.done)
+		defer cancel()
+		m.wait(ctx, sess, acc, page, target)
+	}()
+	return sess
+}
+
+func TestCaptchaWaitResolvesAfterCaptchaCleared(t *testing.T) {
+	site, m, acc, page := newCaptchaTest(t)
+
+	site.SetCaptcha(true)
+	sess := startCaptchaWait(context.Background(), m, acc, page, site.URL()+"/explore")
+	t.Cleanup(func() { sess.cancel(); <-sess.done })
+
+	require.Eventually(t, func() bool { return m.Status(acc).Session.Detected }, 10*time.Second, 200*time.Millisecond)
+	require.True(t, m.Status(acc).Blocked)
+
+	// 模拟人工拖动滑块完成验证
+	site.SetCaptcha(false)
+	page.MustEval(`() => document.querySelector('.red-captcha').remove()`)
+
+	<-sess.done
+	status := m.Status(acc)
+	require.Equal(t, CaptchaResolved, status.Session.Status)
+	require.False(t, status.Blocked)
+	_, err := os.Stat(acc.CookiePath)
+	require.NoError(t, err)
+}
+
+func TestCaptchaWaitKeepsBlockWithoutCaptcha(t *testing.T) {
+	site, m, acc, page := newCaptchaTest(t)
+
+	// 验证页面直接跳转到了普通页面，没有人完成任何验证
+	ctx, cancel := context.WithTimeout(context.Background(), 3*captchaPollInterval)
+	defer cancel()
+	sess := startCaptchaWait(ctx, m, acc, page, site.URL()+"/explore")
+
+	<-sess.done
+	status := m.Status(acc)
+	require.Equal(t, CaptchaExpired, status.Session.Status)
+	require.False(t, status.Session.Detected)
+	require.True(t, status.Blocked)
+	_, err := os.Stat(acc.CookiePath)
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+const usage = `cookies 管理工具
+
+用法:
+  cookies rotate [flags]   使用新密钥重新加密 cookies 文件（旧密钥为空表示原文件为明文）
+  cookies import [flags]   从浏览器导出的 cookies 文件导入（netscape / editthiscookie / rod），并检查登录状态
+  cookies export [flags]   导出账号保存的 cookies
+
+使用 "cookies <command> -h" 查看命令参数。
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "rotate":
+		runRotate(os.Args[2:])
+	case "import":
+		runImport(os.Args[2:])
+	case "export":
+		runExport(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func runRotate(args []string) {
+	fs := flag.NewFlagSet("rotate", flag.ExitOnError)
+
+	var (
+		path           string
+		accountsConfig string
+		oldKeyFile     string
+		newKeyFile     string
+	)
+	fs.StringVar(&path, "path", "", "需要重新加密的 cookies 文件，为空时处理账号配置中的所有账号")
+	fs.StringVar(&accountsConfig, "accounts", os.Getenv("XHS_ACCOUNTS_CONFIG"), "多账号配置文件路径（JSON）")
+	fs.StringVar(&oldKeyFile, "old-key-file", "", "旧密钥文件（也可通过环境变量 COOKIES_OLD_KEY 提供），为空表示原文件为明文")
+	fs.StringVar(&newKeyFile, "new-key-file", "", "新密钥文件（也可通过环境变量 COOKIES_NEW_KEY 提供）")
+	_ = fs.Parse(args)
+
+	oldKey, err := cookies.LoadKey(os.Getenv("COOKIES_OLD_KEY"), oldKeyFile)
+	if err != nil {
+		logrus.Fatalf("failed to load old key: %v", err)
+	}
+	newKey, err := cookies.LoadKey(os.Getenv("COOKIES_NEW_KEY"), newKeyFile)
+	if err != nil {
+		logrus.Fatalf("failed to load new key: %v", err)
+	}
+	if len(newKey) == 0 {
+		logrus.Fatal("new key is required")
+	}
+
+	paths := []string{path}
+	if path == "" {
+		registry, err := accounts.LoadRegistry(accountsConfig)
+		if err != nil {
+			logrus.Fatalf("failed to load accounts: %v", err)
+		}
+
+		paths = paths[:0]
+		for _, acc := range registry.List() {
+			paths = append(paths, acc.CookiePath)
+		}
+	}
+
+	for _, p := range paths {
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			logrus.Warnf("cookies 文件不存在，跳过: %s", p)
+			continue
+		}
+
+		if err := cookies.RotateKey(p, oldKey, newKey); err != nil {
+			logrus.Fatalf("重新加密 %s 失败: %v", p, err)
+		}
+		logrus.Infof("已重新加密: %s", p)
+	}
+}
+
+// accountFlags import/export 共用的账号参数
+type accountFlags struct {
+	accountsConfig string
+	accountID      string
+	cookiesKeyFile string
+	format         string
+}
+
+func (f *accountFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.accountsConfig, "accounts", os.Getenv("XHS_ACCOUNTS_CONFIG"), "多账号配置文件路径（JSON）")
+	fs.StringVar(&f.accountID, "account", "", "账号ID，为空时使用默认账号")
+	fs.StringVar(&f.cookiesKeyFile, "cookies-key-file", os.Getenv("COOKIES_KEY_FILE"), "cookies 加密密钥文件（也可通过环境变量 COOKIES_KEY 直接提供密钥）")
+	fs.StringVar(&f.format, "format", "", "cookies 格式：netscape、editthiscookie（Cookie-Editor）、rod")
+}
+
+// account 加载密钥和账号配置，返回指定的账号
+func (f *accountFlags) account() *accounts.Account {
+	key, err := cookies.LoadKey(os.Getenv("COOKIES_KEY"), f.cookiesKeyFile)
+	if err != nil {
+		logrus.Fatalf("failed to load cookies key: %v", err)
+	}
+	if err := cookies.SetEncryptionKey(key); err != nil {
+		logrus.Fatalf("invalid cookies key: %v", err)
+	}
+
+	registry, err := accounts.LoadRegistry(f.accountsConfig)
+	if err != nil {
+		logrus.Fatalf("failed to load accounts: %v", err)
+	}
+	acc, err := registry.Get(f.accountID)
+	if err != nil {
+		logrus.Fatalf("failed to get account: %v", err)
+	}
+	return acc
+}
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+
+	var (
+		af         accountFlags
+		in         string
+		check      bool
+		binPath    string
+		browserURL string
+		profileDir string
+	)
+	af.register(fs)
+	fs.StringVar(&in, "in", "", "需要导入的 cookies 文件，为空时从标准输入读取")
+	fs.BoolVar(&check, "check", true, "导入后打开浏览器检查登录状态，未登录时恢复原有 cookies")
+	fs.StringVar(&binPath, "bin", os.Getenv("ROD_BROWSER_BIN"), "浏览器二进制文件路径")
+	fs.StringVar(&browserURL, "browser-url", os.Getenv("ROD_BROWSER_URL"), "连接已运行的远程 Chrome 而不是在本地启动")
+	fs.StringVar(&profileDir, "profile-dir", "", "持久化 Chrome profile 的根目录（也可通过环境变量 XHS_PROFILE_DIR 设置）")
+	_ = fs.Parse(args)
+	configs.SetProfileDir(profileDir)
+
+	format, err := cookies.ParseFormat(af.format)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	data, err := readInput(in)
+	if err != nil {
+		logrus.Fatalf("读取 cookies 失败: %v", err)
+	}
+
+	acc := af.account()
+	cookier := acc.Cookier()
+	previous, prevErr := cookier.LoadCookies()
+
+	cks, err := cookies.ImportTo(cookier, data, format)
+	if err != nil {
+		logrus.Fatalf("导入 cookies 失败: %v", err)
+	}
+	logrus.Infof("已导入 %d 个 cookies 到账号 %s: %s", len(cks), acc.ID, acc.CookiePath)
+
+	if !check {
+		return
+	}
+
+	loggedIn, err := checkLogin(binPath, browserURL, acc)
+	if err == nil && loggedIn {
+		logrus.Info("登录状态检查通过")
+		return
+	}
+	if err != nil {
+		logrus.Errorf("检查登录状态失败: %v", err)
+	}
+
+	if prevErr != nil {
+		err = cookier.ClearCookies()
+	} else {
+		err = cookier.SaveCookies(previous)
+	}
+	if err != nil {
+		logrus.Errorf("恢复原有 cookies 失败: %v", err)
+	}
+	logrus.Fatal("导入的 cookies 未处于登录状态，已恢复原有 cookies")
+}
+
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+
+	var (
+		af  accountFlags
+		out string
+	)
+	af.register(fs)
+	fs.StringVar(&out, "out", "", "导出的文件路径，为空时输出到标准输出")
+	_ = fs.Parse(args)
+
+	format, err := cookies.ParseFormat(af.format)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	if format == "" {
+		format = cookies.FormatRod
+	}
+
+	data, err := cookies.ExportFrom(af.account().Cookier(), format)
+	if err != nil {
+		logrus.Fatalf("导出 cookies 失败: %v", err)
+	}
+
+	if out == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(out, data, 0600); err != nil {
+		logrus.Fatalf("写入 %s 失败: %v", out, err)
+	}
+	logrus.Infof("已导出到: %s", out)
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// checkLogin 使用账号保存的 cookies 启动无头浏览器并检查登录状态
+func checkLogin(binPath, browserURL string, acc *accounts.Account) (loggedIn bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("launch browser panic: %v", r)
+		}
+	}()
+
+	b := browser.NewBrowser(true, browser.WithBinPath(binPath), browser.WithRemoteURL(browserURL), browser.WithProfile(acc.ProfileDir()), browser.WithDevice(acc.Settings.Device), browser.WithCookier(acc.Cookier()), browser.WithProxy(acc.Proxy()))
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	status, err := xiaohongshu.NewLogin(page).CheckLoginStatus(context.Background())
+	if err != nil {
+		return false, err
+	}
+	return status.IsLoggedIn, nil
+}
@@ -4,23 +4,80 @@ import (
 	"context"
 	"encoding/json"
 	"flag"
+	"fmt"
+	"os"
+	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/qrterm"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
+// qrcodeTimeout 等待扫码的时长
+const qrcodeTimeout = 4 * time.Minute
+
 func main() {
 	var (
-		binPath string // 浏览器二进制文件路径
+		binPath        string // 浏览器二进制文件路径
+		browserURL     string // 远程浏览器调试地址
+		profileDir     string // 持久化 profile 根目录
+		accountsConfig string // 多账号配置文件路径
+		accountID      string // 需要登录的账号
+		cookiesKeyFile string // cookies 加密密钥文件
+		headless       bool   // 无头模式，在终端中显示二维码
+		invert         bool   // 终端二维码反色显示
 	)
 	flag.StringVar(&binPath, "bin", "", "浏览器二进制文件路径")
+	flag.StringVar(&browserURL, "browser-url", os.Getenv("ROD_BROWSER_URL"), "连接已运行的远程 Chrome 而不是在本地启动（也可通过环境变量 ROD_BROWSER_URL 设置）")
+	flag.StringVar(&profileDir, "profile-dir", "", "持久化 Chrome profile 的根目录，登录状态同时保存到账号的 profile 中（也可通过环境变量 XHS_PROFILE_DIR 设置）")
+	flag.StringVar(&accountsConfig, "accounts", "", "多账号配置文件路径（JSON），为空时使用单个默认账号")
+	flag.StringVar(&accountID, "account", "", "需要登录的账号ID，为空时使用默认账号")
+	flag.StringVar(&cookiesKeyFile, "cookies-key-file", "", "cookies 加密密钥文件（也可通过环境变量 COOKIES_KEY 直接提供密钥）")
+	flag.BoolVar(&headless, "headless", false, "无头模式：不打开浏览器窗口，在终端中显示登录二维码（适用于无图形界面的服务器）")
+	flag.BoolVar(&invert, "invert", false, "终端二维码反色显示，浅色背景的终端无法扫码时使用")
 	flag.Parse()
 
+	configs.SetProfileDir(profileDir)
+
+	if len(accountsConfig) == 0 {
+		accountsConfig = os.Getenv("XHS_ACCOUNTS_CONFIG")
+	}
+	if len(cookiesKeyFile) == 0 {
+		cookiesKeyFile = os.Getenv("COOKIES_KEY_FILE")
+	}
+
+	cookiesKey, err := cookies.LoadKey(os.Getenv("COOKIES_KEY"), cookiesKeyFile)
+	if err != nil {
+		logrus.Fatalf("failed to load cookies key: %v", err)
+	}
+	if err := cookies.SetEncryptionKey(cookiesKey); err != nil {
+		logrus.Fatalf("invalid cookies key: %v", err)
+	}
+
+	registry, err := accounts.LoadRegistry(accountsConfig)
+	if err != nil {
+		logrus.Fatalf("failed to load accounts: %v", err)
+	}
+	acc, err := registry.Get(accountID)
+	if err != nil {
+		logrus.Fatalf("failed to get account: %v", err)
+	}
+	logrus.Infof("登录账号: %s (%s)", acc.DisplayName(), acc.ID)
+
+	if headless {
+		if err := loginInTerminal(binPath, browserURL, acc, invert); err != nil {
+			logrus.Fatalf("登录失败: %v", err)
+		}
+		return
+	}
+
 	// 登录的时候，需要界面，所以不能无头模式
-	b := browser.NewBrowser(false, browser.WithBinPath(binPath))
+	b := browser.NewBrowser(false, browser.WithBinPath(binPath), browser.WithRemoteURL(browserURL), browser.WithProfile(acc.ProfileDir()), browser.WithDevice(acc.Settings.Device), browser.WithCookier(acc.Cookier()), browser.WithProxy(acc.Proxy()))
 	defer b.Close()
 
 	page := b.NewPage()
@@ -33,9 +90,10 @@ func main() {
 		logrus.Fatalf("failed to check login status: %v", err)
 	}
 
-	logrus.Infof("当前登录状态: %v", status)
+	logrus.Infof("当前登录状态: %v", status.IsLoggedIn)
 
-	if status {
+	if status.IsLoggedIn {
+		logCurrentUser(status.User)
 		return
 	}
 
@@ -44,7 +102,7 @@ func main() {
 	if err = action.Login(context.Background()); err != nil {
 		logrus.Fatalf("登录失败: %v", err)
 	} else {
-		if err := saveCookies(page); err != nil {
+		if err := saveCookies(page, acc.Cookier()); err != nil {
 			logrus.Fatalf("failed to save cookies: %v", err)
 		}
 	}
@@ -55,15 +113,65 @@ func main() {
 		logrus.Fatalf("failed to check login status after login: %v", err)
 	}
 
-	if status {
+	if status.IsLoggedIn {
 		logrus.Info("登录成功！")
+		logCurrentUser(status.User)
 	} else {
 		logrus.Error("登录流程完成但仍未登录")
 	}
 
 }
 
-func saveCookies(page *rod.Page) error {
+// logCurrentUser 输出当前登录的用户
+func logCurrentUser(user *xiaohongshu.LoginUser) {
+	if user == nil {
+		return
+	}
+	logrus.Infof("当前用户: %s（小红书号: %s，用户ID: %s）", user.Nickname, user.RedID, user.UserID)
+}
+
+// loginInTerminal 无头模式登录：提取登录二维码渲染到终端，等待扫码后保存 cookies
+func loginInTerminal(binPath, browserURL string, acc *accounts.Account, invert bool) error {
+	b := browser.NewBrowser(true, browser.WithBinPath(binPath), browser.WithRemoteURL(browserURL), browser.WithProfile(acc.ProfileDir()), browser.WithDevice(acc.Settings.Device), browser.WithCookier(acc.Cookier()), browser.WithProxy(acc.Proxy()))
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	action := xiaohongshu.NewLogin(page)
+
+	img, loggedIn, err := action.FetchQrcodeImage(context.Background())
+	if err != nil {
+		return err
+	}
+	if loggedIn {
+		logrus.Info("当前已处于登录状态")
+		return nil
+	}
+
+	fmt.Println("请使用小红书 App 扫描下方二维码登录：")
+	if err := qrterm.RenderDataURL(os.Stdout, img, invert); err != nil {
+		logrus.Warnf("无法在终端显示二维码: %v", err)
+		fmt.Println("请在浏览器中打开以下图片地址后扫码：")
+		fmt.Println(img)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), qrcodeTimeout)
+	defer cancel()
+
+	if !action.WaitForLogin(ctx) {
+		return fmt.Errorf("%s 内未完成扫码，二维码已过期，请重新运行", qrcodeTimeout)
+	}
+
+	if err := saveCookies(page, acc.Cookier()); err != nil {
+		return fmt.Errorf("failed to save cookies: %w", err)
+	}
+
+	logrus.Info("登录成功！")
+	return nil
+}
+
+func saveCookies(page *rod.Page, cookieLoader cookies.Cookier) error {
 	cks, err := page.Browser().GetCookies()
 	if err != nil {
 		return err
@@ -74,6 +182,5 @@ func saveCookies(page *rod.Page) error {
 		return err
 	}
 
-	cookieLoader := cookies.NewLoadCookie(cookies.GetCookiesFilePath())
 	return cookieLoader.SaveCookies(data)
 }
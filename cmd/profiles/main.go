@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+const usage = `Chrome profile 管理工具
+
+用法:
+  profiles list [flags]    列出持久化 profile 的大小、最近使用时间和是否正在使用
+  profiles clean [flags]   删除账号的持久化 profile，正在使用的 profile 会跳过
+
+使用 "profiles <command> -h" 查看命令参数。
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "list":
+		runList(os.Args[2:])
+	case "clean":
+		runClean(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// profileFlags list/clean 共用的参数
+type profileFlags struct {
+	root           string
+	accountsConfig string
+}
+
+func (f *profileFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.root, "profile-dir", "", "持久化 Chrome profile 的根目录（也可通过环境变量 XHS_PROFILE_DIR 设置）")
+	fs.StringVar(&f.accountsConfig, "accounts", os.Getenv("XHS_ACCOUNTS_CONFIG"), "多账号配置文件路径（JSON）")
+}
+
+// load 返回 profile 根目录和账号注册表
+func (f *profileFlags) load() (string, *accounts.Registry) {
+	configs.SetProfileDir(f.root)
+	root := configs.GetProfileDir()
+	if root == "" {
+		logrus.Fatal("profile dir is required, use -profile-dir or XHS_PROFILE_DIR")
+	}
+
+	registry, err := accounts.LoadRegistry(f.accountsConfig)
+	if err != nil {
+		logrus.Fatalf("failed to load accounts: %v", err)
+	}
+	return root, registry
+}
+
+func runList(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+
+	var pf profileFlags
+	pf.register(fs)
+	_ = fs.Parse(args)
+
+	root, registry := pf.load()
+
+	list, err := browser.ListProfiles(root)
+	if err != nil {
+		logrus.Fatalf("列出 profile 失败: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSIZE\tLAST USED\tIN USE\tACCOUNT")
+	for _, p := range list {
+		account := "-"
+		if _, err := registry.Get(p.Name); err == nil {
+			account = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%.1fMB\t%s\t%v\t%s\n",
+			p.Name, float64(p.Size)/1024/1024, p.ModTime.Format("2006-01-02 15:04:05"), p.InUse, account)
+	}
+	_ = w.Flush()
+}
+
+func runClean(args []string) {
+	fs := flag.NewFlagSet("clean", flag.ExitOnError)
+
+	var (
+		pf        profileFlags
+		accountID string
+		orphans   bool
+		all       bool
+	)
+	pf.register(fs)
+	fs.StringVar(&accountID, "account", "", "删除指定账号的 profile，下次启动时重新创建")
+	fs.BoolVar(&orphans, "orphans", false, "删除账号配置中已不存在的账号的 profile")
+	fs.BoolVar(&all, "all", false, "删除所有 profile")
+	_ = fs.Parse(args)
+
+	if accountID == "" && !orphans && !all {
+		logrus.Fatal("one of -account, -orphans or -all is required")
+	}
+
+	root, registry := pf.load()
+
+	var dirs []string
+	if accountID != "" {
+		acc, err := registry.Get(accountID)
+		if err != nil {
+			logrus.Fatalf("failed to get account: %v", err)
+		}
+		dirs = append(dirs, acc.ProfileDir())
+	} else {
+		list, err := browser.ListProfiles(root)
+		if err != nil {
+			logrus.Fatalf("列出 profile 失败: %v", err)
+		}
+		for _, p := range list {
+			if _, err := registry.Get(p.Name); err == nil && !all {
+				continue
+			}
+			dirs = append(dirs, p.Dir)
+		}
+	}
+
+	failed := false
+	for _, dir := range dirs {
+		if err := browser.RemoveProfile(dir); err != nil {
+			logrus.Errorf("删除 %s 失败: %v", dir, err)
+			failed = true
+			continue
+		}
+		logrus.Infof("已删除: %s", dir)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
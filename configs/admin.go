@@ -0,0 +1,12 @@
+package configs
+
+var adminToken = ""
+
+// SetAdminToken 设置管理接口（如 cookies 导入导出）的访问令牌，为空表示禁用这些接口。
+func SetAdminToken(token string) {
+	adminToken = token
+}
+
+func GetAdminToken() string {
+	return adminToken
+}
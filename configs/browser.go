@@ -1,9 +1,19 @@
 package configs
 
+import (
+	"os"
+	"time"
+)
+
 var (
 	useHeadless = true
 
-	binPath = ""
+	binPath    = ""
+	browserURL = ""
+	profileDir = ""
+
+	poolSize        = 2
+	poolIdleTimeout = 5 * time.Minute
 )
 
 func InitHeadless(h bool) {
@@ -22,3 +32,45 @@ func SetBinPath(b string) {
 func GetBinPath() string {
 	return binPath
 }
+
+// SetBrowserURL 设置远程浏览器的调试地址，设置后连接该浏览器而不是在本地启动。
+func SetBrowserURL(u string) {
+	browserURL = u
+}
+
+func GetBrowserURL() string {
+	return browserURL
+}
+
+// SetProfileDir 设置持久化 Chrome profile 的根目录，每个账号使用其中以账号 ID 命名的子目录。
+// 为空时使用环境变量 XHS_PROFILE_DIR，再为空时每次启动使用新的临时 profile。
+func SetProfileDir(d string) {
+	profileDir = d
+}
+
+func GetProfileDir() string {
+	if profileDir != "" {
+		return profileDir
+	}
+	return os.Getenv("XHS_PROFILE_DIR")
+}
+
+// SetPoolSize 设置浏览器池中可同时使用的浏览器数量。
+func SetPoolSize(n int) {
+	if n > 0 {
+		poolSize = n
+	}
+}
+
+func GetPoolSize() int {
+	return poolSize
+}
+
+// SetPoolIdleTimeout 设置浏览器空闲多久后被回收，<=0 表示不回收。
+func SetPoolIdleTimeout(d time.Duration) {
+	poolIdleTimeout = d
+}
+
+func GetPoolIdleTimeout() time.Duration {
+	return poolIdleTimeout
+}
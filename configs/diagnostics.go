@@ -0,0 +1,49 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const DiagnosticsDir = "xiaohongshu_diagnostics"
+
+var (
+	diagnosticsDir        = ""
+	diagnosticsRetention  = 72 * time.Hour
+	diagnosticsMaxBundles = 50
+)
+
+// SetDiagnosticsDir 设置操作失败时保存诊断包的目录。
+// 为空时使用环境变量 XHS_DIAGNOSTICS_DIR，再为空时使用系统临时目录下的 xiaohongshu_diagnostics。
+func SetDiagnosticsDir(dir string) {
+	diagnosticsDir = dir
+}
+
+func GetDiagnosticsDir() string {
+	if diagnosticsDir != "" {
+		return diagnosticsDir
+	}
+	if dir := os.Getenv("XHS_DIAGNOSTICS_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), DiagnosticsDir)
+}
+
+// SetDiagnosticsRetention 设置诊断包保留多久，<=0 表示不按时间清理。
+func SetDiagnosticsRetention(d time.Duration) {
+	diagnosticsRetention = d
+}
+
+func GetDiagnosticsRetention() time.Duration {
+	return diagnosticsRetention
+}
+
+// SetDiagnosticsMaxBundles 设置最多保留多少个诊断包，<=0 表示不保存诊断包。
+func SetDiagnosticsMaxBundles(n int) {
+	diagnosticsMaxBundles = n
+}
+
+func GetDiagnosticsMaxBundles() int {
+	return diagnosticsMaxBundles
+}
@@ -0,0 +1,26 @@
+package configs
+
+var (
+	maxConcurrentReads = 2
+	maxQueueDepth      = 10
+)
+
+// SetMaxConcurrentReads 设置每个账号同时执行的读操作上限，写操作始终串行执行。
+func SetMaxConcurrentReads(n int) {
+	if n > 0 {
+		maxConcurrentReads = n
+	}
+}
+
+func GetMaxConcurrentReads() int {
+	return maxConcurrentReads
+}
+
+// SetMaxQueueDepth 设置每个账号最多排队等待的请求数，超过时返回 BUSY，<=0 表示不限制。
+func SetMaxQueueDepth(n int) {
+	maxQueueDepth = n
+}
+
+func GetMaxQueueDepth() int {
+	return maxQueueDepth
+}
@@ -0,0 +1,36 @@
+package configs
+
+import "time"
+
+var (
+	sessionCheckInterval = 30 * time.Minute
+	sessionExpiryWarning = 24 * time.Hour
+	sessionWebhookURL    = ""
+)
+
+// SetSessionCheckInterval 设置会话健康检查的间隔，<=0 表示不在后台检查。
+func SetSessionCheckInterval(d time.Duration) {
+	sessionCheckInterval = d
+}
+
+func GetSessionCheckInterval() time.Duration {
+	return sessionCheckInterval
+}
+
+// SetSessionExpiryWarning 设置会话过期前多久开始提示即将过期。
+func SetSessionExpiryWarning(d time.Duration) {
+	sessionExpiryWarning = d
+}
+
+func GetSessionExpiryWarning() time.Duration {
+	return sessionExpiryWarning
+}
+
+// SetSessionWebhookURL 设置需要重新登录时通知的 webhook 地址。
+func SetSessionWebhookURL(u string) {
+	sessionWebhookURL = u
+}
+
+func GetSessionWebhookURL() string {
+	return sessionWebhookURL
+}
@@ -0,0 +1,88 @@
+package configs
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultSiteURL    = "https://www.xiaohongshu.com"
+	defaultCreatorURL = "https://creator.xiaohongshu.com"
+)
+
+var (
+	siteURL    = ""
+	creatorURL = ""
+)
+
+// SetSiteURL 设置小红书主站地址，用于指向本地替身服务、转发代理或新的域名。
+// 为空时使用环境变量 XHS_SITE_URL，再为空时使用 https://www.xiaohongshu.com。
+func SetSiteURL(u string) error {
+	u, err := normalizeBaseURL(u)
+	if err != nil {
+		return errors.Wrap(err, "invalid site url")
+	}
+	siteURL = u
+	return nil
+}
+
+// GetSiteURL 获取小红书主站地址，不带末尾的斜杠。
+func GetSiteURL() string {
+	return baseURL(siteURL, "XHS_SITE_URL", defaultSiteURL)
+}
+
+// SetCreatorURL 设置创作服务平台地址。
+// 为空时使用环境变量 XHS_CREATOR_URL，再为空时使用 https://creator.xiaohongshu.com。
+func SetCreatorURL(u string) error {
+	u, err := normalizeBaseURL(u)
+	if err != nil {
+		return errors.Wrap(err, "invalid creator url")
+	}
+	creatorURL = u
+	return nil
+}
+
+// GetCreatorURL 获取创作服务平台地址，不带末尾的斜杠。
+func GetCreatorURL() string {
+	return baseURL(creatorURL, "XHS_CREATOR_URL", defaultCreatorURL)
+}
+
+// SiteURL 拼接主站地址，path 以 / 开头，可以包含查询参数。
+func SiteURL(path string) string {
+	return GetSiteURL() + path
+}
+
+// CreatorURL 拼接创作服务平台地址，path 以 / 开头，可以包含查询参数。
+func CreatorURL(path string) string {
+	return GetCreatorURL() + path
+}
+
+func baseURL(configured, env, fallback string) string {
+	if configured != "" {
+		return configured
+	}
+	if u, err := normalizeBaseURL(os.Getenv(env)); err == nil && u != "" {
+		return u
+	}
+	return fallback
+}
+
+// normalizeBaseURL 校验地址必须是 http(s) 绝对地址，并去掉末尾的斜杠
+func normalizeBaseURL(u string) (string, error) {
+	u = strings.TrimRight(strings.TrimSpace(u), "/")
+	if u == "" {
+		return "", nil
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return "", err
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", errors.Errorf("%q is not an absolute http(s) url", u)
+	}
+	return u, nil
+}
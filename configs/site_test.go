@@ -0,0 +1,26 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteURL(t *testing.T) {
+	t.Cleanup(func() {
+		siteURL, creatorURL = "", ""
+	})
+
+	t.Setenv("XHS_SITE_URL", "")
+	require.Equal(t, "https://www.xiaohongshu.com/explore", SiteURL("/explore"))
+	require.Equal(t, "https://creator.xiaohongshu.com/publish/publish", CreatorURL("/publish/publish"))
+
+	t.Setenv("XHS_SITE_URL", "http://127.0.0.1:9000/")
+	require.Equal(t, "http://127.0.0.1:9000/explore", SiteURL("/explore"))
+
+	require.NoError(t, SetSiteURL("https://proxy.example.com/xhs/"))
+	require.Equal(t, "https://proxy.example.com/xhs/explore", SiteURL("/explore"))
+
+	require.Error(t, SetCreatorURL("creator.example.com"))
+	require.Error(t, SetCreatorURL("ftp://creator.example.com"))
+}
@@ -1,5 +0,0 @@
-package configs
-
-const (
-	Username = "xiaohongshu-mcp"
-)
@@ -10,6 +10,35 @@ import (
 type Cookier interface {
 	LoadCookies() ([]byte, error)
 	SaveCookies(data []byte) error
+	// ClearCookies 删除保存的 cookies，不存在时不报错
+	ClearCookies() error
+}
+
+var cookieKey []byte
+
+// SetEncryptionKey 设置 cookies 加密密钥，设置后 NewCookier 返回加密存储
+func SetEncryptionKey(key []byte) error {
+	if len(key) > 0 {
+		if _, err := newAEAD(key); err != nil {
+			return err
+		}
+	}
+
+	cookieKey = key
+	return nil
+}
+
+// NewCookier 根据配置创建 cookies 存储：配置了密钥时使用加密存储，否则使用明文文件。
+func NewCookier(path string) Cookier {
+	if len(cookieKey) == 0 {
+		return NewLoadCookie(path)
+	}
+
+	c, err := NewEncryptedCookie(path, cookieKey)
+	if err != nil {
+		panic(err)
+	}
+	return c
 }
 
 type localCookie struct {
@@ -39,7 +68,23 @@ func (c *localCookie) LoadCookies() ([]byte, error) {
 
 // SaveCookies 保存 cookies 到文件中。
 func (c *localCookie) SaveCookies(data []byte) error {
-	return os.WriteFile(c.path, data, 0644)
+	return writeFileAtomic(c.path, data, 0644)
+}
+
+// ClearCookies 删除 cookies 文件。
+func (c *localCookie) ClearCookies() error {
+	return removeFile(c.path)
+}
+
+// removeFile 删除 cookies 文件，文件不存在视为成功
+func removeFile(path string) error {
+	unlock := lockPath(path)
+	defer unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove cookies file")
+	}
+	return nil
 }
 
 // GetCookiesFilePath 获取 cookies 文件路径。
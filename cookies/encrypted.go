@@ -0,0 +1,200 @@
+package cookies
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// encryptedMagic 加密文件头，用于区分明文 cookies 文件
+var encryptedMagic = []byte("XHSMCP-COOKIE-v1\n")
+
+// encryptedCookie 使用 AES-GCM 加密保存 cookies。
+// 读取到明文的 cookies 文件时会透明地迁移为加密格式。
+type encryptedCookie struct {
+	path string
+	aead cipher.AEAD
+}
+
+// NewEncryptedCookie 创建加密的 cookies 存储，key 长度必须为 16/24/32 字节
+func NewEncryptedCookie(path string, key []byte) (Cookier, error) {
+	if path == "" {
+		return nil, errors.New("path is required")
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedCookie{path: path, aead: aead}, nil
+}
+
+// LoadCookies 读取并解密 cookies，明文文件会被重新加密保存。
+func (c *encryptedCookie) LoadCookies() ([]byte, error) {
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read cookies from file")
+	}
+
+	if !IsEncrypted(raw) {
+		if !json.Valid(raw) {
+			return nil, errors.New("cookies file is neither encrypted nor valid json")
+		}
+
+		logrus.Infof("migrating plaintext cookies file to encrypted format: %s", c.path)
+		if err := c.SaveCookies(raw); err != nil {
+			return nil, errors.Wrap(err, "failed to migrate plaintext cookies")
+		}
+		return raw, nil
+	}
+
+	return c.decrypt(raw)
+}
+
+// SaveCookies 加密后写入文件，文件权限为 0600。
+func (c *encryptedCookie) SaveCookies(data []byte) error {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return errors.Wrap(err, "failed to generate nonce")
+	}
+
+	buf := make([]byte, 0, len(encryptedMagic)+len(nonce)+len(data)+c.aead.Overhead())
+	buf = append(buf, encryptedMagic...)
+	buf = append(buf, nonce...)
+	buf = c.aead.Seal(buf, nonce, data, encryptedMagic)
+
+	return writeFileAtomic(c.path, buf, 0600)
+}
+
+// ClearCookies 删除加密的 cookies 文件。
+func (c *encryptedCookie) ClearCookies() error {
+	return removeFile(c.path)
+}
+
+func (c *encryptedCookie) decrypt(raw []byte) ([]byte, error) {
+	body := raw[len(encryptedMagic):]
+	nonceSize := c.aead.NonceSize()
+	if len(body) < nonceSize {
+		return nil, errors.New("encrypted cookies file is truncated")
+	}
+
+	data, err := c.aead.Open(nil, body[:nonceSize], body[nonceSize:], encryptedMagic)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt cookies, wrong key?")
+	}
+	return data, nil
+}
+
+// IsEncrypted 判断 cookies 文件内容是否为加密格式
+func IsEncrypted(raw []byte) bool {
+	return bytes.HasPrefix(raw, encryptedMagic)
+}
+
+// RotateKey 使用新密钥重新加密 cookies 文件。
+// oldKey 为空表示原文件是明文。
+func RotateKey(path string, oldKey, newKey []byte) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	if len(oldKey) == 0 {
+		data, err = NewLoadCookie(path).LoadCookies()
+	} else {
+		var old Cookier
+		if old, err = NewEncryptedCookie(path, oldKey); err == nil {
+			data, err = old.LoadCookies()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	next, err := NewEncryptedCookie(path, newKey)
+	if err != nil {
+		return err
+	}
+	return next.SaveCookies(data)
+}
+
+// ParseKey 解析密钥：支持 base64 编码的 16/24/32 字节密钥，
+// 其它任意字符串作为口令，通过 SHA-256 派生出 32 字节密钥。
+func ParseKey(s string) []byte {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+
+	if key, err := base64.StdEncoding.DecodeString(s); err == nil {
+		switch len(key) {
+		case 16, 24, 32:
+			return key
+		}
+	}
+
+	sum := sha256.Sum256([]byte(s))
+	return sum[:]
+}
+
+// LoadKey 从环境变量值或密钥文件加载密钥，两者都为空时返回 nil
+func LoadKey(value, file string) ([]byte, error) {
+	if value != "" {
+		return ParseKey(value), nil
+	}
+	if file == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read cookies key file")
+	}
+
+	key := ParseKey(string(data))
+	if key == nil {
+		return nil, errors.Errorf("cookies key file %s is empty", file)
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cookies key")
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免写到一半的文件被读取
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
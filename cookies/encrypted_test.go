@@ -0,0 +1,94 @@
+package cookies
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testCookies = `[{"name":"web_session","value":"abc","domain":".xiaohongshu.com"}]`
+
+func TestEncryptedCookieRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+
+	c, err := NewEncryptedCookie(path, ParseKey("secret"))
+	require.NoError(t, err)
+	require.NoError(t, c.SaveCookies([]byte(testCookies)))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(raw))
+	require.NotContains(t, string(raw), "web_session")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	data, err := c.LoadCookies()
+	require.NoError(t, err)
+	require.JSONEq(t, testCookies, string(data))
+
+	wrong, err := NewEncryptedCookie(path, ParseKey("other"))
+	require.NoError(t, err)
+	_, err = wrong.LoadCookies()
+	require.Error(t, err)
+}
+
+func TestEncryptedCookieMigratesPlaintext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	require.NoError(t, os.WriteFile(path, []byte(testCookies), 0644))
+
+	c, err := NewEncryptedCookie(path, ParseKey("secret"))
+	require.NoError(t, err)
+
+	data, err := c.LoadCookies()
+	require.NoError(t, err)
+	require.JSONEq(t, testCookies, string(data))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(raw))
+}
+
+func TestRotateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	require.NoError(t, os.WriteFile(path, []byte(testCookies), 0644))
+
+	oldKey, newKey := ParseKey("old"), ParseKey("new")
+	require.NoError(t, RotateKey(path, nil, oldKey))
+	require.NoError(t, RotateKey(path, oldKey, newKey))
+
+	c, err := NewEncryptedCookie(path, newKey)
+	require.NoError(t, err)
+	data, err := c.LoadCookies()
+	require.NoError(t, err)
+	require.JSONEq(t, testCookies, string(data))
+
+	require.Error(t, RotateKey(path, oldKey, newKey))
+}
+
+func TestParseKey(t *testing.T) {
+	require.Nil(t, ParseKey("  "))
+	require.Len(t, ParseKey("MDEyMzQ1Njc4OWFiY2RlZg=="), 16)
+	require.Len(t, ParseKey("passphrase"), 32)
+}
+
+func TestClearCookies(t *testing.T) {
+	dir := t.TempDir()
+
+	encrypted, err := NewEncryptedCookie(filepath.Join(dir, "encrypted.json"), ParseKey("secret"))
+	require.NoError(t, err)
+
+	for _, c := range []Cookier{NewLoadCookie(filepath.Join(dir, "plain.json")), encrypted} {
+		require.NoError(t, c.SaveCookies([]byte(testCookies)))
+		require.NoError(t, c.ClearCookies())
+
+		_, err := c.LoadCookies()
+		require.Error(t, err)
+
+		// 重复清除不报错
+		require.NoError(t, c.ClearCookies())
+	}
+}
@@ -0,0 +1,47 @@
+package cookies
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// SessionCookieNames 决定登录会话是否有效的 cookies
+var SessionCookieNames = []string{"web_session"}
+
+// SessionExpiry 解析 cookies，返回登录会话 cookies 中最早的过期时间。
+// found 为 false 表示没有会话 cookies；返回零值时间表示会话 cookie 没有过期时间（浏览器会话级）。
+func SessionExpiry(data []byte) (expiresAt time.Time, found bool, err error) {
+	var cks []*proto.NetworkCookie
+	if err := json.Unmarshal(data, &cks); err != nil {
+		return time.Time{}, false, errors.Wrap(err, "parse cookies")
+	}
+
+	for _, ck := range cks {
+		if !isSessionCookie(ck.Name) {
+			continue
+		}
+		found = true
+
+		if ck.Expires <= 0 {
+			continue
+		}
+		t := ck.Expires.Time()
+		if expiresAt.IsZero() || t.Before(expiresAt) {
+			expiresAt = t
+		}
+	}
+
+	return expiresAt, found, nil
+}
+
+func isSessionCookie(name string) bool {
+	for _, n := range SessionCookieNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
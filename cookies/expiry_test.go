@@ -0,0 +1,32 @@
+package cookies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionExpiry(t *testing.T) {
+	data := []byte(`[
+		{"name":"a1","value":"x","expires":1700000000},
+		{"name":"web_session","value":"y","expires":1800000000}
+	]`)
+
+	expiresAt, found, err := SessionExpiry(data)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, time.Unix(1800000000, 0).Unix(), expiresAt.Unix())
+
+	_, found, err = SessionExpiry([]byte(`[{"name":"a1","value":"x","expires":1700000000}]`))
+	require.NoError(t, err)
+	require.False(t, found)
+
+	expiresAt, found, err = SessionExpiry([]byte(`[{"name":"web_session","value":"y","expires":-1}]`))
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, expiresAt.IsZero())
+
+	_, _, err = SessionExpiry([]byte(`not json`))
+	require.Error(t, err)
+}
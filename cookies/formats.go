@@ -0,0 +1,352 @@
+package cookies
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// Format cookies 导入导出格式
+type Format string
+
+const (
+	FormatNetscape       Format = "netscape"       // curl/wget 使用的 cookies.txt
+	FormatEditThisCookie Format = "editthiscookie" // EditThisCookie / Cookie-Editor 浏览器扩展导出的 JSON
+	FormatRod            Format = "rod"            // rod 的 []proto.NetworkCookie JSON，即服务保存的格式
+)
+
+// Domain 只导入导出该域名及其子域名的 cookies
+const Domain = "xiaohongshu.com"
+
+// netscapeHTTPOnlyPrefix Netscape 格式中 HttpOnly cookie 的行前缀
+const netscapeHTTPOnlyPrefix = "#HttpOnly_"
+
+// ParseFormat 解析格式名称，为空时返回空格式表示自动识别
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return "", nil
+	case "netscape", "txt", "cookies.txt":
+		return FormatNetscape, nil
+	case "editthiscookie", "cookie-editor", "cookieeditor":
+		return FormatEditThisCookie, nil
+	case "rod", "json":
+		return FormatRod, nil
+	default:
+		return "", errors.Errorf("unsupported cookies format: %s", s)
+	}
+}
+
+// DetectFormat 根据内容识别 cookies 格式
+func DetectFormat(data []byte) Format {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return FormatNetscape
+	}
+
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &items); err != nil {
+		return FormatNetscape
+	}
+	for _, item := range items {
+		_, hasExpirationDate := item["expirationDate"]
+		_, hasHostOnly := item["hostOnly"]
+		if hasExpirationDate || hasHostOnly {
+			return FormatEditThisCookie
+		}
+	}
+	return FormatRod
+}
+
+// IsXiaohongshuDomain 判断 cookie 域名是否属于小红书
+func IsXiaohongshuDomain(domain string) bool {
+	domain = strings.TrimPrefix(strings.ToLower(domain), ".")
+	return domain == Domain || strings.HasSuffix(domain, "."+Domain)
+}
+
+// FilterDomain 只保留小红书域名下的 cookies
+func FilterDomain(cks []*proto.NetworkCookie) []*proto.NetworkCookie {
+	var result []*proto.NetworkCookie
+	for _, ck := range cks {
+		if IsXiaohongshuDomain(ck.Domain) {
+			result = append(result, ck)
+		}
+	}
+	return result
+}
+
+// Import 解析指定格式的 cookies，format 为空时自动识别。只返回小红书域名下的 cookies。
+func Import(data []byte, format Format) ([]*proto.NetworkCookie, error) {
+	if format == "" {
+		format = DetectFormat(data)
+	}
+
+	var (
+		cks []*proto.NetworkCookie
+		err error
+	)
+	switch format {
+	case FormatNetscape:
+		cks, err = parseNetscape(data)
+	case FormatEditThisCookie:
+		cks, err = parseEditThisCookie(data)
+	case FormatRod:
+		err = json.Unmarshal(data, &cks)
+	default:
+		err = errors.Errorf("unsupported cookies format: %s", format)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %s cookies failed", format)
+	}
+
+	cks = FilterDomain(cks)
+	if len(cks) == 0 {
+		return nil, errors.Errorf("no cookies for %s found", Domain)
+	}
+	return cks, nil
+}
+
+// Export 将 cookies 转换为指定格式，只导出小红书域名下的 cookies
+func Export(cks []*proto.NetworkCookie, format Format) ([]byte, error) {
+	cks = FilterDomain(cks)
+
+	switch format {
+	case FormatNetscape:
+		return formatNetscape(cks), nil
+	case FormatEditThisCookie:
+		return formatEditThisCookie(cks)
+	case FormatRod, "":
+		return json.MarshalIndent(cks, "", "  ")
+	default:
+		return nil, errors.Errorf("unsupported cookies format: %s", format)
+	}
+}
+
+// ImportTo 解析 cookies 并保存到存储，返回导入的 cookies
+func ImportTo(c Cookier, data []byte, format Format) ([]*proto.NetworkCookie, error) {
+	cks, err := Import(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(cks)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.SaveCookies(raw); err != nil {
+		return nil, err
+	}
+	return cks, nil
+}
+
+// ExportFrom 读取存储中的 cookies 并转换为指定格式
+func ExportFrom(c Cookier, format Format) ([]byte, error) {
+	raw, err := c.LoadCookies()
+	if err != nil {
+		return nil, err
+	}
+
+	var cks []*proto.NetworkCookie
+	if err := json.Unmarshal(raw, &cks); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal stored cookies")
+	}
+	return Export(cks, format)
+}
+
+func parseNetscape(data []byte) ([]*proto.NetworkCookie, error) {
+	var cks []*proto.NetworkCookie
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := strings.HasPrefix(line, netscapeHTTPOnlyPrefix)
+		if httpOnly {
+			line = strings.TrimPrefix(line, netscapeHTTPOnlyPrefix)
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, errors.Errorf("line %d: expect 7 tab separated fields, got %d", n, len(fields))
+		}
+
+		expires, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return nil, errors.Errorf("line %d: invalid expiry %q", n, fields[4])
+		}
+
+		domain := fields[0]
+		// 第二列表示是否包含子域名，对应以点开头的域名
+		if strings.EqualFold(fields[1], "TRUE") && !strings.HasPrefix(domain, ".") {
+			domain = "." + domain
+		}
+
+		cks = append(cks, newNetworkCookie(fields[5], fields[6], domain, fields[2],
+			expires, httpOnly, strings.EqualFold(fields[3], "TRUE"), ""))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cks, nil
+}
+
+func formatNetscape(cks []*proto.NetworkCookie) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Netscape HTTP Cookie File\n")
+	buf.WriteString("# Exported by xiaohongshu-mcp\n\n")
+
+	for _, ck := range cks {
+		prefix := ""
+		if ck.HTTPOnly {
+			prefix = netscapeHTTPOnlyPrefix
+		}
+
+		expires := int64(0)
+		if !ck.Session && ck.Expires > 0 {
+			expires = int64(ck.Expires)
+		}
+
+		fmt.Fprintf(&buf, "%s%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			prefix, ck.Domain, netscapeBool(strings.HasPrefix(ck.Domain, ".")),
+			ck.Path, netscapeBool(ck.Secure), expires, ck.Name, ck.Value)
+	}
+
+	return buf.Bytes()
+}
+
+func netscapeBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// editThisCookie EditThisCookie / Cookie-Editor 扩展的 cookie 格式
+type editThisCookie struct {
+	Domain         string   `json:"domain"`
+	ExpirationDate *float64 `json:"expirationDate,omitempty"`
+	HostOnly       bool     `json:"hostOnly"`
+	HTTPOnly       bool     `json:"httpOnly"`
+	Name           string   `json:"name"`
+	Path           string   `json:"path"`
+	SameSite       string   `json:"sameSite,omitempty"`
+	Secure         bool     `json:"secure"`
+	Session        bool     `json:"session"`
+	StoreID        string   `json:"storeId,omitempty"`
+	Value          string   `json:"value"`
+}
+
+func parseEditThisCookie(data []byte) ([]*proto.NetworkCookie, error) {
+	var items []editThisCookie
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	cks := make([]*proto.NetworkCookie, 0, len(items))
+	for _, item := range items {
+		expires := float64(0)
+		if item.ExpirationDate != nil && !item.Session {
+			expires = *item.ExpirationDate
+		}
+
+		domain := item.Domain
+		if !item.HostOnly && !strings.HasPrefix(domain, ".") {
+			domain = "." + domain
+		}
+
+		cks = append(cks, newNetworkCookie(item.Name, item.Value, domain, item.Path,
+			expires, item.HTTPOnly, item.Secure, sameSiteFromExtension(item.SameSite)))
+	}
+
+	return cks, nil
+}
+
+func formatEditThisCookie(cks []*proto.NetworkCookie) ([]byte, error) {
+	items := make([]editThisCookie, 0, len(cks))
+	for _, ck := range cks {
+		item := editThisCookie{
+			Domain:   ck.Domain,
+			HostOnly: !strings.HasPrefix(ck.Domain, "."),
+			HTTPOnly: ck.HTTPOnly,
+			Name:     ck.Name,
+			Path:     ck.Path,
+			SameSite: sameSiteToExtension(ck.SameSite),
+			Secure:   ck.Secure,
+			Session:  ck.Session || ck.Expires <= 0,
+			StoreID:  "0",
+			Value:    ck.Value,
+		}
+		if !item.Session {
+			expires := float64(ck.Expires)
+			item.ExpirationDate = &expires
+		}
+		items = append(items, item)
+	}
+
+	return json.MarshalIndent(items, "", "  ")
+}
+
+// newNetworkCookie expires 为 0 表示会话 cookie
+func newNetworkCookie(name, value, domain, path string, expires float64, httpOnly, secure bool, sameSite proto.NetworkCookieSameSite) *proto.NetworkCookie {
+	if path == "" {
+		path = "/"
+	}
+
+	ck := &proto.NetworkCookie{
+		Name:     name,
+		Value:    value,
+		Domain:   domain,
+		Path:     path,
+		Size:     len(name) + len(value),
+		HTTPOnly: httpOnly,
+		Secure:   secure,
+		SameSite: sameSite,
+		Priority: proto.NetworkCookiePriorityMedium,
+	}
+
+	if expires <= 0 {
+		ck.Session = true
+		ck.Expires = -1
+	} else {
+		ck.Expires = proto.TimeSinceEpoch(expires)
+	}
+
+	return ck
+}
+
+func sameSiteFromExtension(s string) proto.NetworkCookieSameSite {
+	switch strings.ToLower(s) {
+	case "strict":
+		return proto.NetworkCookieSameSiteStrict
+	case "lax":
+		return proto.NetworkCookieSameSiteLax
+	case "no_restriction", "none":
+		return proto.NetworkCookieSameSiteNone
+	default:
+		return ""
+	}
+}
+
+func sameSiteToExtension(s proto.NetworkCookieSameSite) string {
+	switch s {
+	case proto.NetworkCookieSameSiteStrict:
+		return "strict"
+	case proto.NetworkCookieSameSiteLax:
+		return "lax"
+	case proto.NetworkCookieSameSiteNone:
+		return "no_restriction"
+	default:
+		return "unspecified"
+	}
+}
@@ -0,0 +1,103 @@
+package cookies
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/require"
+)
+
+const netscapeCookies = `# Netscape HTTP Cookie File
+
+.xiaohongshu.com	TRUE	/	FALSE	1893456000	a1	abc
+#HttpOnly_.xiaohongshu.com	TRUE	/	TRUE	1893456000	web_session	sess
+edith.xiaohongshu.com	FALSE	/	FALSE	0	temp	1
+.example.com	TRUE	/	FALSE	1893456000	other	x
+`
+
+const editThisCookieJSON = `[
+  {"domain": ".xiaohongshu.com", "expirationDate": 1893456000.5, "hostOnly": false, "httpOnly": true,
+   "name": "web_session", "path": "/", "sameSite": "lax", "secure": true, "session": false, "storeId": "0", "value": "sess"},
+  {"domain": "www.xiaohongshu.com", "hostOnly": true, "httpOnly": false,
+   "name": "temp", "path": "/", "sameSite": "unspecified", "secure": false, "session": true, "value": "1"},
+  {"domain": ".example.com", "expirationDate": 1893456000, "hostOnly": false, "name": "other", "path": "/", "value": "x"}
+]`
+
+func findCookie(t *testing.T, cks []*proto.NetworkCookie, name string) *proto.NetworkCookie {
+	for _, ck := range cks {
+		if ck.Name == name {
+			return ck
+		}
+	}
+	t.Fatalf("cookie %s not found", name)
+	return nil
+}
+
+func TestDetectFormat(t *testing.T) {
+	require.Equal(t, FormatNetscape, DetectFormat([]byte(netscapeCookies)))
+	require.Equal(t, FormatEditThisCookie, DetectFormat([]byte(editThisCookieJSON)))
+	require.Equal(t, FormatRod, DetectFormat([]byte(testCookies)))
+}
+
+func TestImportNetscape(t *testing.T) {
+	cks, err := Import([]byte(netscapeCookies), "")
+	require.NoError(t, err)
+	require.Len(t, cks, 3)
+
+	session := findCookie(t, cks, "web_session")
+	require.True(t, session.HTTPOnly)
+	require.True(t, session.Secure)
+	require.Equal(t, ".xiaohongshu.com", session.Domain)
+	require.Equal(t, proto.TimeSinceEpoch(1893456000), session.Expires)
+
+	temp := findCookie(t, cks, "temp")
+	require.True(t, temp.Session)
+	require.Equal(t, "edith.xiaohongshu.com", temp.Domain)
+}
+
+func TestImportEditThisCookie(t *testing.T) {
+	cks, err := Import([]byte(editThisCookieJSON), FormatEditThisCookie)
+	require.NoError(t, err)
+	require.Len(t, cks, 2)
+
+	session := findCookie(t, cks, "web_session")
+	require.Equal(t, proto.NetworkCookieSameSiteLax, session.SameSite)
+	require.Equal(t, proto.TimeSinceEpoch(1893456000.5), session.Expires)
+	require.False(t, session.Session)
+
+	temp := findCookie(t, cks, "temp")
+	require.True(t, temp.Session)
+	require.Equal(t, "www.xiaohongshu.com", temp.Domain)
+}
+
+func TestImportNoXiaohongshuCookies(t *testing.T) {
+	_, err := Import([]byte(".example.com\tTRUE\t/\tFALSE\t0\tother\tx\n"), FormatNetscape)
+	require.Error(t, err)
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	cks, err := Import([]byte(netscapeCookies), FormatNetscape)
+	require.NoError(t, err)
+
+	for _, format := range []Format{FormatNetscape, FormatEditThisCookie, FormatRod} {
+		data, err := Export(cks, format)
+		require.NoError(t, err)
+
+		got, err := Import(data, "")
+		require.NoError(t, err, format)
+		require.Len(t, got, len(cks), format)
+		require.False(t, Changed(cks, got), format)
+	}
+}
+
+func TestImportToAndExportFrom(t *testing.T) {
+	c := NewLoadCookie(filepath.Join(t.TempDir(), "cookies.json"))
+
+	_, err := ImportTo(c, []byte(editThisCookieJSON), "")
+	require.NoError(t, err)
+
+	data, err := ExportFrom(c, FormatNetscape)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "#HttpOnly_.xiaohongshu.com\tTRUE\t/\tTRUE\t1893456000\tweb_session\tsess")
+}
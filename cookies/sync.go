@@ -0,0 +1,90 @@
+package cookies
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// pathLocks 每个 cookies 文件一把锁，保证同一账号的并发写回按顺序进行
+var pathLocks sync.Map
+
+func lockPath(path string) func() {
+	v, _ := pathLocks.LoadOrStore(path, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// pather 可以返回 cookies 文件路径的存储
+type pather interface {
+	cookiePath() string
+}
+
+func (c *localCookie) cookiePath() string     { return c.path }
+func (c *encryptedCookie) cookiePath() string { return c.path }
+
+// Changed 判断浏览器中的 cookies 相对已保存的 cookies 是否发生变化。
+// 以 name+domain+path 为键，比较值和过期时间。
+func Changed(stored, current []*proto.NetworkCookie) bool {
+	if len(stored) != len(current) {
+		return true
+	}
+
+	index := make(map[string]*proto.NetworkCookie, len(stored))
+	for _, ck := range stored {
+		index[identity(ck)] = ck
+	}
+
+	for _, ck := range current {
+		old, ok := index[identity(ck)]
+		if !ok {
+			return true
+		}
+		if old.Value != ck.Value || old.Expires != ck.Expires {
+			return true
+		}
+	}
+
+	return false
+}
+
+func identity(ck *proto.NetworkCookie) string {
+	return ck.Name + "|" + ck.Domain + "|" + ck.Path
+}
+
+// Sync 将浏览器中的最新 cookies 写回存储，只有发生变化时才写入。
+// 同一文件的 Sync 调用会串行执行，返回是否写入。
+func Sync(c Cookier, current []*proto.NetworkCookie) (bool, error) {
+	if len(current) == 0 {
+		// 浏览器中没有 cookies 通常意味着读取失败，不能覆盖已有会话
+		return false, nil
+	}
+
+	if p, ok := c.(pather); ok {
+		defer lockPath(p.cookiePath())()
+	}
+
+	var stored []*proto.NetworkCookie
+	if data, err := c.LoadCookies(); err == nil {
+		if err := json.Unmarshal(data, &stored); err != nil {
+			stored = nil
+		}
+	}
+
+	if !Changed(stored, current) {
+		return false, nil
+	}
+
+	data, err := json.Marshal(current)
+	if err != nil {
+		return false, errors.Wrap(err, "marshal cookies")
+	}
+	if err := c.SaveCookies(data); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,81 @@
+package cookies
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func newCookie(name, value string, expires float64) *proto.NetworkCookie {
+	return &proto.NetworkCookie{
+		Name:    name,
+		Value:   value,
+		Domain:  ".xiaohongshu.com",
+		Path:    "/",
+		Expires: proto.TimeSinceEpoch(expires),
+	}
+}
+
+func TestChanged(t *testing.T) {
+	stored := []*proto.NetworkCookie{newCookie("a", "1", 100), newCookie("b", "2", 100)}
+
+	require.False(t, Changed(stored, []*proto.NetworkCookie{newCookie("b", "2", 100), newCookie("a", "1", 100)}))
+	require.True(t, Changed(stored, []*proto.NetworkCookie{newCookie("a", "1", 100)}))
+	require.True(t, Changed(stored, []*proto.NetworkCookie{newCookie("a", "1", 100), newCookie("b", "3", 100)}))
+	require.True(t, Changed(stored, []*proto.NetworkCookie{newCookie("a", "1", 200), newCookie("b", "2", 100)}))
+	require.True(t, Changed(stored, []*proto.NetworkCookie{newCookie("a", "1", 100), newCookie("c", "2", 100)}))
+}
+
+func TestSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	c := NewLoadCookie(path)
+
+	current := []*proto.NetworkCookie{newCookie("web_session", "v1", 100)}
+
+	written, err := Sync(c, current)
+	require.NoError(t, err)
+	require.True(t, written)
+
+	written, err = Sync(c, current)
+	require.NoError(t, err)
+	require.False(t, written)
+
+	written, err = Sync(c, nil)
+	require.NoError(t, err)
+	require.False(t, written)
+
+	current = []*proto.NetworkCookie{newCookie("web_session", "v2", 200)}
+	written, err = Sync(c, current)
+	require.NoError(t, err)
+	require.True(t, written)
+
+	data, err := c.LoadCookies()
+	require.NoError(t, err)
+	var stored []*proto.NetworkCookie
+	require.NoError(t, json.Unmarshal(data, &stored))
+	require.Equal(t, "v2", stored[0].Value)
+}
+
+func TestSyncConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	c := NewLoadCookie(path)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := Sync(c, []*proto.NetworkCookie{newCookie("web_session", "v", float64(i))})
+			require.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := c.LoadCookies()
+	require.NoError(t, err)
+	require.True(t, json.Valid(data))
+}
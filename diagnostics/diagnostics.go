@@ -0,0 +1,310 @@
+// Package diagnostics 在浏览器操作失败时保存页面现场（截图、HTML、URL 和 __INITIAL_STATE__），
+// 用于排查选择器失效、页面改版、风控拦截等问题。
+//
+// 每个诊断包是目录下的一个子目录，目录名即诊断包 ID：
+//
+//	<dir>/20261017-212626-a1b2c3/
+//	    meta.json       元数据：账号、失败步骤、错误信息、URL、时间
+//	    screenshot.png  整页截图
+//	    page.html       页面 HTML
+//	    state.json      window.__INITIAL_STATE__
+package diagnostics
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	metaFile       = "meta.json"
+	screenshotFile = "screenshot.png"
+	htmlFile       = "page.html"
+	stateFile      = "state.json"
+
+	// captureTimeout 采集现场的超时时间，操作本身的 ctx 可能已经超时
+	captureTimeout = 15 * time.Second
+)
+
+// ErrNotFound 诊断包不存在
+var ErrNotFound = errors.New("diagnostics bundle not found")
+
+var idPattern = regexp.MustCompile(`^\d{8}-\d{6}-[0-9a-f]{6}$`)
+
+// Bundle 诊断包的元数据
+type Bundle struct {
+	ID        string    `json:"id"`
+	Account   string    `json:"account"`
+	Step      string    `json:"step,omitempty"`
+	Error     string    `json:"error"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+	Files     []string  `json:"files"`
+}
+
+// Snapshot 页面现场，采集失败的部分为空
+type Snapshot struct {
+	URL        string
+	HTML       string
+	State      string
+	Screenshot []byte
+}
+
+// Capture 采集页面现场。页面可能已经处于异常状态，每一部分单独采集，失败时跳过。
+func Capture(page *rod.Page) *Snapshot {
+	pp := page.Timeout(captureTimeout)
+	snap := &Snapshot{}
+
+	if info, err := pp.Info(); err != nil {
+		logrus.Warnf("diagnostics: read page url failed: %v", err)
+	} else {
+		snap.URL = info.URL
+	}
+
+	if html, err := pp.HTML(); err != nil {
+		logrus.Warnf("diagnostics: read page html failed: %v", err)
+	} else {
+		snap.HTML = html
+	}
+
+	if res, err := pp.Eval(`() => {
+		if (window.__INITIAL_STATE__ === undefined) {
+			return "";
+		}
+		const seen = new WeakSet();
+		try {
+			return JSON.stringify(window.__INITIAL_STATE__, (key, value) => {
+				if (typeof value === "object" && value !== null) {
+					if (seen.has(value)) {
+						return undefined;
+					}
+					seen.add(value);
+				}
+				return value;
+			}, 2);
+		} catch (e) {
+			return JSON.stringify({ error: String(e) });
+		}
+	}`); err != nil {
+		logrus.Warnf("diagnostics: read __INITIAL_STATE__ failed: %v", err)
+	} else {
+		snap.State = res.Value.String()
+	}
+
+	if img, err := pp.Screenshot(true, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+	}); err != nil {
+		logrus.Warnf("diagnostics: take screenshot failed: %v", err)
+	} else {
+		snap.Screenshot = img
+	}
+
+	return snap
+}
+
+// Store 诊断包存储，按保留时长和数量上限清理旧的诊断包
+type Store struct {
+	dir        string
+	retention  time.Duration
+	maxBundles int
+
+	mu sync.Mutex
+}
+
+// NewStore 创建诊断包存储。maxBundles<=0 时不保存诊断包，返回 nil。
+func NewStore(dir string, retention time.Duration, maxBundles int) *Store {
+	if maxBundles <= 0 {
+		return nil
+	}
+	return &Store{dir: dir, retention: retention, maxBundles: maxBundles}
+}
+
+// Save 保存诊断包，返回填好 ID、URL 和文件列表的元数据
+func (s *Store) Save(bundle Bundle, snap *Snapshot) (*Bundle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(s.dir, id)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "create diagnostics dir failed")
+	}
+
+	bundle.ID = id
+	bundle.URL = snap.URL
+	if bundle.CreatedAt.IsZero() {
+		bundle.CreatedAt = time.Now()
+	}
+	bundle.Files = nil
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{screenshotFile, snap.Screenshot},
+		{htmlFile, []byte(snap.HTML)},
+		{stateFile, []byte(snap.State)},
+	}
+	for _, f := range files {
+		if len(f.data) == 0 {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, f.name), f.data, 0600); err != nil {
+			return nil, errors.Wrapf(err, "write %s failed", f.name)
+		}
+		bundle.Files = append(bundle.Files, f.name)
+	}
+
+	meta, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, metaFile), meta, 0600); err != nil {
+		return nil, errors.Wrap(err, "write meta failed")
+	}
+
+	if err := s.cleanup(time.Now()); err != nil {
+		logrus.Warnf("diagnostics: cleanup failed: %v", err)
+	}
+
+	return &bundle, nil
+}
+
+// List 按时间倒序列出诊断包
+func (s *Store) List() ([]Bundle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.list()
+}
+
+// Get 读取诊断包元数据
+func (s *Store) Get(id string) (*Bundle, error) {
+	if !idPattern.MatchString(id) {
+		return nil, ErrNotFound
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, id, metaFile))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, errors.Wrap(err, "unmarshal meta failed")
+	}
+	return &bundle, nil
+}
+
+// WriteZip 将诊断包打包为 zip 写入 w
+func (s *Store) WriteZip(w io.Writer, id string) error {
+	bundle, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, name := range append([]string{metaFile}, bundle.Files...) {
+		if err := addFile(zw, filepath.Join(s.dir, id, name), name); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func addFile(zw *zip.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// Cleanup 删除超过保留时长和数量上限的诊断包
+func (s *Store) Cleanup() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cleanup(time.Now())
+}
+
+func (s *Store) cleanup(now time.Time) error {
+	bundles, err := s.list()
+	if err != nil {
+		return err
+	}
+
+	for i, b := range bundles {
+		expired := s.retention > 0 && now.Sub(b.CreatedAt) > s.retention
+		if i < s.maxBundles && !expired {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.dir, b.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) list() ([]Bundle, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var bundles []Bundle
+	for _, e := range entries {
+		if !e.IsDir() || !idPattern.MatchString(e.Name()) {
+			continue
+		}
+		b, err := s.Get(e.Name())
+		if err != nil {
+			logrus.Warnf("diagnostics: skip bundle %s: %v", e.Name(), err)
+			continue
+		}
+		bundles = append(bundles, *b)
+	}
+
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].CreatedAt.After(bundles[j].CreatedAt)
+	})
+	return bundles, nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(b), nil
+}
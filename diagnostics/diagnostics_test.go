@@ -0,0 +1,85 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu/mocksite"
+)
+
+func TestStoreSaveAndDownload(t *testing.T) {
+	store := NewStore(t.TempDir(), time.Hour, 10)
+
+	bundle, err := store.Save(Bundle{Account: "default", Step: "publish_image/click_publish", Error: "element not found"}, &Snapshot{
+		URL:        "https://creator.xiaohongshu.com/publish/publish",
+		HTML:       "<html></html>",
+		Screenshot: []byte("png"),
+	})
+	require.NoError(t, err)
+	require.Regexp(t, idPattern, bundle.ID)
+	require.Equal(t, []string{screenshotFile, htmlFile}, bundle.Files)
+
+	got, err := store.Get(bundle.ID)
+	require.NoError(t, err)
+	require.Equal(t, "publish_image/click_publish", got.Step)
+	require.Equal(t, "https://creator.xiaohongshu.com/publish/publish", got.URL)
+
+	var buf bytes.Buffer
+	require.NoError(t, store.WriteZip(&buf, bundle.ID))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{metaFile, screenshotFile, htmlFile}, names)
+
+	_, err = store.Get("../../etc")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, store.WriteZip(&buf, "20260101-000000-000000"), ErrNotFound)
+}
+
+func TestStoreCleanup(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, 24*time.Hour, 2)
+	require.Nil(t, NewStore(dir, 24*time.Hour, 0))
+
+	now := time.Now()
+	old, err := store.Save(Bundle{CreatedAt: now.Add(-48 * time.Hour)}, &Snapshot{})
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, old.ID))
+	require.True(t, os.IsNotExist(err), "bundle older than retention should be removed")
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		b, err := store.Save(Bundle{CreatedAt: now.Add(time.Duration(i) * time.Second)}, &Snapshot{})
+		require.NoError(t, err)
+		ids = append(ids, b.ID)
+	}
+
+	bundles, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, bundles, 2)
+	require.Equal(t, ids[2], bundles[0].ID)
+	require.Equal(t, ids[1], bundles[1].ID)
+}
+
+func TestCaptureWithMockSite(t *testing.T) {
+	site, b := mocksite.NewBrowser(t)
+	page := b.NewPage()
+	defer page.Close()
+
+	page.MustNavigate(site.URL() + "/explore").MustWaitLoad()
+
+	snap := Capture(page)
+	require.Equal(t, site.URL()+"/explore", snap.URL)
+	require.Contains(t, snap.HTML, "__INITIAL_STATE__")
+	require.Contains(t, snap.State, "homefeed_recommend")
+	require.NotEmpty(t, snap.Screenshot)
+}
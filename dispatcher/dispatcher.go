@@ -0,0 +1,186 @@
+// Package dispatcher 按账号调度浏览器操作。
+//
+// 同一账号的写操作（发布、评论、点赞、收藏、退出登录）串行执行，
+// 读操作（浏览、搜索、查看详情等）限制并发数量，排队的请求超过上限时直接拒绝，
+// 避免并发调用同时驱动多个浏览器操作同一账号。
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+// Kind 操作类型
+type Kind int
+
+const (
+	Read  Kind = iota // 只读操作，可以有限并发
+	Write             // 写操作，同一账号串行执行
+)
+
+func (k Kind) String() string {
+	if k == Write {
+		return "write"
+	}
+	return "read"
+}
+
+// Config 调度配置
+type Config struct {
+	MaxReads int // 每个账号同时执行的读操作上限
+	MaxQueue int // 每个账号排队等待的请求上限，<=0 表示不限制
+}
+
+// KindStats 一类操作的排队状态，等待时间单位为毫秒
+type KindStats struct {
+	Limit      int   `json:"limit"`
+	Running    int   `json:"running"`
+	Queued     int   `json:"queued"`
+	Served     int64 `json:"served"`
+	Rejected   int64 `json:"rejected"`
+	LastWaitMs int64 `json:"last_wait_ms"`
+	AvgWaitMs  int64 `json:"avg_wait_ms"`
+	MaxWaitMs  int64 `json:"max_wait_ms"`
+}
+
+// Stats 一个账号的排队状态
+type Stats struct {
+	Account  string    `json:"account"`
+	MaxQueue int       `json:"max_queue"`
+	Read     KindStats `json:"read"`
+	Write    KindStats `json:"write"`
+}
+
+// Dispatcher 按账号调度操作
+type Dispatcher struct {
+	cfg Config
+
+	mu    sync.Mutex
+	lanes map[string]*lane
+}
+
+type lane struct {
+	slots [2]chan struct{} // 按 Kind 索引
+
+	queued    [2]int
+	running   [2]int
+	served    [2]int64
+	rejected  [2]int64
+	totalWait [2]time.Duration
+	lastWait  [2]time.Duration
+	maxWait   [2]time.Duration
+}
+
+// New 创建调度器
+func New(cfg Config) *Dispatcher {
+	if cfg.MaxReads <= 0 {
+		cfg.MaxReads = 1
+	}
+	return &Dispatcher{
+		cfg:   cfg,
+		lanes: make(map[string]*lane),
+	}
+}
+
+func (d *Dispatcher) lane(account string) *lane {
+	l, ok := d.lanes[account]
+	if !ok {
+		l = &lane{}
+		l.slots[Read] = make(chan struct{}, d.cfg.MaxReads)
+		l.slots[Write] = make(chan struct{}, 1)
+		d.lanes[account] = l
+	}
+	return l
+}
+
+// Do 在账号的队列中执行 fn。
+// 没有空闲名额时排队等待直到 ctx 结束；排队的请求已达上限时返回 BUSY 错误。
+func (d *Dispatcher) Do(ctx context.Context, account string, kind Kind, fn func() error) error {
+	start := time.Now()
+
+	d.mu.Lock()
+	l := d.lane(account)
+	slot := l.slots[kind]
+
+	select {
+	case slot <- struct{}{}:
+	default:
+		if d.cfg.MaxQueue > 0 && l.queued[Read]+l.queued[Write] >= d.cfg.MaxQueue {
+			l.rejected[kind]++
+			d.mu.Unlock()
+			return myerrors.New(myerrors.CodeBusy,
+				fmt.Sprintf("账号 %s 排队的请求已达上限 %d，请稍后再试", account, d.cfg.MaxQueue))
+		}
+
+		l.queued[kind]++
+		d.mu.Unlock()
+
+		var err error
+		select {
+		case slot <- struct{}{}:
+		case <-ctx.Done():
+			err = errors.Wrapf(ctx.Err(), "wait for %s queue", kind)
+		}
+
+		d.mu.Lock()
+		l.queued[kind]--
+		if err != nil {
+			d.mu.Unlock()
+			return err
+		}
+	}
+
+	wait := time.Since(start)
+	l.running[kind]++
+	l.served[kind]++
+	l.totalWait[kind] += wait
+	l.lastWait[kind] = wait
+	if wait > l.maxWait[kind] {
+		l.maxWait[kind] = wait
+	}
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		l.running[kind]--
+		d.mu.Unlock()
+		<-slot
+	}()
+
+	return fn()
+}
+
+// Stats 返回账号的排队状态
+func (d *Dispatcher) Stats(account string) Stats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	l := d.lane(account)
+	return Stats{
+		Account:  account,
+		MaxQueue: d.cfg.MaxQueue,
+		Read:     l.stats(Read),
+		Write:    l.stats(Write),
+	}
+}
+
+func (l *lane) stats(kind Kind) KindStats {
+	s := KindStats{
+		Limit:      cap(l.slots[kind]),
+		Running:    l.running[kind],
+		Queued:     l.queued[kind],
+		Served:     l.served[kind],
+		Rejected:   l.rejected[kind],
+		LastWaitMs: l.lastWait[kind].Milliseconds(),
+		MaxWaitMs:  l.maxWait[kind].Milliseconds(),
+	}
+	if l.served[kind] > 0 {
+		s.AvgWaitMs = (l.totalWait[kind] / time.Duration(l.served[kind])).Milliseconds()
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package dispatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+// hold 在队列中占住一个名额，直到 release 被关闭
+func hold(t *testing.T, d *Dispatcher, kind Kind, release chan struct{}) {
+	t.Helper()
+
+	started := make(chan struct{})
+	go d.Do(context.Background(), "a", kind, func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+}
+
+func TestWritesAreSerialized(t *testing.T) {
+	d := New(Config{MaxReads: 2, MaxQueue: 10})
+
+	release := make(chan struct{})
+	hold(t, d, Write, release)
+
+	done := make(chan error)
+	go func() {
+		done <- d.Do(context.Background(), "a", Write, func() error { return nil })
+	}()
+
+	require.Eventually(t, func() bool {
+		return d.Stats("a").Write.Queued == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// 读操作和其他账号不受影响
+	require.NoError(t, d.Do(context.Background(), "a", Read, func() error { return nil }))
+	require.NoError(t, d.Do(context.Background(), "b", Write, func() error { return nil }))
+
+	close(release)
+	require.NoError(t, <-done)
+
+	stats := d.Stats("a")
+	require.Equal(t, 0, stats.Write.Queued)
+	require.Equal(t, int64(2), stats.Write.Served)
+	require.Equal(t, 1, stats.Write.Limit)
+}
+
+func TestQueueDepthLimit(t *testing.T) {
+	d := New(Config{MaxReads: 1, MaxQueue: 1})
+
+	release := make(chan struct{})
+	hold(t, d, Read, release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	waiting := make(chan error)
+	go func() {
+		waiting <- d.Do(ctx, "a", Read, func() error { return nil })
+	}()
+	require.Eventually(t, func() bool {
+		return d.Stats("a").Read.Queued == 1
+	}, time.Second, 10*time.Millisecond)
+
+	err := d.Do(context.Background(), "a", Read, func() error { return nil })
+	require.Equal(t, myerrors.CodeBusy, myerrors.CodeOf(err))
+	require.Equal(t, int64(1), d.Stats("a").Read.Rejected)
+
+	// 有空闲名额的请求不需要排队，不受排队上限影响
+	require.NoError(t, d.Do(context.Background(), "a", Write, func() error { return nil }))
+
+	cancel()
+	require.ErrorIs(t, <-waiting, context.Canceled)
+	require.Equal(t, 0, d.Stats("a").Read.Queued)
+
+	close(release)
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ActionError 浏览器操作在某一步失败时返回的错误，记录失败的操作和步骤，
+// 原始错误可以通过 errors.Is / errors.As 继续判断（如 context.DeadlineExceeded）。
+type ActionError struct {
+	Action string // 操作，如 search、publish_image
+	Step   string // 失败的步骤，如 navigate、read_state
+	Err    error
+}
+
+func (e *ActionError) Error() string {
+	return fmt.Sprintf("%s: %s failed: %v", e.Action, e.Step, e.Err)
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
+
+// Cause 兼容 github.com/pkg/errors
+func (e *ActionError) Cause() error {
+	return e.Err
+}
+
+// NewActionError 包装操作某一步的错误，err 为 nil 时返回 nil。
+// err 已经是 ActionError 时（如嵌套调用导航操作）保留最内层的步骤。
+func NewActionError(action, step string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var ae *ActionError
+	if errors.As(err, &ae) {
+		return err
+	}
+	return &ActionError{Action: action, Step: step, Err: err}
+}
+
+// StepOf 返回错误中记录的失败步骤，格式为 操作/步骤，不是 ActionError 时返回空
+func StepOf(err error) string {
+	var ae *ActionError
+	if !errors.As(err, &ae) {
+		return ""
+	}
+	return ae.Action + "/" + ae.Step
+}
@@ -0,0 +1,32 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewActionError(t *testing.T) {
+	require.NoError(t, NewActionError("search", "navigate", nil))
+
+	err := NewActionError("list_feeds", "read_state", ErrNoFeeds)
+	require.EqualError(t, err, "list_feeds: read_state failed: "+ErrNoFeeds.Error())
+	require.ErrorIs(t, err, ErrNoFeeds)
+	require.Equal(t, "list_feeds/read_state", StepOf(err))
+	require.Equal(t, ErrNoFeeds, pkgerrors.Cause(err))
+
+	// 外层包装后仍能取到步骤
+	wrapped := fmt.Errorf("搜索失败: %w", NewActionError("search", "wait_state", context.DeadlineExceeded))
+	require.ErrorIs(t, wrapped, context.DeadlineExceeded)
+	require.Equal(t, "search/wait_state", StepOf(wrapped))
+
+	// 嵌套调用保留最内层的步骤
+	inner := NewActionError("navigate", "click_profile_link", errors.New("element not found"))
+	require.Equal(t, "navigate/click_profile_link", StepOf(NewActionError("my_profile", "navigate", inner)))
+
+	require.Empty(t, StepOf(errors.New("plain")))
+}
@@ -0,0 +1,85 @@
+package errors
+
+import "errors"
+
+// Code 稳定的错误码，REST 和 MCP 都会原样返回给调用方，便于程序化处理
+type Code string
+
+const (
+	CodeNotLoggedIn      Code = "NOT_LOGGED_IN"      // 未登录或登录已失效
+	CodeCaptchaRequired  Code = "CAPTCHA_REQUIRED"   // 触发了验证码
+	CodeRateLimited      Code = "RATE_LIMITED"       // 访问频次异常
+	CodeNoteNotFound     Code = "NOTE_NOT_FOUND"     // 笔记不存在或已删除
+	CodeXsecTokenInvalid Code = "XSEC_TOKEN_INVALID" // xsec_token 无效或已过期
+	CodeSelectorNotFound Code = "SELECTOR_NOT_FOUND" // 页面结构变化，找不到元素
+	CodeUploadTimeout    Code = "UPLOAD_TIMEOUT"     // 上传图片或视频超时
+	CodeValidationFailed Code = "VALIDATION_FAILED"  // 参数校验失败
+	CodeBusy             Code = "BUSY"               // 账号排队的请求过多
+	CodeQuotaExceeded    Code = "QUOTA_EXCEEDED"     // 操作次数超过账号的额度
+	CodeInternal         Code = "INTERNAL_ERROR"     // 其他未分类的错误
+)
+
+// Error 带错误码的错误，Err 为可选的原始错误。
+// errors.Is 按错误码匹配，可以直接和下面的哨兵错误比较。
+type Error struct {
+	Code    Code
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	default:
+		return e.Message + ": " + e.Err.Error()
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is 错误码相同即视为同一类错误
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+var (
+	ErrNotLoggedIn      = New(CodeNotLoggedIn, "未登录或登录已失效")
+	ErrCaptchaRequired  = New(CodeCaptchaRequired, "触发验证码，请在浏览器中完成验证")
+	ErrRateLimited      = New(CodeRateLimited, "访问频次异常，请稍后再试")
+	ErrNoteNotFound     = New(CodeNoteNotFound, "笔记不存在或已被删除")
+	ErrXsecTokenInvalid = New(CodeXsecTokenInvalid, "xsec_token 无效或已过期")
+	ErrUploadTimeout    = New(CodeUploadTimeout, "上传超时，请检查网络连接和文件大小")
+)
+
+// New 创建带错误码的错误
+func New(code Code, message string) error {
+	return &Error{Code: code, Message: message}
+}
+
+// WithCode 给错误加上错误码，err 为 nil 时返回 nil
+func WithCode(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Validation 参数校验失败
+func Validation(message string) error {
+	return New(CodeValidationFailed, message)
+}
+
+// CodeOf 返回错误链上最外层的错误码，没有错误码时返回空
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
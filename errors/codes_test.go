@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOf(t *testing.T) {
+	require.Empty(t, CodeOf(nil))
+	require.Empty(t, CodeOf(errors.New("plain")))
+	require.NoError(t, WithCode(CodeRateLimited, nil))
+
+	err := NewActionError("publish_image", "check_page", ErrNotLoggedIn)
+	require.Equal(t, CodeNotLoggedIn, CodeOf(err))
+	require.ErrorIs(t, err, ErrNotLoggedIn)
+	require.NotErrorIs(t, err, ErrRateLimited)
+
+	// 错误码相同即匹配哨兵错误
+	err = fmt.Errorf("detail: %w", WithCode(CodeNoteNotFound, errors.New("feed not found in noteDetailMap")))
+	require.Equal(t, CodeNoteNotFound, CodeOf(err))
+	require.ErrorIs(t, err, ErrNoteNotFound)
+	require.ErrorIs(t, ErrNoFeedDetail, ErrNoteNotFound)
+	require.EqualError(t, err, "detail: feed not found in noteDetailMap")
+
+	// 附上诊断包后错误码和步骤不变
+	err = WithDiagnostics(NewActionError("like", "click_button", ErrRateLimited), "20261017-212626-a1b2c3")
+	require.Equal(t, CodeRateLimited, CodeOf(err))
+	require.Equal(t, "like/click_button", StepOf(err))
+	require.Equal(t, "20261017-212626-a1b2c3", DiagnosticsOf(err))
+	require.Empty(t, DiagnosticsOf(ErrRateLimited))
+
+	require.EqualError(t, Validation("标题长度超过限制"), "标题长度超过限制")
+	require.Equal(t, CodeValidationFailed, CodeOf(Validation("标题长度超过限制")))
+}
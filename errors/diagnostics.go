@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DiagnosticsError 附带诊断包 ID 的错误，诊断包中保存了失败时的页面现场
+type DiagnosticsError struct {
+	ID  string
+	Err error
+}
+
+func (e *DiagnosticsError) Error() string {
+	return fmt.Sprintf("%v (诊断包: %s)", e.Err, e.ID)
+}
+
+func (e *DiagnosticsError) Unwrap() error {
+	return e.Err
+}
+
+// WithDiagnostics 给错误附上诊断包 ID，err 为 nil 或 id 为空时原样返回
+func WithDiagnostics(err error, id string) error {
+	if err == nil || id == "" {
+		return err
+	}
+	return &DiagnosticsError{ID: id, Err: err}
+}
+
+// DiagnosticsOf 返回错误附带的诊断包 ID，没有时返回空
+func DiagnosticsOf(err error) string {
+	var e *DiagnosticsError
+	if errors.As(err, &e) {
+		return e.ID
+	}
+	return ""
+}
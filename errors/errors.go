@@ -3,4 +3,4 @@ package errors
 import "errors"
 
 var ErrNoFeeds = errors.New("没有捕获到 feeds 数据")
-var ErrNoFeedDetail = errors.New("没有捕获到 feed 详情数据")
+var ErrNoFeedDetail = &Error{Code: CodeNoteNotFound, Message: "没有捕获到 feed 详情数据"}
@@ -0,0 +1,35 @@
+package errors
+
+import "errors"
+
+// NotExecutedError 操作在浏览器对网站做任何事之前就失败了，如排队被拒绝、账号等待验证、排队时取消或没有可用的浏览器
+type NotExecutedError struct {
+	Err error
+}
+
+func (e *NotExecutedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NotExecutedError) Unwrap() error {
+	return e.Err
+}
+
+// NotExecuted 标记错误发生在操作执行之前，err 为 nil 时返回 nil
+func NotExecuted(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &NotExecutedError{Err: err}
+}
+
+// IsNotExecuted 错误是否发生在操作执行之前。
+// 除了 NotExecuted 标记的错误，参数校验失败和排队被拒绝也没有访问网站。
+func IsNotExecuted(err error) bool {
+	var e *NotExecutedError
+	if errors.As(err, &e) {
+		return true
+	}
+	code := CodeOf(err)
+	return code == CodeValidationFailed || code == CodeBusy
+}
@@ -0,0 +1,26 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotExecuted(t *testing.T) {
+	require.NoError(t, NotExecuted(nil))
+
+	err := fmt.Errorf("like: %w", NotExecuted(context.Canceled))
+	require.True(t, IsNotExecuted(err))
+	require.ErrorIs(t, err, context.Canceled)
+	require.EqualError(t, err, "like: context canceled")
+
+	require.True(t, IsNotExecuted(Validation("内容不能为空")))
+	require.True(t, IsNotExecuted(New(CodeBusy, "busy")))
+
+	// 操作执行过程中的失败已经访问了网站
+	require.False(t, IsNotExecuted(ErrUploadTimeout))
+	require.False(t, IsNotExecuted(NewActionError("publish", "submit", New(CodeSelectorNotFound, "找不到发布按钮"))))
+	require.False(t, IsNotExecuted(ErrCaptchaRequired))
+}
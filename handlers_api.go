@@ -1,15 +1,27 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/diagnostics"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
-// respondError 返回错误响应
+// maxCookiesImportSize 导入 cookies 请求体的大小上限
+const maxCookiesImportSize = 1 << 20
+
+// respondError 返回错误响应。
+// details 为带错误码的 error 时，使用错误码和对应的 HTTP 状态码，并带上失败的步骤。
 func respondError(c *gin.Context, statusCode int, code, message string, details any) {
 	response := ErrorResponse{
 		Error:   message,
@@ -17,12 +29,44 @@ func respondError(c *gin.Context, statusCode int, code, message string, details
 		Details: details,
 	}
 
+	if err, ok := details.(error); ok {
+		if errCode := myerrors.CodeOf(err); errCode != "" {
+			statusCode = httpStatusOf(errCode)
+			response.Code = string(errCode)
+		}
+		response.Step = myerrors.StepOf(err)
+		response.DiagnosticsID = myerrors.DiagnosticsOf(err)
+		response.Details = err.Error()
+	}
+
 	logrus.Errorf("%s %s %s %d", c.Request.Method, c.Request.URL.Path,
 		c.GetString("account"), statusCode)
 
 	c.JSON(statusCode, response)
 }
 
+// httpStatusOf 错误码对应的 HTTP 状态码
+func httpStatusOf(code myerrors.Code) int {
+	switch code {
+	case myerrors.CodeNotLoggedIn:
+		return http.StatusUnauthorized
+	case myerrors.CodeCaptchaRequired, myerrors.CodeXsecTokenInvalid:
+		return http.StatusForbidden
+	case myerrors.CodeRateLimited, myerrors.CodeBusy, myerrors.CodeQuotaExceeded:
+		return http.StatusTooManyRequests
+	case myerrors.CodeNoteNotFound:
+		return http.StatusNotFound
+	case myerrors.CodeValidationFailed:
+		return http.StatusBadRequest
+	case myerrors.CodeSelectorNotFound:
+		return http.StatusBadGateway
+	case myerrors.CodeUploadTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // respondSuccess 返回成功响应
 func respondSuccess(c *gin.Context, data any, message string) {
 	response := SuccessResponse{
@@ -42,33 +86,199 @@ func (s *AppServer) checkLoginStatusHandler(c *gin.Context) {
 	status, err := s.xiaohongshuService.CheckLoginStatus(c.Request.Context())
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "STATUS_CHECK_FAILED",
-			"检查登录状态失败", err.Error())
+			"检查登录状态失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, status, "检查登录状态成功")
 }
 
+// logoutHandler 处理 [DELETE /api/v1/login] 请求。
+// 在网站上登出并清除账号保存的 cookies 和浏览器。
+func (s *AppServer) logoutHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.Logout(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "LOGOUT_FAILED",
+			"退出登录失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "退出登录成功")
+}
+
+// importCookiesHandler 处理 [POST /api/v1/cookies/import] 请求。
+// 请求体为 cookies 文件内容，format 参数指定格式（netscape/editthiscookie/rod），为空时自动识别。
+func (s *AppServer) importCookiesHandler(c *gin.Context) {
+	format, err := cookies.ParseFormat(c.Query("format"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"不支持的 cookies 格式", err)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCookiesImportSize))
+	if err != nil || len(data) == 0 {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求体为空", nil)
+		return
+	}
+
+	result, err := s.xiaohongshuService.ImportCookies(c.Request.Context(), data, format)
+	if err != nil {
+		respondError(c, http.StatusUnprocessableEntity, "COOKIES_IMPORT_FAILED",
+			"导入 cookies 失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "导入 cookies 成功")
+}
+
+// exportCookiesHandler 处理 [GET /api/v1/cookies/export] 请求。
+// 以文件形式返回账号保存的 cookies，format 参数默认为 rod。
+func (s *AppServer) exportCookiesHandler(c *gin.Context) {
+	format, err := cookies.ParseFormat(c.Query("format"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"不支持的 cookies 格式", err)
+		return
+	}
+	if format == "" {
+		format = cookies.FormatRod
+	}
+
+	data, err := s.xiaohongshuService.ExportCookies(c.Request.Context(), format)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "COOKIES_EXPORT_FAILED",
+			"导出 cookies 失败", err)
+		return
+	}
+
+	contentType, filename := "application/json", "cookies.json"
+	if format == cookies.FormatNetscape {
+		contentType, filename = "text/plain; charset=utf-8", "cookies.txt"
+	}
+
+	logrus.Infof("%s %s %s %d", c.Request.Method, c.Request.URL.Path,
+		c.GetString("account"), http.StatusOK)
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// listDiagnosticsHandler 处理 [GET /api/v1/diagnostics] 请求。
+// 按时间倒序列出当前账号操作失败时保存的诊断包。
+func (s *AppServer) listDiagnosticsHandler(c *gin.Context) {
+	bundles, err := s.xiaohongshuService.ListDiagnostics(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "LIST_DIAGNOSTICS_FAILED",
+			"获取诊断包列表失败", err)
+		return
+	}
+
+	respondSuccess(c, bundles, "获取诊断包列表成功")
+}
+
+// downloadDiagnosticsHandler 处理 [GET /api/v1/diagnostics/:id] 请求。
+// 以 zip 文件返回诊断包：meta.json、screenshot.png、page.html、state.json。
+func (s *AppServer) downloadDiagnosticsHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	var buf bytes.Buffer
+	if err := s.xiaohongshuService.WriteDiagnostics(c.Request.Context(), &buf, id); err != nil {
+		if errors.Is(err, diagnostics.ErrNotFound) {
+			respondError(c, http.StatusNotFound, "DIAGNOSTICS_NOT_FOUND", "诊断包不存在", err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "DOWNLOAD_DIAGNOSTICS_FAILED",
+			"下载诊断包失败", err)
+		return
+	}
+
+	logrus.Infof("%s %s %s %d", c.Request.Method, c.Request.URL.Path,
+		c.GetString("account"), http.StatusOK)
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id+".zip"))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
 // getLoginQrcodeHandler 处理 [GET /api/login/qrcode] 请求。
 // 用于生成并返回登录二维码（Base64 图片 + 超时时间），供前端展示给用户扫码登录。
 func (s *AppServer) getLoginQrcodeHandler(c *gin.Context) {
 	result, err := s.xiaohongshuService.GetLoginQrcode(c.Request.Context())
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "STATUS_CHECK_FAILED",
-			"获取登录二维码失败", err.Error())
+			"获取登录二维码失败", err)
 		return
 	}
 
 	respondSuccess(c, result, "获取登录二维码成功")
 }
 
+// getLoginSessionHandler 处理 [GET /api/v1/login/qrcode/:id] 请求。
+// 返回扫码登录会话的状态：pending/scanned/succeeded/expired/failed/cancelled。
+func (s *AppServer) getLoginSessionHandler(c *gin.Context) {
+	info, err := s.xiaohongshuService.GetLoginSession(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondLoginSessionError(c, err, "查询扫码登录状态失败")
+		return
+	}
+
+	respondSuccess(c, info, "查询扫码登录状态成功")
+}
+
+// cancelLoginSessionHandler 处理 [DELETE /api/v1/login/qrcode/:id] 请求。
+func (s *AppServer) cancelLoginSessionHandler(c *gin.Context) {
+	info, err := s.xiaohongshuService.CancelLoginSession(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondLoginSessionError(c, err, "取消扫码登录失败")
+		return
+	}
+
+	respondSuccess(c, info, "取消扫码登录成功")
+}
+
+// refreshLoginQrcodeHandler 处理 [POST /api/v1/login/qrcode/:id/refresh] 请求。
+// 重新生成二维码，会话 ID 保持不变。
+func (s *AppServer) refreshLoginQrcodeHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.RefreshLoginQrcode(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondLoginSessionError(c, err, "刷新登录二维码失败")
+		return
+	}
+
+	respondSuccess(c, result, "刷新登录二维码成功")
+}
+
+func respondLoginSessionError(c *gin.Context, err error, message string) {
+	if errors.Is(err, ErrLoginSessionNotFound) {
+		respondError(c, http.StatusNotFound, "LOGIN_SESSION_NOT_FOUND", message, err)
+		return
+	}
+
+	respondError(c, http.StatusInternalServerError, "LOGIN_SESSION_FAILED", message, err)
+}
+
+// loginHealthHandler 处理 [GET /api/v1/login/health] 请求。
+// 返回会话健康状态（有效/即将过期/已过期/未登录），refresh=true 时实时检查登录状态。
+func (s *AppServer) loginHealthHandler(c *gin.Context) {
+	refresh := c.Query("refresh") == "true"
+
+	result, err := s.sessionMonitor.Health(c.Request.Context(), refresh)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "SESSION_HEALTH_FAILED",
+			"获取会话健康状态失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取会话健康状态成功")
+}
+
 // publishHandler 发布内容
 func (s *AppServer) publishHandler(c *gin.Context) {
 	var req PublishRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
-			"请求参数错误", err.Error())
+			"请求参数错误", err)
 		return
 	}
 
@@ -76,7 +286,7 @@ func (s *AppServer) publishHandler(c *gin.Context) {
 	result, err := s.xiaohongshuService.PublishContent(c.Request.Context(), &req)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "PUBLISH_FAILED",
-			"发布失败", err.Error())
+			"发布失败", err)
 		return
 	}
 
@@ -88,7 +298,7 @@ func (s *AppServer) publishVideoHandler(c *gin.Context) {
 	var req PublishVideoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
-			"请求参数错误", err.Error())
+			"请求参数错误", err)
 		return
 	}
 
@@ -96,7 +306,7 @@ func (s *AppServer) publishVideoHandler(c *gin.Context) {
 	result, err := s.xiaohongshuService.PublishVideo(c.Request.Context(), &req)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "PUBLISH_VIDEO_FAILED",
-			"视频发布失败", err.Error())
+			"视频发布失败", err)
 		return
 	}
 
@@ -105,21 +315,28 @@ func (s *AppServer) publishVideoHandler(c *gin.Context) {
 
 // listFeedsHandler 获取Feeds列表
 func (s *AppServer) listFeedsHandler(c *gin.Context) {
+	location, err := parseLocation(c.Query("location"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err)
+		return
+	}
+
 	// 获取 Feeds 列表
-	result, err := s.xiaohongshuService.ListFeeds(c.Request.Context())
+	result, err := s.xiaohongshuService.ListFeeds(c.Request.Context(), location)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "LIST_FEEDS_FAILED",
-			"获取Feeds列表失败", err.Error())
+			"获取Feeds列表失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, result, "获取Feeds列表成功")
 }
 
 // searchFeedsHandler 搜索Feeds
 func (s *AppServer) searchFeedsHandler(c *gin.Context) {
-	var keyword string
+	var keyword, rawLocation, cursor string
+	var limit int
 	var filters xiaohongshu.FilterOption
 
 	switch c.Request.Method {
@@ -128,13 +345,26 @@ func (s *AppServer) searchFeedsHandler(c *gin.Context) {
 		var searchReq SearchFeedsRequest
 		if err := c.ShouldBindJSON(&searchReq); err != nil {
 			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
-				"请求参数错误", err.Error())
+				"请求参数错误", err)
 			return
 		}
 		keyword = searchReq.Keyword
 		filters = searchReq.Filters
+		rawLocation = searchReq.Location
+		limit = searchReq.Limit
+		cursor = searchReq.Cursor
 	default:
 		keyword = c.Query("keyword")
+		rawLocation = c.Query("location")
+		cursor = c.Query("cursor")
+		if rawLimit := c.Query("limit"); rawLimit != "" {
+			var err error
+			if limit, err = strconv.Atoi(rawLimit); err != nil {
+				respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+					"请求参数错误", "limit must be an integer")
+				return
+			}
+		}
 	}
 
 	if keyword == "" {
@@ -143,15 +373,21 @@ func (s *AppServer) searchFeedsHandler(c *gin.Context) {
 		return
 	}
 
+	location, err := parseLocation(rawLocation)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err)
+		return
+	}
+
 	// 搜索 Feeds
-	result, err := s.xiaohongshuService.SearchFeeds(c.Request.Context(), keyword, filters)
+	result, err := s.xiaohongshuService.SearchFeeds(c.Request.Context(), keyword, location, limit, cursor, filters)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "SEARCH_FEEDS_FAILED",
-			"搜索Feeds失败", err.Error())
+			"搜索Feeds失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, result, "搜索Feeds成功")
 }
 
@@ -160,7 +396,7 @@ func (s *AppServer) getFeedDetailHandler(c *gin.Context) {
 	var req FeedDetailRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
-			"请求参数错误", err.Error())
+			"请求参数错误", err)
 		return
 	}
 
@@ -168,11 +404,10 @@ func (s *AppServer) getFeedDetailHandler(c *gin.Context) {
 	result, err := s.xiaohongshuService.GetFeedDetail(c.Request.Context(), req.FeedID, req.XsecToken)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "GET_FEED_DETAIL_FAILED",
-			"获取Feed详情失败", err.Error())
+			"获取Feed详情失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, result, "获取Feed详情成功")
 }
 
@@ -181,19 +416,18 @@ func (s *AppServer) userProfileHandler(c *gin.Context) {
 	var req UserProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
-			"请求参数错误", err.Error())
+			"请求参数错误", err)
 		return
 	}
 
 	// 获取用户信息
-	result, err := s.xiaohongshuService.UserProfile(c.Request.Context(), req.UserID, req.XsecToken)
+	result, err := s.xiaohongshuService.UserProfile(c.Request.Context(), req.UserID, req.XsecToken, req.Limit)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "GET_USER_PROFILE_FAILED",
-			"获取用户主页失败", err.Error())
+			"获取用户主页失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, map[string]any{"data": result}, "result.Message")
 }
 
@@ -202,7 +436,7 @@ func (s *AppServer) postCommentHandler(c *gin.Context) {
 	var req PostCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
-			"请求参数错误", err.Error())
+			"请求参数错误", err)
 		return
 	}
 
@@ -210,14 +444,89 @@ func (s *AppServer) postCommentHandler(c *gin.Context) {
 	result, err := s.xiaohongshuService.PostCommentToFeed(c.Request.Context(), req.FeedID, req.XsecToken, req.Content)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "POST_COMMENT_FAILED",
-			"发表评论失败", err.Error())
+			"发表评论失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, result, result.Message)
 }
 
+// listAccountsHandler 列出所有已配置的账号
+func (s *AppServer) listAccountsHandler(c *gin.Context) {
+	list := s.xiaohongshuService.Accounts()
+
+	result := make([]AccountInfo, 0, len(list))
+	for _, acc := range list {
+		result = append(result, AccountInfo{ID: acc.ID, Name: acc.DisplayName()})
+	}
+
+	respondSuccess(c, result, "获取账号列表成功")
+}
+
+// queueStatsHandler 处理 [GET /api/v1/queue] 请求。
+// 返回当前账号的排队深度、等待时间和浏览器池状态。
+func (s *AppServer) queueStatsHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.QueueStats(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "GET_QUEUE_STATS_FAILED",
+			"获取排队状态失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取排队状态成功")
+}
+
+// captchaStatusHandler 处理 [GET /api/v1/captcha] 请求。
+// 返回账号是否因触发验证码而暂停、触发时的截图和人工验证会话状态。
+func (s *AppServer) captchaStatusHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.CaptchaStatus(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "GET_CAPTCHA_STATUS_FAILED",
+			"获取验证状态失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取验证状态成功")
+}
+
+// resolveCaptchaHandler 处理 [POST /api/v1/captcha/resolve] 请求。
+// 在服务端打开有界面的浏览器，人工完成验证后账号自动恢复。
+func (s *AppServer) resolveCaptchaHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.ResolveCaptcha(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "RESOLVE_CAPTCHA_FAILED",
+			"启动人工验证失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "请在打开的浏览器中完成验证")
+}
+
+// cancelCaptchaHandler 处理 [DELETE /api/v1/captcha/resolve] 请求，取消进行中的人工验证
+func (s *AppServer) cancelCaptchaHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.CancelCaptcha(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "CANCEL_CAPTCHA_FAILED",
+			"取消人工验证失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "已取消人工验证")
+}
+
+// quotaHandler 处理 [GET /api/v1/quota] 请求。
+// 返回当前账号发布、评论、点赞等操作的额度用量和剩余次数。
+func (s *AppServer) quotaHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.QuotaUsage(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "GET_QUOTA_FAILED",
+			"获取操作额度失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取操作额度成功")
+}
+
 // healthHandler 健康检查
 func healthHandler(c *gin.Context) {
 	respondSuccess(c, map[string]any{
@@ -234,11 +543,10 @@ func (s *AppServer) myProfileHandler(c *gin.Context) {
 	result, err := s.xiaohongshuService.GetMyProfile(c.Request.Context())
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "GET_MY_PROFILE_FAILED",
-			"获取我的主页失败", err.Error())
+			"获取我的主页失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, map[string]any{"data": result}, "获取我的主页成功")
 }
 
@@ -248,10 +556,9 @@ func (s *AppServer) getUserLikedFeedsHandler(c *gin.Context) {
 	result, err := s.xiaohongshuService.GetUserLikedFeeds(c.Request.Context())
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "GET_USER_LIKED_FEEDS_FAILED",
-			"获取用户点赞笔记失败", err.Error())
+			"获取用户点赞笔记失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, result, "获取用户点赞笔记成功")
 }
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+const (
+	// loginQrcodeTimeout 二维码的有效时长
+	loginQrcodeTimeout = 4 * time.Minute
+	// loginSessionRetention 结束的登录会话保留多久，供客户端查询结果
+	loginSessionRetention = 30 * time.Minute
+	// loginPollInterval 轮询二维码状态的间隔
+	loginPollInterval = 500 * time.Millisecond
+)
+
+// ErrLoginSessionNotFound 登录会话不存在或已被清理
+var ErrLoginSessionNotFound = errors.New("login session not found")
+
+// LoginSessionStatus 扫码登录会话状态
+type LoginSessionStatus string
+
+const (
+	LoginPending   LoginSessionStatus = "pending"   // 等待扫码
+	LoginScanned   LoginSessionStatus = "scanned"   // 已扫码，等待确认
+	LoginSucceeded LoginSessionStatus = "succeeded" // 登录成功，cookies 已保存
+	LoginExpired   LoginSessionStatus = "expired"   // 二维码过期
+	LoginFailed    LoginSessionStatus = "failed"    // 登录过程出错
+	LoginCancelled LoginSessionStatus = "cancelled" // 被取消
+)
+
+// Finished 会话是否已结束
+func (s LoginSessionStatus) Finished() bool {
+	return s != LoginPending && s != LoginScanned
+}
+
+// LoginSessionInfo 登录会话对外展示的信息
+type LoginSessionInfo struct {
+	ID        string             `json:"id"`
+	Account   string             `json:"account"`
+	Status    LoginSessionStatus `json:"status"`
+	Img       string             `json:"img,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// loginSession 一次扫码登录会话，持有独立的浏览器直到会话结束
+type loginSession struct {
+	info   LoginSessionInfo
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// LoginSessionManager 管理扫码登录会话
+type LoginSessionManager struct {
+	service *XiaohongshuService
+
+	// startMu 串行化创建会话，避免同一账号并发启动多个扫码浏览器
+	startMu sync.Mutex
+
+	mu       sync.Mutex
+	sessions map[string]*loginSession
+}
+
+func newLoginSessionManager(service *XiaohongshuService) *LoginSessionManager {
+	return &LoginSessionManager{
+		service:  service,
+		sessions: make(map[string]*loginSession),
+	}
+}
+
+// Start 为账号开始扫码登录。
+// 已登录时返回 loggedIn=true；账号已有进行中的会话时直接返回该会话。
+func (m *LoginSessionManager) Start(ctx context.Context, acc *accounts.Account) (info *LoginSessionInfo, loggedIn bool, err error) {
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
+
+	m.cleanup()
+
+	if sess := m.active(acc.ID); sess != nil {
+		return sess, false, nil
+	}
+
+	return m.start(ctx, acc, newLoginSessionID())
+}
+
+// Get 查询账号的登录会话，会话属于其他账号时视为不存在
+func (m *LoginSessionManager) Get(accountID, id string) (*LoginSessionInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sess, ok := m.sessions[id]
+	if !ok || sess.info.Account != accountID {
+		return nil, ErrLoginSessionNotFound
+	}
+
+	info := sess.info
+	return &info, nil
+}
+
+// Cancel 取消账号进行中的登录会话并关闭浏览器
+func (m *LoginSessionManager) Cancel(accountID, id string) (*LoginSessionInfo, error) {
+	m.mu.Lock()
+	sess, ok := m.sessions[id]
+	m.mu.Unlock()
+	if !ok || sess.info.Account != accountID {
+		return nil, ErrLoginSessionNotFound
+	}
+
+	sess.cancel()
+	<-sess.done
+
+	return m.Get(accountID, id)
+}
+
+// CancelAccount 取消账号所有进行中的登录会话
+func (m *LoginSessionManager) CancelAccount(accountID string) {
+	m.mu.Lock()
+	var list []*loginSession
+	for _, sess := range m.sessions {
+		if sess.info.Account == accountID {
+			list = append(list, sess)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, sess := range list {
+		sess.cancel()
+		<-sess.done
+	}
+}
+
+// Refresh 重新获取账号的二维码，沿用原会话 ID。进行中的会话会先被取消。
+func (m *LoginSessionManager) Refresh(ctx context.Context, acc *accounts.Account, id string) (info *LoginSessionInfo, loggedIn bool, err error) {
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
+
+	old, err := m.Cancel(acc.ID, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if old.Status == LoginSucceeded {
+		return old, true, nil
+	}
+
+	return m.start(ctx, acc, id)
+}
+
+// Close 取消所有进行中的会话
+func (m *LoginSessionManager) Close() {
+	m.mu.Lock()
+	list := make([]*loginSession, 0, len(m.sessions))
+	for _, sess := range m.sessions {
+		list = append(list, sess)
+	}
+	m.mu.Unlock()
+
+	for _, sess := range list {
+		sess.cancel()
+		<-sess.done
+	}
+}
+
+func (m *LoginSessionManager) start(ctx context.Context, acc *accounts.Account, id string) (*LoginSessionInfo, bool, error) {
+	release, err := m.service.holdProfile(ctx, acc)
+	if err != nil {
+		return nil, false, err
+	}
+	b, err := launchBrowser(acc)
+	if err != nil {
+		release()
+		return nil, false, err
+	}
+	page, err := newLoginPage(b)
+	if err != nil {
+		closeLoginBrowser(b)
+		release()
+		return nil, false, err
+	}
+
+	loginAction := xiaohongshu.NewLogin(page)
+
+	img, loggedIn, err := loginAction.FetchQrcodeImage(ctx)
+	if err != nil || loggedIn {
+		_ = page.Close()
+		closeLoginBrowser(b)
+		release()
+		return nil, loggedIn, err
+	}
+
+	now := time.Now()
+	waitCtx, cancel := context.WithTimeout(context.Background(), loginQrcodeTimeout)
+	sess := &loginSession{
+		info: LoginSessionInfo{
+			ID:        id,
+			Account:   acc.ID,
+			Status:    LoginPending,
+			Img:       img,
+			CreatedAt: now,
+			ExpiresAt: now.Add(loginQrcodeTimeout),
+			UpdatedAt: now,
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.sessions[id] = sess
+	m.mu.Unlock()
+
+	go func() {
+		defer close(sess.done)
+		defer cancel()
+		defer release()
+		defer closeLoginBrowser(b)
+		defer func() { _ = page.Close() }()
+
+		m.wait(waitCtx, sess, acc, page, loginAction)
+	}()
+
+	info := sess.info
+	return &info, false, nil
+}
+
+// wait 轮询二维码状态直到登录成功、过期或被取消
+func (m *LoginSessionManager) wait(ctx context.Context, sess *loginSession, acc *accounts.Account, page *rod.Page, loginAction *xiaohongshu.LoginAction) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.finish(sess, LoginFailed, fmt.Sprintf("登录过程发生错误: %v", r))
+		}
+	}()
+
+	ticker := time.NewTicker(loginPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				m.finish(sess, LoginExpired, "二维码已过期，请刷新")
+			} else {
+				m.finish(sess, LoginCancelled, "")
+			}
+			return
+		case <-ticker.C:
+		}
+
+		state, err := loginAction.GetQrcodeState(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			logrus.Debugf("login session %s: read qrcode state failed: %v", sess.info.ID, err)
+			continue
+		}
+
+		switch state {
+		case xiaohongshu.QrcodeScanned:
+			m.update(sess, LoginScanned)
+		case xiaohongshu.QrcodeExpired:
+			m.finish(sess, LoginExpired, "二维码已过期，请刷新")
+			return
+		case xiaohongshu.QrcodeLoggedIn:
+			if err := saveCookies(page, acc.Cookier()); err != nil {
+				logrus.Errorf("failed to save cookies for account %s: %v", acc.ID, err)
+				m.finish(sess, LoginFailed, fmt.Sprintf("保存 cookies 失败: %v", err))
+				return
+			}
+			// 池中的浏览器仍然持有旧的 cookies，需要重建
+			m.service.drainPool(acc)
+			m.finish(sess, LoginSucceeded, "")
+			return
+		}
+	}
+}
+
+func (m *LoginSessionManager) update(sess *loginSession, status LoginSessionStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if sess.info.Status != status {
+		sess.info.Status = status
+		sess.info.UpdatedAt = time.Now()
+	}
+}
+
+func (m *LoginSessionManager) finish(sess *loginSession, status LoginSessionStatus, msg string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sess.info.Status = status
+	sess.info.Error = msg
+	sess.info.UpdatedAt = time.Now()
+	if status == LoginSucceeded {
+		sess.info.Img = ""
+	}
+
+	logrus.Infof("login session %s for account %s finished: %s", sess.info.ID, sess.info.Account, status)
+}
+
+// active 返回账号进行中的会话
+func (m *LoginSessionManager) active(accountID string) *LoginSessionInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, sess := range m.sessions {
+		if sess.info.Account == accountID && !sess.info.Status.Finished() {
+			info := sess.info
+			return &info
+		}
+	}
+	return nil
+}
+
+// cleanup 清理结束超过保留时长的会话
+func (m *LoginSessionManager) cleanup() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deadline := time.Now().Add(-loginSessionRetention)
+	for id, sess := range m.sessions {
+		if sess.info.Status.Finished() && sess.info.UpdatedAt.Before(deadline) {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+func newLoginSessionID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// newLoginPage 创建扫码登录页面，页面初始化失败时 NewPage 会 panic，转换为错误返回，
+// 由调用方关闭浏览器释放用户数据目录
+func newLoginPage(b *browser.Browser) (page *rod.Page, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("create login page panic: %v", r)
+		}
+	}()
+
+	return b.NewPage(), nil
+}
+
+func closeLoginBrowser(b *browser.Browser) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Warnf("close login browser panic: %v", r)
+		}
+	}()
+
+	b.Close()
+}
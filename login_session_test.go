@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginSessionScopedToAccount(t *testing.T) {
+	m := newLoginSessionManager(nil)
+	_, cancel := context.WithCancel(context.Background())
+	sess := &loginSession{
+		info:   LoginSessionInfo{ID: "s1", Account: "a", Status: LoginPending},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	close(sess.done)
+	m.sessions["s1"] = sess
+
+	info, err := m.Get("a", "s1")
+	require.NoError(t, err)
+	require.Equal(t, "a", info.Account)
+
+	// 其他账号查询、取消都视为不存在
+	_, err = m.Get("b", "s1")
+	require.ErrorIs(t, err, ErrLoginSessionNotFound)
+	_, err = m.Cancel("b", "s1")
+	require.ErrorIs(t, err, ErrLoginSessionNotFound)
+
+	_, err = m.Cancel("a", "s1")
+	require.NoError(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 	"strings"
 	"time"
@@ -12,22 +13,53 @@ import (
 
 // MCP 工具处理函数
 
+// errorResult 返回工具执行失败的结果，structuredContent.error 中带上错误码和失败的步骤，
+// 方便调用方按错误码处理（如 NOT_LOGGED_IN 时引导重新登录）
+func errorResult(prefix string, err error) *MCPToolResult {
+	code := myerrors.CodeOf(err)
+	if code == "" {
+		code = myerrors.CodeInternal
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: fmt.Sprintf("%s: %v [%s]", prefix, err, code),
+		}},
+		IsError: true,
+		StructuredContent: map[string]any{
+			"error": MCPError{
+				Code:    string(code),
+				Message: err.Error(),
+				Step:    myerrors.StepOf(err),
+
+				DiagnosticsID: myerrors.DiagnosticsOf(err),
+			},
+		},
+	}
+}
+
 // handleCheckLoginStatus 处理检查登录状态
 func (s *AppServer) handleCheckLoginStatus(ctx context.Context) *MCPToolResult {
 	logrus.Info("MCP: 检查登录状态")
 
 	status, err := s.xiaohongshuService.CheckLoginStatus(ctx)
+	if err != nil {
+		return errorResult("检查登录状态失败", err)
+	}
+
+	jsonData, err := json.MarshalIndent(status, "", "  ")
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "检查登录状态失败: " + err.Error(),
+				Text: fmt.Sprintf("登录状态检查成功，但序列化失败: %v", err),
 			}},
 			IsError: true,
 		}
 	}
 
-	resultText := fmt.Sprintf("登录状态检查成功: %+v", status)
+	resultText := "登录状态检查成功:\n" + string(jsonData)
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -43,10 +75,7 @@ func (s *AppServer) handleGetLoginQrcode(ctx context.Context) *MCPToolResult {
 
 	result, err := s.xiaohongshuService.GetLoginQrcode(ctx)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{Type: "text", Text: "获取登录扫码图片失败: " + err.Error()}},
-			IsError: true,
-		}
+		return errorResult("获取登录扫码图片失败", err)
 	}
 
 	if result.IsLoggedIn {
@@ -55,27 +84,214 @@ func (s *AppServer) handleGetLoginQrcode(ctx context.Context) *MCPToolResult {
 		}
 	}
 
-	now := time.Now()
-	deadline := func() string {
-		d, err := time.ParseDuration(result.Timeout)
-		if err != nil {
-			return now.Format("2006-01-02 15:04:05")
-		}
-		return now.Add(d).Format("2006-01-02 15:04:05")
-	}()
+	// 未登录：文本 + 图片
+	return &MCPToolResult{Content: loginQrcodeContents(result)}
+}
+
+// loginQrcodeContents 生成二维码提示文本和图片
+func loginQrcodeContents(result *LoginQrcodeResponse) []MCPContent {
+	deadline := time.Now().Format("2006-01-02 15:04:05")
+	if result.ExpiresAt != nil {
+		deadline = result.ExpiresAt.Format("2006-01-02 15:04:05")
+	}
 
-	// 已登录：文本 + 图片
-	contents := []MCPContent{
-		{Type: "text", Text: "请用小红书 App 在 " + deadline + " 前扫码登录 👇"},
+	return []MCPContent{
+		{Type: "text", Text: fmt.Sprintf("请用小红书 App 在 %s 前扫码登录 👇\n会话ID: %s（可通过 get_login_qrcode_status 查询登录结果）", deadline, result.SessionID)},
 		{
 			Type:     "image",
 			MimeType: "image/png",
 			Data:     strings.TrimPrefix(result.Img, "data:image/png;base64,"),
 		},
 	}
+}
+
+// handleLogout 处理退出登录
+func (s *AppServer) handleLogout(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 退出登录")
+
+	result, err := s.xiaohongshuService.Logout(ctx)
+	if err != nil {
+		return errorResult("退出登录失败", err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: fmt.Sprintf("账号 %s %s", result.Account, result.Message),
+		}},
+	}
+}
+
+// handleGetLoginQrcodeStatus 处理查询扫码登录状态
+func (s *AppServer) handleGetLoginQrcodeStatus(ctx context.Context, sessionID string) *MCPToolResult {
+	logrus.Infof("MCP: 查询扫码登录状态 - Session ID: %s", sessionID)
+
+	info, err := s.xiaohongshuService.GetLoginSession(ctx, sessionID)
+	if err != nil {
+		return errorResult("查询扫码登录状态失败", err)
+	}
+
+	return loginSessionResult(info)
+}
+
+// handleCancelLoginQrcode 处理取消扫码登录
+func (s *AppServer) handleCancelLoginQrcode(ctx context.Context, sessionID string) *MCPToolResult {
+	logrus.Infof("MCP: 取消扫码登录 - Session ID: %s", sessionID)
+
+	info, err := s.xiaohongshuService.CancelLoginSession(ctx, sessionID)
+	if err != nil {
+		return errorResult("取消扫码登录失败", err)
+	}
+
+	return loginSessionResult(info)
+}
+
+// handleRefreshLoginQrcode 处理刷新登录二维码
+func (s *AppServer) handleRefreshLoginQrcode(ctx context.Context, sessionID string) *MCPToolResult {
+	logrus.Infof("MCP: 刷新登录二维码 - Session ID: %s", sessionID)
+
+	result, err := s.xiaohongshuService.RefreshLoginQrcode(ctx, sessionID)
+	if err != nil {
+		return errorResult("刷新登录二维码失败", err)
+	}
+
+	if result.IsLoggedIn {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "你当前已处于登录状态"}},
+		}
+	}
+
+	return &MCPToolResult{Content: loginQrcodeContents(result)}
+}
+
+// loginSessionResult 将登录会话信息（不含二维码图片）序列化为文本结果
+func loginSessionResult(info *LoginSessionInfo) *MCPToolResult {
+	info.Img = ""
+
+	jsonData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("序列化登录会话失败: %v", err)}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: string(jsonData)}},
+	}
+}
+
+// handleCheckSessionHealth 处理会话健康检查
+func (s *AppServer) handleCheckSessionHealth(ctx context.Context, refresh bool) *MCPToolResult {
+	logrus.Info("MCP: 检查会话健康状态")
+
+	result, err := s.sessionMonitor.Health(ctx, refresh)
+	if err != nil {
+		return errorResult("检查会话健康状态失败", err)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("检查会话健康状态成功，但序列化失败: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleGetCaptchaStatus 处理查询验证状态，账号暂停时附带触发验证时的截图
+func (s *AppServer) handleGetCaptchaStatus(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 查询验证状态")
+
+	result, err := s.xiaohongshuService.CaptchaStatus(ctx)
+	if err != nil {
+		return errorResult("查询验证状态失败", err)
+	}
+
+	var screenshot string
+	if result.Block != nil {
+		screenshot = result.Block.Screenshot
+		result.Block.Screenshot = ""
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("查询验证状态成功，但序列化失败: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	contents := []MCPContent{{Type: "text", Text: string(jsonData)}}
+	if screenshot != "" {
+		contents = append(contents, MCPContent{
+			Type:     "image",
+			MimeType: "image/png",
+			Data:     strings.TrimPrefix(screenshot, "data:image/png;base64,"),
+		})
+	}
 	return &MCPToolResult{Content: contents}
 }
 
+// handleResolveCaptcha 处理启动人工验证
+func (s *AppServer) handleResolveCaptcha(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 启动人工验证")
+
+	result, err := s.xiaohongshuService.ResolveCaptcha(ctx)
+	if err != nil {
+		return errorResult("启动人工验证失败", err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: fmt.Sprintf("已在服务端打开浏览器，请在 %s 前完成验证，完成后账号 %s 自动恢复（可通过 get_captcha_status 查询结果）",
+				result.ExpiresAt.Format("2006-01-02 15:04:05"), result.Account),
+		}},
+	}
+}
+
+// handleGetQuota 处理查询操作额度
+func (s *AppServer) handleGetQuota(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 查询操作额度")
+
+	result, err := s.xiaohongshuService.QuotaUsage(ctx)
+	if err != nil {
+		return errorResult("查询操作额度失败", err)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("查询操作额度成功，但序列化失败: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
 // handlePublishContent 处理发布内容
 func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]interface{}) *MCPToolResult {
 	logrus.Info("MCP: 发布内容")
@@ -113,13 +329,7 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 	// 执行发布
 	result, err := s.xiaohongshuService.PublishContent(ctx, req)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发布失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return errorResult("发布失败", err)
 	}
 
 	resultText := fmt.Sprintf("内容发布成功: %+v", result)
@@ -148,13 +358,7 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 	}
 
 	if videoPath == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发布失败: 缺少本地视频文件路径",
-			}},
-			IsError: true,
-		}
+		return errorResult("发布失败", myerrors.Validation("缺少本地视频文件路径"))
 	}
 
 	logrus.Infof("MCP: 发布视频 - 标题: %s, 标签数量: %d", title, len(tags))
@@ -170,13 +374,7 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 	// 执行发布
 	result, err := s.xiaohongshuService.PublishVideo(ctx, req)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发布失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return errorResult("发布失败", err)
 	}
 
 	resultText := fmt.Sprintf("视频发布成功: %+v", result)
@@ -189,18 +387,17 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 }
 
 // handleListFeeds 处理获取Feeds列表
-func (s *AppServer) handleListFeeds(ctx context.Context) *MCPToolResult {
+func (s *AppServer) handleListFeeds(ctx context.Context, args ListFeedsArgs) *MCPToolResult {
 	logrus.Info("MCP: 获取Feeds列表")
 
-	result, err := s.xiaohongshuService.ListFeeds(ctx)
+	location, err := parseLocation(args.Location)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取Feeds列表失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return errorResult("获取Feeds列表失败", err)
+	}
+
+	result, err := s.xiaohongshuService.ListFeeds(ctx, location)
+	if err != nil {
+		return errorResult("获取Feeds列表失败", err)
 	}
 
 	// 格式化输出，转换为JSON字符串
@@ -228,13 +425,7 @@ func (s *AppServer) handleSearchFeeds(ctx context.Context, args SearchFeedsArgs)
 	logrus.Info("MCP: 搜索Feeds")
 
 	if args.Keyword == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "搜索Feeds失败: 缺少关键词参数",
-			}},
-			IsError: true,
-		}
+		return errorResult("搜索Feeds失败", myerrors.Validation("缺少关键词参数"))
 	}
 
 	logrus.Infof("MCP: 搜索Feeds - 关键词: %s", args.Keyword)
@@ -248,15 +439,14 @@ func (s *AppServer) handleSearchFeeds(ctx context.Context, args SearchFeedsArgs)
 		Location:    args.Filters.Location,
 	}
 
-	result, err := s.xiaohongshuService.SearchFeeds(ctx, args.Keyword, filter)
+	location, err := parseLocation(args.Location)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "搜索Feeds失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return errorResult("搜索Feeds失败", err)
+	}
+
+	result, err := s.xiaohongshuService.SearchFeeds(ctx, args.Keyword, location, args.Limit, args.Cursor, filter)
+	if err != nil {
+		return errorResult("搜索Feeds失败", err)
 	}
 
 	// 格式化输出，转换为JSON字符串
@@ -286,37 +476,19 @@ func (s *AppServer) handleGetFeedDetail(ctx context.Context, args map[string]any
 	// 解析参数
 	feedID, ok := args["feed_id"].(string)
 	if !ok || feedID == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取Feed详情失败: 缺少feed_id参数",
-			}},
-			IsError: true,
-		}
+		return errorResult("获取Feed详情失败", myerrors.Validation("缺少feed_id参数"))
 	}
 
 	xsecToken, ok := args["xsec_token"].(string)
 	if !ok || xsecToken == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取Feed详情失败: 缺少xsec_token参数",
-			}},
-			IsError: true,
-		}
+		return errorResult("获取Feed详情失败", myerrors.Validation("缺少xsec_token参数"))
 	}
 
 	logrus.Infof("MCP: 获取Feed详情 - Feed ID: %s", feedID)
 
 	result, err := s.xiaohongshuService.GetFeedDetail(ctx, feedID, xsecToken)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取Feed详情失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return errorResult("获取Feed详情失败", err)
 	}
 
 	// 格式化输出，转换为JSON字符串
@@ -346,37 +518,21 @@ func (s *AppServer) handleUserProfile(ctx context.Context, args map[string]any)
 	// 解析参数
 	userID, ok := args["user_id"].(string)
 	if !ok || userID == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取用户主页失败: 缺少user_id参数",
-			}},
-			IsError: true,
-		}
+		return errorResult("获取用户主页失败", myerrors.Validation("缺少user_id参数"))
 	}
 
 	xsecToken, ok := args["xsec_token"].(string)
 	if !ok || xsecToken == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取用户主页失败: 缺少xsec_token参数",
-			}},
-			IsError: true,
-		}
+		return errorResult("获取用户主页失败", myerrors.Validation("缺少xsec_token参数"))
 	}
 
+	limit, _ := args["limit"].(int)
+
 	logrus.Infof("MCP: 获取用户主页 - User ID: %s", userID)
 
-	result, err := s.xiaohongshuService.UserProfile(ctx, userID, xsecToken)
+	result, err := s.xiaohongshuService.UserProfile(ctx, userID, xsecToken, limit)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取用户主页失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return errorResult("获取用户主页失败", err)
 	}
 
 	// 格式化输出，转换为JSON字符串
@@ -403,11 +559,11 @@ func (s *AppServer) handleUserProfile(ctx context.Context, args map[string]any)
 func (s *AppServer) handleLikeFeed(ctx context.Context, args map[string]interface{}) *MCPToolResult {
 	feedID, ok := args["feed_id"].(string)
 	if !ok || feedID == "" {
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: 缺少feed_id参数"}}, IsError: true}
+		return errorResult("操作失败", myerrors.Validation("缺少feed_id参数"))
 	}
 	xsecToken, ok := args["xsec_token"].(string)
 	if !ok || xsecToken == "" {
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: 缺少xsec_token参数"}}, IsError: true}
+		return errorResult("操作失败", myerrors.Validation("缺少xsec_token参数"))
 	}
 	unlike, _ := args["unlike"].(bool)
 
@@ -425,7 +581,7 @@ func (s *AppServer) handleLikeFeed(ctx context.Context, args map[string]interfac
 		if unlike {
 			action = "取消点赞"
 		}
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + err.Error()}}, IsError: true}
+		return errorResult(action+"失败", err)
 	}
 
 	action := "点赞"
@@ -439,11 +595,11 @@ func (s *AppServer) handleLikeFeed(ctx context.Context, args map[string]interfac
 func (s *AppServer) handleFavoriteFeed(ctx context.Context, args map[string]interface{}) *MCPToolResult {
 	feedID, ok := args["feed_id"].(string)
 	if !ok || feedID == "" {
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: 缺少feed_id参数"}}, IsError: true}
+		return errorResult("操作失败", myerrors.Validation("缺少feed_id参数"))
 	}
 	xsecToken, ok := args["xsec_token"].(string)
 	if !ok || xsecToken == "" {
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: 缺少xsec_token参数"}}, IsError: true}
+		return errorResult("操作失败", myerrors.Validation("缺少xsec_token参数"))
 	}
 	unfavorite, _ := args["unfavorite"].(bool)
 
@@ -461,7 +617,7 @@ func (s *AppServer) handleFavoriteFeed(ctx context.Context, args map[string]inte
 		if unfavorite {
 			action = "取消收藏"
 		}
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + err.Error()}}, IsError: true}
+		return errorResult(action+"失败", err)
 	}
 
 	action := "收藏"
@@ -478,35 +634,17 @@ func (s *AppServer) handlePostComment(ctx context.Context, args map[string]inter
 	// 解析参数
 	feedID, ok := args["feed_id"].(string)
 	if !ok || feedID == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发表评论失败: 缺少feed_id参数",
-			}},
-			IsError: true,
-		}
+		return errorResult("发表评论失败", myerrors.Validation("缺少feed_id参数"))
 	}
 
 	xsecToken, ok := args["xsec_token"].(string)
 	if !ok || xsecToken == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发表评论失败: 缺少xsec_token参数",
-			}},
-			IsError: true,
-		}
+		return errorResult("发表评论失败", myerrors.Validation("缺少xsec_token参数"))
 	}
 
 	content, ok := args["content"].(string)
 	if !ok || content == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发表评论失败: 缺少content参数",
-			}},
-			IsError: true,
-		}
+		return errorResult("发表评论失败", myerrors.Validation("缺少content参数"))
 	}
 
 	logrus.Infof("MCP: 发表评论 - Feed ID: %s, 内容长度: %d", feedID, len(content))
@@ -514,13 +652,7 @@ func (s *AppServer) handlePostComment(ctx context.Context, args map[string]inter
 	// 发表评论
 	result, err := s.xiaohongshuService.PostCommentToFeed(ctx, feedID, xsecToken, content)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发表评论失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return errorResult("发表评论失败", err)
 	}
 
 	// 返回成功结果，只包含feed_id
@@ -538,23 +670,47 @@ func (s *AppServer) handleGetUserLikedFeeds(ctx context.Context) *MCPToolResult
 	logrus.Info("MCP: 获取用户点赞笔记")
 
 	result, err := s.xiaohongshuService.GetUserLikedFeeds(ctx)
+	if err != nil {
+		return errorResult("获取用户点赞笔记失败", err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "获取用户点赞笔记失败: " + err.Error(),
+				Text: fmt.Sprintf("获取用户点赞笔记成功，但序列化失败: %v", err),
 			}},
 			IsError: true,
 		}
 	}
 
-	// 格式化输出，转换为JSON字符串
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleListAccounts 处理列出账号
+func (s *AppServer) handleListAccounts(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 列出账号")
+
+	list := s.xiaohongshuService.Accounts()
+
+	result := make([]AccountInfo, 0, len(list))
+	for _, acc := range list {
+		result = append(result, AccountInfo{ID: acc.ID, Name: acc.DisplayName()})
+	}
+
 	jsonData, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: fmt.Sprintf("获取用户点赞笔记成功，但序列化失败: %v", err),
+				Text: fmt.Sprintf("列出账号成功，但序列化失败: %v", err),
 			}},
 			IsError: true,
 		}
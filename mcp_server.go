@@ -8,16 +8,23 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
 )
 
 // MCP 工具参数结构体定义
 
+// AccountArgs 只需要指定账号的工具参数
+type AccountArgs struct {
+	Account string `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
+}
+
 // PublishContentArgs 发布内容的参数
 type PublishContentArgs struct {
 	Title   string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
 	Content string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
 	Images  []string `json:"images" jsonschema:"图片路径列表（至少需要1张图片）。支持两种方式：1. HTTP/HTTPS图片链接（自动下载）；2. 本地图片绝对路径（推荐，如:/Users/user/image.jpg）"`
 	Tags    []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	Account string   `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
 }
 
 // PublishVideoArgs 发布视频的参数（仅支持本地单个视频文件）
@@ -26,12 +33,23 @@ type PublishVideoArgs struct {
 	Content string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
 	Video   string   `json:"video" jsonschema:"本地视频绝对路径（仅支持单个视频文件，如:/Users/user/video.mp4）"`
 	Tags    []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	Account string   `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
+}
+
+// ListFeedsArgs 获取首页 Feeds 的参数
+type ListFeedsArgs struct {
+	Location string `json:"location,omitempty" jsonschema:"模拟的地理位置（可选）：城市名如 上海，或 纬度,经度 如 31.23,121.47"`
+	Account  string `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
 }
 
 // SearchFeedsArgs 搜索内容的参数
 type SearchFeedsArgs struct {
-	Keyword string       `json:"keyword" jsonschema:"搜索关键词"`
-	Filters FilterOption `json:"filters,omitempty" jsonschema:"筛选选项"`
+	Keyword  string       `json:"keyword" jsonschema:"搜索关键词"`
+	Filters  FilterOption `json:"filters,omitempty" jsonschema:"筛选选项"`
+	Location string       `json:"location,omitempty" jsonschema:"模拟的地理位置（可选）：城市名如 上海，或 纬度,经度 如 31.23,121.47，配合 filters.location 的同城、附近筛选使用"`
+	Limit    int          `json:"limit,omitempty" jsonschema:"最多返回的笔记数（可选），默认20，最大100，超过首屏数量时会滚动结果页加载更多"`
+	Cursor   string       `json:"cursor,omitempty" jsonschema:"分页游标（可选），传入上一次结果中的 next_cursor 继续获取，需要使用相同的关键词和筛选条件"`
+	Account  string       `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
 }
 
 // FilterOption 筛选选项结构体
@@ -47,12 +65,15 @@ type FilterOption struct {
 type FeedDetailArgs struct {
 	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
 	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Account   string `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
 }
 
 // UserProfileArgs 获取用户主页的参数
 type UserProfileArgs struct {
 	UserID    string `json:"user_id" jsonschema:"小红书用户ID，从Feed列表获取"`
 	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"最多返回的笔记数（可选），默认只返回主页首屏的笔记，最大300，超过首屏数量时会滚动主页加载更多"`
+	Account   string `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
 }
 
 // PostCommentArgs 发表评论的参数
@@ -60,6 +81,7 @@ type PostCommentArgs struct {
 	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
 	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
 	Content   string `json:"content" jsonschema:"评论内容"`
+	Account   string `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
 }
 
 // LikeFeedArgs 点赞参数
@@ -67,6 +89,7 @@ type LikeFeedArgs struct {
 	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
 	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
 	Unlike    bool   `json:"unlike,omitempty" jsonschema:"是否取消点赞，true为取消点赞，false或未设置则为点赞"`
+	Account   string `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
 }
 
 // FavoriteFeedArgs 收藏参数
@@ -74,11 +97,24 @@ type FavoriteFeedArgs struct {
 	FeedID     string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
 	XsecToken  string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
 	Unfavorite bool   `json:"unfavorite,omitempty" jsonschema:"是否取消收藏，true为取消收藏，false或未设置则为收藏"`
+	Account    string `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
 }
 
 // GetUserLikedFeedsArgs 获取用户点赞笔记的参数
 type GetUserLikedFeedsArgs struct {
-	// 无参数，获取当前登录用户的所有点赞笔记
+	Account string `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
+}
+
+// SessionHealthArgs 会话健康检查的参数
+type SessionHealthArgs struct {
+	Account string `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号"`
+	Refresh bool   `json:"refresh,omitempty" jsonschema:"是否打开浏览器实时检查登录状态，默认只解析 cookies 过期时间"`
+}
+
+// LoginSessionArgs 扫码登录会话的参数
+type LoginSessionArgs struct {
+	Account   string `json:"account,omitempty" jsonschema:"账号ID（可选），不填使用默认账号，需要与获取二维码时的账号一致"`
+	SessionID string `json:"session_id" jsonschema:"扫码登录会话ID，从 get_login_qrcode 的返回结果获取"`
 }
 
 // InitMCPServer 初始化 MCP Server
@@ -138,10 +174,10 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "check_login_status",
-			Description: "检查小红书登录状态",
+			Description: "检查小红书登录状态，已登录时返回当前账号的用户ID、小红书号、昵称和头像",
 		},
-		withPanicRecovery("check_login_status", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
-			result := appServer.handleCheckLoginStatus(ctx)
+		withPanicRecovery("check_login_status", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleCheckLoginStatus(accounts.WithAccount(ctx, args.Account))
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -150,10 +186,10 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "get_login_qrcode",
-			Description: "获取登录二维码（返回 Base64 图片和超时时间）",
+			Description: "获取登录二维码（返回 Base64 图片、超时时间和会话ID），扫码后可通过 get_login_qrcode_status 查询登录结果",
 		},
-		withPanicRecovery("get_login_qrcode", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
-			result := appServer.handleGetLoginQrcode(ctx)
+		withPanicRecovery("get_login_qrcode", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleGetLoginQrcode(accounts.WithAccount(ctx, args.Account))
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -172,7 +208,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 				"images":  convertStringsToInterfaces(args.Images),
 				"tags":    convertStringsToInterfaces(args.Tags),
 			}
-			result := appServer.handlePublishContent(ctx, argsMap)
+			result := appServer.handlePublishContent(accounts.WithAccount(ctx, args.Account), argsMap)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -181,10 +217,10 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "list_feeds",
-			Description: "获取首页 Feeds 列表",
+			Description: "获取首页 Feeds 列表，可以指定地理位置获取不同城市的推荐",
 		},
-		withPanicRecovery("list_feeds", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
-			result := appServer.handleListFeeds(ctx)
+		withPanicRecovery("list_feeds", func(ctx context.Context, req *mcp.CallToolRequest, args ListFeedsArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListFeeds(accounts.WithAccount(ctx, args.Account), args)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -196,7 +232,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 			Description: "搜索小红书内容（需要已登录）",
 		},
 		withPanicRecovery("search_feeds", func(ctx context.Context, req *mcp.CallToolRequest, args SearchFeedsArgs) (*mcp.CallToolResult, any, error) {
-			result := appServer.handleSearchFeeds(ctx, args)
+			result := appServer.handleSearchFeeds(accounts.WithAccount(ctx, args.Account), args)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -212,7 +248,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 				"feed_id":    args.FeedID,
 				"xsec_token": args.XsecToken,
 			}
-			result := appServer.handleGetFeedDetail(ctx, argsMap)
+			result := appServer.handleGetFeedDetail(accounts.WithAccount(ctx, args.Account), argsMap)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -227,8 +263,9 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 			argsMap := map[string]interface{}{
 				"user_id":    args.UserID,
 				"xsec_token": args.XsecToken,
+				"limit":      args.Limit,
 			}
-			result := appServer.handleUserProfile(ctx, argsMap)
+			result := appServer.handleUserProfile(accounts.WithAccount(ctx, args.Account), argsMap)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -245,7 +282,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 				"xsec_token": args.XsecToken,
 				"content":    args.Content,
 			}
-			result := appServer.handlePostComment(ctx, argsMap)
+			result := appServer.handlePostComment(accounts.WithAccount(ctx, args.Account), argsMap)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -263,7 +300,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 				"video":   args.Video,
 				"tags":    convertStringsToInterfaces(args.Tags),
 			}
-			result := appServer.handlePublishVideo(ctx, argsMap)
+			result := appServer.handlePublishVideo(accounts.WithAccount(ctx, args.Account), argsMap)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -280,7 +317,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 				"xsec_token": args.XsecToken,
 				"unlike":     args.Unlike,
 			}
-			result := appServer.handleLikeFeed(ctx, argsMap)
+			result := appServer.handleLikeFeed(accounts.WithAccount(ctx, args.Account), argsMap)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -297,7 +334,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 				"xsec_token": args.XsecToken,
 				"unfavorite": args.Unfavorite,
 			}
-			result := appServer.handleFavoriteFeed(ctx, argsMap)
+			result := appServer.handleFavoriteFeed(accounts.WithAccount(ctx, args.Account), argsMap)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -309,12 +346,120 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 			Description: "获取当前登录用户所有点赞的笔记列表，返回笔记标题、链接、作者等详细信息",
 		},
 		withPanicRecovery("get_user_liked_feeds", func(ctx context.Context, req *mcp.CallToolRequest, args GetUserLikedFeedsArgs) (*mcp.CallToolResult, any, error) {
-			result := appServer.handleGetUserLikedFeeds(ctx)
+			result := appServer.handleGetUserLikedFeeds(accounts.WithAccount(ctx, args.Account))
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 13: 列出账号
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_accounts",
+			Description: "列出服务端已配置的所有小红书账号，其它工具可通过 account 参数指定账号",
+		},
+		withPanicRecovery("list_accounts", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListAccounts(ctx)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 14: 会话健康检查
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "check_session_health",
+			Description: "检查登录会话健康状态：有效、即将过期（剩余小时数）、已过期或未登录，需要重新登录时请调用 get_login_qrcode",
+		},
+		withPanicRecovery("check_session_health", func(ctx context.Context, req *mcp.CallToolRequest, args SessionHealthArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleCheckSessionHealth(accounts.WithAccount(ctx, args.Account), args.Refresh)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 15: 查询扫码登录状态
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_login_qrcode_status",
+			Description: "查询扫码登录会话状态：pending 等待扫码、scanned 已扫码待确认、succeeded 登录成功、expired 二维码过期、failed 失败、cancelled 已取消",
+		},
+		withPanicRecovery("get_login_qrcode_status", func(ctx context.Context, req *mcp.CallToolRequest, args LoginSessionArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleGetLoginQrcodeStatus(accounts.WithAccount(ctx, args.Account), args.SessionID)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 16: 取消扫码登录
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "cancel_login_qrcode",
+			Description: "取消进行中的扫码登录会话并关闭对应的浏览器",
+		},
+		withPanicRecovery("cancel_login_qrcode", func(ctx context.Context, req *mcp.CallToolRequest, args LoginSessionArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleCancelLoginQrcode(accounts.WithAccount(ctx, args.Account), args.SessionID)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 17: 刷新登录二维码
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "refresh_login_qrcode",
+			Description: "二维码过期后重新获取登录二维码，会话ID保持不变",
+		},
+		withPanicRecovery("refresh_login_qrcode", func(ctx context.Context, req *mcp.CallToolRequest, args LoginSessionArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleRefreshLoginQrcode(accounts.WithAccount(ctx, args.Account), args.SessionID)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 18: 退出登录
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "logout",
+			Description: "退出小红书登录：在网站上登出并删除服务端保存的 cookies，之后需要重新扫码登录",
+		},
+		withPanicRecovery("logout", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleLogout(accounts.WithAccount(ctx, args.Account))
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 19: 查询操作额度
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_quota",
+			Description: "查询账号发布、评论、点赞、收藏等操作在最近 1 小时和 24 小时内的已用次数和剩余额度，额度用完时这些操作会返回 QUOTA_EXCEEDED",
+		},
+		withPanicRecovery("get_quota", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleGetQuota(accounts.WithAccount(ctx, args.Account))
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 20: 查询验证状态
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_captcha_status",
+			Description: "查询账号是否因触发验证码而暂停，暂停时返回触发验证时的页面截图和人工验证进度。操作返回 CAPTCHA_REQUIRED 后调用",
+		},
+		withPanicRecovery("get_captcha_status", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleGetCaptchaStatus(accounts.WithAccount(ctx, args.Account))
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 21: 人工完成验证
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "resolve_captcha",
+			Description: "在服务端打开有界面的浏览器进入验证页面，由用户人工完成滑块等验证，完成后账号自动恢复，需要服务端有图形界面",
+		},
+		withPanicRecovery("resolve_captcha", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleResolveCaptcha(accounts.WithAccount(ctx, args.Account))
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
 
-	logrus.Infof("Registered %d MCP tools", 12)
+	logrus.Infof("Registered %d MCP tools", 21)
 }
 
 // convertToMCPResult 将自定义的 MCPToolResult 转换为官方 SDK 的格式
@@ -343,8 +488,9 @@ func convertToMCPResult(result *MCPToolResult) *mcp.CallToolResult {
 	}
 
 	return &mcp.CallToolResult{
-		Content: contents,
-		IsError: result.IsError,
+		Content:           contents,
+		IsError:           result.IsError,
+		StructuredContent: result.StructuredContent,
 	}
 }
 
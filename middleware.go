@@ -1,10 +1,14 @@
 package main
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 )
 
 // corsMiddleware CORS 中间件
@@ -12,7 +16,7 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Xhs-Account, X-Admin-Token")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -32,3 +36,56 @@ func errorHandlingMiddleware() gin.HandlerFunc {
 			"服务器内部错误", recovered)
 	})
 }
+
+// accountMiddleware 账号选择中间件。
+// 通过 query 参数 account 或请求头 X-Xhs-Account 指定账号，未指定时使用默认账号。
+func accountMiddleware(registry *accounts.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Query("account")
+		if id == "" {
+			id = c.GetHeader("X-Xhs-Account")
+		}
+
+		acc, err := registry.Get(id)
+		if err != nil {
+			c.Set("account", id)
+			respondError(c, http.StatusNotFound, "ACCOUNT_NOT_FOUND",
+				"账号不存在", err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Set("account", acc.ID)
+		c.Request = c.Request.WithContext(accounts.WithAccount(c.Request.Context(), acc.ID))
+
+		c.Next()
+	}
+}
+
+// adminAuthMiddleware 管理接口鉴权中间件。
+// 需要通过 Authorization: Bearer <token> 或请求头 X-Admin-Token 提供管理令牌，未配置令牌时接口禁用。
+func adminAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := configs.GetAdminToken()
+		if token == "" {
+			respondError(c, http.StatusForbidden, "ADMIN_DISABLED",
+				"管理接口未启用", "请通过 -admin-token 参数或环境变量 XHS_ADMIN_TOKEN 配置管理令牌")
+			c.Abort()
+			return
+		}
+
+		provided := c.GetHeader("X-Admin-Token")
+		if auth := c.GetHeader("Authorization"); provided == "" && strings.HasPrefix(auth, "Bearer ") {
+			provided = strings.TrimPrefix(auth, "Bearer ")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			respondError(c, http.StatusUnauthorized, "UNAUTHORIZED",
+				"管理令牌无效", nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
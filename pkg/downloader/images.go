@@ -13,6 +13,7 @@ import (
 
 	"github.com/h2non/filetype"
 	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 )
 
 // ImageDownloader 图片下载器
@@ -21,19 +22,43 @@ type ImageDownloader struct {
 	httpClient *http.Client
 }
 
+// Option 图片下载器的配置项
+type Option func(*ImageDownloader)
+
+// WithProxy 通过代理下载图片，与浏览器使用相同的出口，为空时不使用代理。
+// 代理地址无效时下载直接失败，不会绕过代理直连。
+func WithProxy(proxy string) Option {
+	return func(d *ImageDownloader) {
+		if proxy == "" {
+			return
+		}
+
+		u, err := configs.ParseProxy(proxy)
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = func(*http.Request) (*url.URL, error) {
+			return u, err
+		}
+		d.httpClient.Transport = transport
+	}
+}
+
 // NewImageDownloader 创建图片下载器
-func NewImageDownloader(savePath string) *ImageDownloader {
+func NewImageDownloader(savePath string, opts ...Option) *ImageDownloader {
 	// 确保保存目录存在
 	if err := os.MkdirAll(savePath, 0755); err != nil {
 		panic(fmt.Sprintf("failed to create save path: %v", err))
 	}
 
-	return &ImageDownloader{
+	d := &ImageDownloader{
 		savePath: savePath,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 // DownloadImage 下载图片
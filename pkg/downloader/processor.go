@@ -11,10 +11,10 @@ type ImageProcessor struct {
 	downloader *ImageDownloader
 }
 
-// NewImageProcessor 创建图片处理器
-func NewImageProcessor() *ImageProcessor {
+// NewImageProcessor 创建图片处理器，opts 用于配置图片下载
+func NewImageProcessor(opts ...Option) *ImageProcessor {
 	return &ImageProcessor{
-		downloader: NewImageDownloader(configs.GetImagesPath()),
+		downloader: NewImageDownloader(configs.GetImagesPath(), opts...),
 	}
 }
 
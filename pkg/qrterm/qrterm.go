@@ -0,0 +1,162 @@
+// Package qrterm 将二维码图片转换为终端中可扫描的 Unicode 字符画。
+package qrterm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// quietZone 渲染时在二维码四周保留的空白模块数
+const quietZone = 2
+
+// DecodeDataURL 解析 data:image/...;base64,... 格式的图片
+func DecodeDataURL(src string) (image.Image, error) {
+	if !strings.HasPrefix(src, "data:image/") {
+		return nil, errors.New("qrcode src is not a data url")
+	}
+
+	idx := strings.Index(src, ",")
+	if idx < 0 || !strings.Contains(src[:idx], ";base64") {
+		return nil, errors.New("qrcode data url is not base64 encoded")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(src[idx+1:])
+	if err != nil {
+		return nil, errors.Wrap(err, "decode qrcode base64 failed")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "decode qrcode image failed")
+	}
+
+	return img, nil
+}
+
+// Modules 从二维码图片中提取模块矩阵，true 表示深色模块。
+// 通过左上角定位图案（7 个模块宽）推算模块大小，再对每个模块的中心采样。
+func Modules(img image.Image) ([][]bool, error) {
+	b := img.Bounds()
+
+	minX, minY, maxX, maxY := b.Max.X, b.Max.Y, b.Min.X-1, b.Min.Y-1
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if !isDark(img.At(x, y)) {
+				continue
+			}
+			minX, minY = min(minX, x), min(minY, y)
+			maxX, maxY = max(maxX, x), max(maxY, y)
+		}
+	}
+	if maxX < minX || maxY < minY {
+		return nil, errors.New("no qrcode found in image")
+	}
+
+	// 定位图案的第一行是 7 个连续的深色模块
+	run := 0
+	for x := minX; x <= maxX && isDark(img.At(x, minY)); x++ {
+		run++
+	}
+
+	width := float64(maxX - minX + 1)
+	moduleSize := float64(run) / 7
+	if moduleSize < 1 {
+		return nil, errors.New("qrcode finder pattern not found")
+	}
+
+	// 二维码边长为 17+4*version 个模块，取最接近的合法值
+	version := math.Round((width/moduleSize - 17) / 4)
+	if version < 1 || version > 40 {
+		return nil, errors.Errorf("unexpected qrcode size: %.1f modules", width/moduleSize)
+	}
+	n := int(version)*4 + 17
+
+	size := width / float64(n)
+	height := float64(maxY - minY + 1)
+	sizeY := height / float64(n)
+
+	modules := make([][]bool, n)
+	for row := 0; row < n; row++ {
+		modules[row] = make([]bool, n)
+		y := minY + int((float64(row)+0.5)*sizeY)
+		for col := 0; col < n; col++ {
+			x := minX + int((float64(col)+0.5)*size)
+			modules[row][col] = isDark(img.At(x, y))
+		}
+	}
+
+	return modules, nil
+}
+
+// Render 使用半高方块字符输出二维码，每行字符对应两行模块。
+// 默认以浅色字符绘制空白部分，适合深色背景的终端；invert 为 true 时适合浅色背景。
+func Render(w io.Writer, modules [][]bool, invert bool) error {
+	n := len(modules)
+	total := n + quietZone*2
+
+	// dark 返回带空白边框的坐标是否为深色模块
+	dark := func(row, col int) bool {
+		row, col = row-quietZone, col-quietZone
+		if row < 0 || col < 0 || row >= n || col >= n {
+			return false
+		}
+		return modules[row][col]
+	}
+
+	var sb strings.Builder
+	for row := 0; row < total; row += 2 {
+		for col := 0; col < total; col++ {
+			// top/bottom 为 true 表示该半格不绘制，超出最后一行的部分不绘制
+			top := dark(row, col) != invert
+			bottom := row+1 >= total || dark(row+1, col) != invert
+
+			switch {
+			case !top && !bottom:
+				sb.WriteString("█")
+			case !top && bottom:
+				sb.WriteString("▀")
+			case top && !bottom:
+				sb.WriteString("▄")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// RenderDataURL 解析 data url 格式的二维码图片并输出到终端
+func RenderDataURL(w io.Writer, src string, invert bool) error {
+	img, err := DecodeDataURL(src)
+	if err != nil {
+		return err
+	}
+
+	modules, err := Modules(img)
+	if err != nil {
+		return err
+	}
+
+	return Render(w, modules, invert)
+}
+
+func isDark(c color.Color) bool {
+	r, g, b, a := c.RGBA()
+	if a < 0x8000 {
+		return false
+	}
+	lum := (299*r + 587*g + 114*b) / 1000
+	return lum < 0x8000
+}
@@ -0,0 +1,105 @@
+package qrterm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testModules 生成带三个定位图案和固定花纹的 21x21 矩阵
+func testModules() [][]bool {
+	const n = 21
+	m := make([][]bool, n)
+	for i := range m {
+		m[i] = make([]bool, n)
+	}
+
+	finder := func(r0, c0 int) {
+		for r := 0; r < 7; r++ {
+			for c := 0; c < 7; c++ {
+				ring := r == 0 || r == 6 || c == 0 || c == 6
+				center := r >= 2 && r <= 4 && c >= 2 && c <= 4
+				m[r0+r][c0+c] = ring || center
+			}
+		}
+	}
+	finder(0, 0)
+	finder(0, n-7)
+	finder(n-7, 0)
+
+	for r := 8; r < n; r++ {
+		for c := 8; c < n; c++ {
+			m[r][c] = (r*c)%3 == 0
+		}
+	}
+	return m
+}
+
+func dataURL(t *testing.T, modules [][]bool, scale, border int) string {
+	n := len(modules)
+	size := n*scale + border*2
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	for r, row := range modules {
+		for c, dark := range row {
+			if !dark {
+				continue
+			}
+			for y := 0; y < scale; y++ {
+				for x := 0; x < scale; x++ {
+					img.Set(border+c*scale+x, border+r*scale+y, color.Black)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestModules(t *testing.T) {
+	want := testModules()
+
+	img, err := DecodeDataURL(dataURL(t, want, 6, 15))
+	require.NoError(t, err)
+
+	got, err := Modules(img)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestRender(t *testing.T) {
+	modules := testModules()
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, modules, false))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	total := len(modules) + quietZone*2
+	require.Len(t, lines, (total+1)/2)
+	for _, line := range lines {
+		require.Equal(t, total, len([]rune(line)))
+	}
+
+	// 第一行全部是空白边框
+	require.Equal(t, strings.Repeat("█", total), lines[0])
+}
+
+func TestDecodeDataURLInvalid(t *testing.T) {
+	_, err := DecodeDataURL("https://example.com/qrcode.png")
+	require.Error(t, err)
+
+	_, err = DecodeDataURL("data:image/png;base64,!!!")
+	require.Error(t, err)
+}
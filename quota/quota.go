@@ -0,0 +1,333 @@
+// Package quota 按账号限制发布、评论、点赞等写操作的频次。
+//
+// 每类操作可以设置每小时、每天的次数上限，以及两次操作之间的最小间隔和随机抖动，
+// 避免短时间内大量操作触发风控。计数只保存在内存中，服务重启后清零。
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+// Action 受限制的操作类型
+type Action string
+
+const (
+	Publish  Action = "publish"  // 发布图文或视频
+	Comment  Action = "comment"  // 发表评论
+	Like     Action = "like"     // 点赞、取消点赞
+	Favorite Action = "favorite" // 收藏、取消收藏
+	Follow   Action = "follow"   // 关注、取消关注
+)
+
+const (
+	hour = time.Hour
+	day  = 24 * time.Hour
+)
+
+// Policy 一类操作的限制，为 0 的字段表示不限制
+type Policy struct {
+	Hourly      int           // 最近 1 小时内的次数上限
+	Daily       int           // 最近 24 小时内的次数上限
+	MinInterval time.Duration // 两次操作之间的最小间隔
+	Jitter      time.Duration // 在最小间隔之上额外增加 [0, Jitter) 的随机等待
+}
+
+type policyJSON struct {
+	Hourly      int    `json:"hourly,omitempty"`
+	Daily       int    `json:"daily,omitempty"`
+	MinInterval string `json:"min_interval,omitempty"`
+	Jitter      string `json:"jitter,omitempty"`
+}
+
+// UnmarshalJSON 时长使用 "30s"、"5m" 这样的字符串
+func (p *Policy) UnmarshalJSON(data []byte) error {
+	var v policyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	p.Hourly, p.Daily = v.Hourly, v.Daily
+	p.MinInterval, p.Jitter = 0, 0
+
+	var err error
+	if v.MinInterval != "" {
+		if p.MinInterval, err = time.ParseDuration(v.MinInterval); err != nil {
+			return errors.Wrap(err, "parse min_interval")
+		}
+	}
+	if v.Jitter != "" {
+		if p.Jitter, err = time.ParseDuration(v.Jitter); err != nil {
+			return errors.Wrap(err, "parse jitter")
+		}
+	}
+	return nil
+}
+
+// Policies 各类操作的限制，未配置的操作不限制
+type Policies map[Action]Policy
+
+// DefaultPolicies 默认限制，参考正常用户的操作频率
+func DefaultPolicies() Policies {
+	return Policies{
+		Publish:  {Hourly: 3, Daily: 10, MinInterval: 5 * time.Minute, Jitter: time.Minute},
+		Comment:  {Hourly: 20, Daily: 100, MinInterval: 30 * time.Second, Jitter: 30 * time.Second},
+		Like:     {Hourly: 60, Daily: 300, MinInterval: 3 * time.Second, Jitter: 5 * time.Second},
+		Favorite: {Hourly: 60, Daily: 300, MinInterval: 3 * time.Second, Jitter: 5 * time.Second},
+		Follow:   {Hourly: 20, Daily: 100, MinInterval: 10 * time.Second, Jitter: 10 * time.Second},
+	}
+}
+
+// LoadPolicies 从 JSON 文件加载限制，文件中的操作覆盖默认值。path 为空时返回默认限制。
+//
+//	{"like": {"hourly": 30, "daily": 200, "min_interval": "5s", "jitter": "10s"}}
+func LoadPolicies(path string) (Policies, error) {
+	policies := DefaultPolicies()
+	if path == "" {
+		return policies, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read quota config")
+	}
+
+	var f map[Action]Policy
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, errors.Wrap(err, "parse quota config")
+	}
+	for action, p := range f {
+		if _, ok := policies[action]; !ok {
+			return nil, fmt.Errorf("unknown quota action %q", action)
+		}
+		policies[action] = p
+	}
+	return policies, nil
+}
+
+// Usage 一类操作的用量，上限为 0 时表示不限制，对应的剩余次数为 -1
+type Usage struct {
+	Action          Action     `json:"action"`
+	HourlyLimit     int        `json:"hourly_limit"`
+	HourlyUsed      int        `json:"hourly_used"`
+	HourlyRemaining int        `json:"hourly_remaining"`
+	DailyLimit      int        `json:"daily_limit"`
+	DailyUsed       int        `json:"daily_used"`
+	DailyRemaining  int        `json:"daily_remaining"`
+	MinInterval     string     `json:"min_interval"`
+	NextAllowedAt   *time.Time `json:"next_allowed_at,omitempty"`
+}
+
+// Limiter 按账号记录操作历史并执行限制
+type Limiter struct {
+	policies Policies
+
+	mu      sync.Mutex
+	history map[string]map[Action][]time.Time // 每个账号每类操作的执行时间，按时间排序
+
+	now    func() time.Time
+	jitter func(time.Duration) time.Duration
+}
+
+// NewLimiter 创建限制器
+func NewLimiter(policies Policies) *Limiter {
+	return &Limiter{
+		policies: policies,
+		history:  make(map[string]map[Action][]time.Time),
+		now:      time.Now,
+		jitter: func(max time.Duration) time.Duration {
+			if max <= 0 {
+				return 0
+			}
+			return time.Duration(rand.Int63n(int64(max)))
+		},
+	}
+}
+
+// Take 为账号执行一次操作占用额度。
+// 超过次数上限时返回 QUOTA_EXCEEDED 错误；距离上次操作不足最小间隔时等待，等待期间 ctx 结束则归还额度。
+func (l *Limiter) Take(ctx context.Context, account string, action Action) error {
+	_, err := l.take(ctx, account, action)
+	return err
+}
+
+// take 占用额度并等待到可以执行的时间，返回记入历史的时间，用于归还额度
+func (l *Limiter) take(ctx context.Context, account string, action Action) (time.Time, error) {
+	at, err := l.reserve(account, action)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	wait := at.Sub(l.now())
+	if wait <= 0 {
+		return at, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return at, nil
+	case <-ctx.Done():
+		l.cancel(account, action, at)
+		return time.Time{}, errors.Wrapf(ctx.Err(), "wait for %s interval", action)
+	}
+}
+
+// Do 占用一次额度后执行 fn。fn 在访问网站之前就失败时（见 myerrors.IsNotExecuted，如排队被拒绝、
+// 账号等待验证、参数校验失败）归还额度；已经访问网站后的失败（如上传超时、提交后找不到元素）
+// 仍计入次数上限和最小间隔，避免反复失败的操作不受限制地请求网站。
+func (l *Limiter) Do(ctx context.Context, account string, action Action, fn func() error) error {
+	at, err := l.take(ctx, account, action)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		if myerrors.IsNotExecuted(err) {
+			l.cancel(account, action, at)
+		}
+		return err
+	}
+	return nil
+}
+
+// reserve 检查次数上限，返回本次操作可以执行的时间并记入历史
+func (l *Limiter) reserve(account string, action Action) (time.Time, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	p := l.policies[action]
+	times := l.prune(account, action, now)
+
+	if err := exceeded(action, "每小时", p.Hourly, hour, times, now); err != nil {
+		return time.Time{}, err
+	}
+	if err := exceeded(action, "每天", p.Daily, day, times, now); err != nil {
+		return time.Time{}, err
+	}
+
+	at := now
+	if n := len(times); n > 0 && p.MinInterval > 0 {
+		if next := times[n-1].Add(p.MinInterval + l.jitter(p.Jitter)); next.After(at) {
+			at = next
+		}
+	}
+
+	l.history[account][action] = append(times, at)
+	return at, nil
+}
+
+// exceeded 检查 window 内的次数是否已达上限，达到时返回带恢复时间的错误
+func exceeded(action Action, name string, limit int, window time.Duration, times []time.Time, now time.Time) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	inWindow := countSince(times, now.Add(-window))
+	if inWindow < limit {
+		return nil
+	}
+
+	// 窗口内最早的一次操作过期后才能恢复额度
+	resetAt := times[len(times)-inWindow].Add(window)
+	return myerrors.New(myerrors.CodeQuotaExceeded,
+		fmt.Sprintf("%s %s操作次数已达上限 %d，%s 后恢复", action, name, limit, resetAt.Format("2006-01-02 15:04:05")))
+}
+
+// cancel 归还一次尚未执行的操作占用的额度
+func (l *Limiter) cancel(account string, action Action, at time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	times := l.history[account][action]
+	for i := len(times) - 1; i >= 0; i-- {
+		if times[i].Equal(at) {
+			l.history[account][action] = append(times[:i], times[i+1:]...)
+			return
+		}
+	}
+}
+
+// prune 删除 24 小时之前的记录，返回剩余的历史
+func (l *Limiter) prune(account string, action Action, now time.Time) []time.Time {
+	byAction, ok := l.history[account]
+	if !ok {
+		byAction = make(map[Action][]time.Time)
+		l.history[account] = byAction
+	}
+
+	times := byAction[action]
+	if n := len(times) - countSince(times, now.Add(-day)); n > 0 {
+		times = append(times[:0], times[n:]...)
+	}
+	byAction[action] = times
+	return times
+}
+
+// countSince since 之后（不含）的次数，times 按时间排序
+func countSince(times []time.Time, since time.Time) int {
+	i := sort.Search(len(times), func(i int) bool {
+		return times[i].After(since)
+	})
+	return len(times) - i
+}
+
+// Usage 返回账号各类操作的用量，按操作名排序
+func (l *Limiter) Usage(account string) []Usage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	actions := make([]Action, 0, len(l.policies))
+	for action := range l.policies {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
+
+	usages := make([]Usage, 0, len(actions))
+	for _, action := range actions {
+		p := l.policies[action]
+		times := l.prune(account, action, now)
+
+		u := Usage{
+			Action:      action,
+			HourlyLimit: p.Hourly,
+			HourlyUsed:  countSince(times, now.Add(-hour)),
+			DailyLimit:  p.Daily,
+			DailyUsed:   len(times),
+			MinInterval: p.MinInterval.String(),
+		}
+		u.HourlyRemaining = remaining(p.Hourly, u.HourlyUsed)
+		u.DailyRemaining = remaining(p.Daily, u.DailyUsed)
+		if n := len(times); n > 0 && p.MinInterval > 0 {
+			if next := times[n-1].Add(p.MinInterval); next.After(now) {
+				u.NextAllowedAt = &next
+			}
+		}
+		usages = append(usages, u)
+	}
+	return usages
+}
+
+func remaining(limit, used int) int {
+	if limit <= 0 {
+		return -1
+	}
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
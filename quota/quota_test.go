@@ -0,0 +1,133 @@
+package quota
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+func newTestLimiter(policies Policies, now *time.Time) *Limiter {
+	l := NewLimiter(policies)
+	l.now = func() time.Time { return *now }
+	l.jitter = func(time.Duration) time.Duration { return 0 }
+	return l
+}
+
+func TestHourlyAndDailyLimits(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(Policies{Like: {Hourly: 2, Daily: 3}}, &now)
+
+	require.NoError(t, l.Take(context.Background(), "a", Like))
+	require.NoError(t, l.Take(context.Background(), "a", Like))
+
+	err := l.Take(context.Background(), "a", Like)
+	require.Equal(t, myerrors.CodeQuotaExceeded, myerrors.CodeOf(err))
+	require.Contains(t, err.Error(), "2026-10-17 13:00:00")
+
+	// 其他账号和其他操作不受影响
+	require.NoError(t, l.Take(context.Background(), "b", Like))
+	require.NoError(t, l.Take(context.Background(), "a", Comment))
+
+	now = now.Add(time.Hour + time.Second)
+	require.NoError(t, l.Take(context.Background(), "a", Like))
+
+	err = l.Take(context.Background(), "a", Like)
+	require.Equal(t, myerrors.CodeQuotaExceeded, myerrors.CodeOf(err))
+
+	usage := l.Usage("a")
+	require.Len(t, usage, 1)
+	require.Equal(t, 1, usage[0].HourlyUsed)
+	require.Equal(t, 1, usage[0].HourlyRemaining)
+	require.Equal(t, 0, usage[0].DailyRemaining)
+
+	now = now.Add(24 * time.Hour)
+	usage = l.Usage("a")
+	require.Equal(t, 0, usage[0].DailyUsed)
+	require.Equal(t, 3, usage[0].DailyRemaining)
+}
+
+func TestMinInterval(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(Policies{Comment: {MinInterval: 30 * time.Second}}, &now)
+
+	at, err := l.reserve("a", Comment)
+	require.NoError(t, err)
+	require.Equal(t, now, at)
+
+	at, err = l.reserve("a", Comment)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(30*time.Second), at)
+
+	// 等待中取消时归还额度，下一次仍然排在第二个位置
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, l.Take(ctx, "a", Comment), context.Canceled)
+
+	at, err = l.reserve("a", Comment)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(60*time.Second), at)
+
+	usage := l.Usage("a")
+	require.Equal(t, -1, usage[0].HourlyRemaining)
+	require.NotNil(t, usage[0].NextAllowedAt)
+}
+
+func TestDoReleasesQuotaOnFailure(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(Policies{Like: {Hourly: 1, MinInterval: time.Minute}}, &now)
+	ctx := context.Background()
+
+	// 排队被拒绝或操作失败时不计入次数，也不影响最小间隔
+	busy := myerrors.New(myerrors.CodeBusy, "busy")
+	require.Equal(t, busy, l.Do(ctx, "a", Like, func() error { return busy }))
+	usage := l.Usage("a")
+	require.Equal(t, 0, usage[0].HourlyUsed)
+	require.Nil(t, usage[0].NextAllowedAt)
+
+	require.NoError(t, l.Do(ctx, "a", Like, func() error { return nil }))
+	usage = l.Usage("a")
+	require.Equal(t, 1, usage[0].HourlyUsed)
+	require.Equal(t, 0, usage[0].HourlyRemaining)
+
+	called := false
+	err := l.Do(ctx, "a", Like, func() error { called = true; return nil })
+	require.Equal(t, myerrors.CodeQuotaExceeded, myerrors.CodeOf(err))
+	require.False(t, called)
+}
+
+func TestDoKeepsQuotaAfterExecution(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(Policies{Publish: {Hourly: 2, MinInterval: time.Minute}}, &now)
+	ctx := context.Background()
+
+	// 排队时取消、账号等待验证等没有访问网站的失败归还额度
+	require.ErrorIs(t, l.Do(ctx, "a", Publish, func() error { return myerrors.NotExecuted(context.Canceled) }), context.Canceled)
+	require.Equal(t, 0, l.Usage("a")[0].HourlyUsed)
+
+	// 已经提交后的失败仍计入次数和最小间隔
+	err := l.Do(ctx, "a", Publish, func() error { return myerrors.ErrUploadTimeout })
+	require.ErrorIs(t, err, myerrors.ErrUploadTimeout)
+	usage := l.Usage("a")
+	require.Equal(t, 1, usage[0].HourlyUsed)
+	require.NotNil(t, usage[0].NextAllowedAt)
+	require.Equal(t, now.Add(time.Minute), *usage[0].NextAllowedAt)
+}
+
+func TestLoadPolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quota.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"like": {"hourly": 5, "min_interval": "2s"}}`), 0600))
+
+	policies, err := LoadPolicies(path)
+	require.NoError(t, err)
+	require.Equal(t, Policy{Hourly: 5, MinInterval: 2 * time.Second}, policies[Like])
+	require.Equal(t, DefaultPolicies()[Publish], policies[Publish])
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"retweet": {"hourly": 5}}`), 0600))
+	_, err = LoadPolicies(path)
+	require.Error(t, err)
+}
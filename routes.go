@@ -37,9 +37,21 @@ func setupRoutes(appServer *AppServer) *gin.Engine {
 
 	// API 路由组
 	api := router.Group("/api/v1")
+	api.Use(accountMiddleware(appServer.xiaohongshuService.accounts))
 	{
+		api.GET("/accounts", appServer.listAccountsHandler)
+		api.GET("/queue", appServer.queueStatsHandler)
+		api.GET("/quota", appServer.quotaHandler)
 		api.GET("/login/status", appServer.checkLoginStatusHandler)
+		api.DELETE("/login", appServer.logoutHandler)
 		api.GET("/login/qrcode", appServer.getLoginQrcodeHandler)
+		api.GET("/login/qrcode/:id", appServer.getLoginSessionHandler)
+		api.DELETE("/login/qrcode/:id", appServer.cancelLoginSessionHandler)
+		api.POST("/login/qrcode/:id/refresh", appServer.refreshLoginQrcodeHandler)
+		api.GET("/login/health", appServer.loginHealthHandler)
+		api.GET("/captcha", appServer.captchaStatusHandler)
+		api.POST("/captcha/resolve", appServer.resolveCaptchaHandler)
+		api.DELETE("/captcha/resolve", appServer.cancelCaptchaHandler)
 		api.POST("/publish", appServer.publishHandler)
 		api.POST("/publish_video", appServer.publishVideoHandler)
 		api.GET("/feeds/list", appServer.listFeedsHandler)
@@ -52,5 +64,14 @@ func setupRoutes(appServer *AppServer) *gin.Engine {
 		api.GET("/user/liked-feeds", appServer.getUserLikedFeedsHandler)
 	}
 
+	// 管理接口，需要管理令牌
+	admin := api.Group("", adminAuthMiddleware())
+	{
+		admin.POST("/cookies/import", appServer.importCookiesHandler)
+		admin.GET("/cookies/export", appServer.exportCookiesHandler)
+		admin.GET("/diagnostics", appServer.listDiagnosticsHandler)
+		admin.GET("/diagnostics/:id", appServer.downloadDiagnosticsHandler)
+	}
+
 	return router
 }
@@ -4,27 +4,191 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"github.com/xpzouying/headless_browser"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/diagnostics"
+	"github.com/xpzouying/xiaohongshu-mcp/dispatcher"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/quota"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu/user_likes"
 )
 
 // XiaohongshuService 小红书业务服务
-type XiaohongshuService struct{}
+type XiaohongshuService struct {
+	accounts *accounts.Registry
+
+	logins  *LoginSessionManager
+	captcha *CaptchaManager // 触发验证码而暂停的账号和人工验证会话
+
+	mu    sync.Mutex
+	pools map[string]*browser.Pool // 每个账号一个浏览器池
+
+	dispatcher *dispatcher.Dispatcher // 按账号串行写操作、限制读操作并发
+	quotas     *quota.Limiter         // 按账号限制写操作的次数和间隔
+
+	diagnostics *diagnostics.Store // 操作失败时保存页面现场，为 nil 时不保存
+}
 
 // NewXiaohongshuService 创建小红书服务实例
-func NewXiaohongshuService() *XiaohongshuService {
-	return &XiaohongshuService{}
+func NewXiaohongshuService(registry *accounts.Registry, policies quota.Policies) *XiaohongshuService {
+	s := &XiaohongshuService{
+		accounts: registry,
+		pools:    make(map[string]*browser.Pool),
+		dispatcher: dispatcher.New(dispatcher.Config{
+			MaxReads: configs.GetMaxConcurrentReads(),
+			MaxQueue: configs.GetMaxQueueDepth(),
+		}),
+		quotas: quota.NewLimiter(policies),
+		diagnostics: diagnostics.NewStore(configs.GetDiagnosticsDir(),
+			configs.GetDiagnosticsRetention(), configs.GetDiagnosticsMaxBundles()),
+	}
+	s.logins = newLoginSessionManager(s)
+	s.captcha = newCaptchaManager(s)
+
+	return s
+}
+
+// Close 释放服务持有的浏览器资源
+func (s *XiaohongshuService) Close() {
+	s.logins.Close()
+	s.captcha.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, pool := range s.pools {
+		pool.Close()
+		delete(s.pools, id)
+	}
+}
+
+// Accounts 返回所有已配置的账号
+func (s *XiaohongshuService) Accounts() []*accounts.Account {
+	return s.accounts.List()
+}
+
+// account 根据 context 中的账号 ID 获取账号，未指定时使用默认账号
+func (s *XiaohongshuService) account(ctx context.Context) (*accounts.Account, error) {
+	return s.accounts.Get(accounts.FromContext(ctx))
+}
+
+// poolFor 获取账号对应的浏览器池，不存在时创建
+func (s *XiaohongshuService) poolFor(acc *accounts.Account) *browser.Pool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pool, ok := s.pools[acc.ID]; ok {
+		return pool
+	}
+
+	size := configs.GetPoolSize()
+	if acc.Settings.PoolSize > 0 {
+		size = acc.Settings.PoolSize
+	}
+	// 持久化 profile 同时只能被一个浏览器使用
+	if usesProfile(acc) && size > 1 {
+		logrus.Infof("account %s uses a persistent profile, pool size limited to 1", acc.ID)
+		size = 1
+	}
+
+	pool := browser.NewPool(browser.PoolConfig{
+		Size:        size,
+		IdleTimeout: configs.GetPoolIdleTimeout(),
+	}, func() (*browser.Browser, error) {
+		return launchBrowser(acc)
+	})
+	s.pools[acc.ID] = pool
+
+	return pool
+}
+
+// drainPool 关闭账号池中的浏览器，使后续调用重新加载 cookies
+func (s *XiaohongshuService) drainPool(acc *accounts.Account) {
+	s.mu.Lock()
+	pool, ok := s.pools[acc.ID]
+	s.mu.Unlock()
+
+	if ok {
+		pool.Drain()
+	}
+}
+
+// holdProfile 使用持久化 profile 时独占账号的浏览器池：等待进行中的操作结束并关闭池中的浏览器，
+// 将 profile 交给扫码登录、人工验证的浏览器。这些浏览器关闭后调用 release，池才恢复借出。
+func (s *XiaohongshuService) holdProfile(ctx context.Context, acc *accounts.Account) (release func(), err error) {
+	if !usesProfile(acc) {
+		return func() {}, nil
+	}
+	return s.poolFor(acc).Exclusive(ctx)
+}
+
+// QueueStats 返回当前账号的排队状态和浏览器池状态
+func (s *XiaohongshuService) QueueStats(ctx context.Context) (*QueueStatsResponse, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueueStatsResponse{
+		Stats: s.dispatcher.Stats(acc.ID),
+		Pool:  s.poolFor(acc).Stats(),
+	}, nil
+}
+
+// CaptchaStatus 返回当前账号是否因触发验证码而暂停，以及人工验证会话的状态
+func (s *XiaohongshuService) CaptchaStatus(ctx context.Context) (*CaptchaStatusResponse, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.captcha.Status(acc), nil
+}
+
+// ResolveCaptcha 打开有界面的浏览器供人工完成验证，完成后恢复账号
+func (s *XiaohongshuService) ResolveCaptcha(ctx context.Context) (*CaptchaSessionInfo, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.captcha.Resolve(ctx, acc)
+}
+
+// CancelCaptcha 取消当前账号进行中的人工验证会话
+func (s *XiaohongshuService) CancelCaptcha(ctx context.Context) (*CaptchaStatusResponse, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.captcha.Cancel(acc), nil
+}
+
+// QuotaUsage 返回当前账号各类操作的额度用量
+func (s *XiaohongshuService) QuotaUsage(ctx context.Context) (*QuotaUsageResponse, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuotaUsageResponse{
+		Account: acc.ID,
+		Usages:  s.quotas.Usage(acc.ID),
+	}, nil
 }
 
 // PublishRequest 发布请求
@@ -35,17 +199,53 @@ type PublishRequest struct {
 	Tags    []string `json:"tags,omitempty"`
 }
 
+// QueueStatsResponse 排队状态响应
+type QueueStatsResponse struct {
+	dispatcher.Stats
+	Pool browser.PoolStats `json:"pool"`
+}
+
+// QuotaUsageResponse 操作额度响应
+type QuotaUsageResponse struct {
+	Account string        `json:"account"`
+	Usages  []quota.Usage `json:"usages"`
+}
+
 // LoginStatusResponse 登录状态响应
 type LoginStatusResponse struct {
+	Account    string `json:"account"`
 	IsLoggedIn bool   `json:"is_logged_in"`
-	Username   string `json:"username,omitempty"`
+	Username   string `json:"username,omitempty"` // 与 nickname 相同，保留以兼容旧的调用方
+	UserID     string `json:"user_id,omitempty"`
+	RedID      string `json:"red_id,omitempty"`
+	Nickname   string `json:"nickname,omitempty"`
+	Avatar     string `json:"avatar,omitempty"`
 }
 
 // LoginQrcodeResponse 登录扫码二维码
 type LoginQrcodeResponse struct {
-	Timeout    string `json:"timeout"`
+	Account    string             `json:"account"`
+	SessionID  string             `json:"session_id,omitempty"`
+	Status     LoginSessionStatus `json:"status,omitempty"`
+	Timeout    string             `json:"timeout"`
+	ExpiresAt  *time.Time         `json:"expires_at,omitempty"`
+	IsLoggedIn bool               `json:"is_logged_in"`
+	Img        string             `json:"img,omitempty"`
+}
+
+// LogoutResponse 退出登录响应
+type LogoutResponse struct {
+	Account        string `json:"account"`
+	SiteLoggedOut  bool   `json:"site_logged_out"`
+	CookiesCleared bool   `json:"cookies_cleared"`
+	Message        string `json:"message"`
+}
+
+// ImportCookiesResponse 导入 cookies 响应
+type ImportCookiesResponse struct {
+	Account    string `json:"account"`
+	Count      int    `json:"count"`
 	IsLoggedIn bool   `json:"is_logged_in"`
-	Img        string `json:"img,omitempty"`
 }
 
 // PublishResponse 发布响应
@@ -76,8 +276,11 @@ type PublishVideoResponse struct {
 
 // FeedsListResponse Feeds列表响应
 type FeedsListResponse struct {
-	Feeds []xiaohongshu.Feed `json:"feeds"`
-	Count int                `json:"count"`
+	Feeds    []xiaohongshu.Feed   `json:"feeds"`
+	Count    int                  `json:"count"`
+	Location *browser.Geolocation `json:"location,omitempty"` // 获取时模拟的地理位置
+
+	NextCursor string `json:"next_cursor,omitempty"` // 搜索结果的下一页游标，为空表示没有更多结果
 }
 
 // UserProfileResponse 用户主页响应
@@ -89,86 +292,212 @@ type UserProfileResponse struct {
 
 // CheckLoginStatus 检查登录状态
 func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatusResponse, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	var status *xiaohongshu.LoginStatus
+	err := s.withBrowserPage(ctx, dispatcher.Read, func(page *rod.Page) error {
+		loginAction := xiaohongshu.NewLogin(page)
 
-	loginAction := xiaohongshu.NewLogin(page)
+		var err error
+		status, err = loginAction.CheckLoginStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	isLoggedIn, err := loginAction.CheckLoginStatus(ctx)
+	acc, err := s.account(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	response := &LoginStatusResponse{
-		IsLoggedIn: isLoggedIn,
-		Username:   configs.Username,
+		Account:    acc.ID,
+		IsLoggedIn: status.IsLoggedIn,
+	}
+	if user := status.User; user != nil {
+		response.Username = user.Nickname
+		response.UserID = user.UserID
+		response.RedID = user.RedID
+		response.Nickname = user.Nickname
+		response.Avatar = user.Avatar
 	}
 
 	return response, nil
 }
 
-// GetLoginQrcode 获取登录的扫码二维码
+// GetLoginQrcode 获取登录的扫码二维码。
+// 返回的 session_id 可用于查询扫码结果、取消或刷新二维码；账号已有进行中的会话时返回该会话。
 func (s *XiaohongshuService) GetLoginQrcode(ctx context.Context) (*LoginQrcodeResponse, error) {
-	b := newBrowser()
-	page := b.NewPage()
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	deferFunc := func() {
-		_ = page.Close()
-		b.Close()
+	sess, loggedIn, err := s.logins.Start(ctx, acc)
+	if err != nil {
+		return nil, err
 	}
 
-	loginAction := xiaohongshu.NewLogin(page)
+	return newLoginQrcodeResponse(acc.ID, sess, loggedIn), nil
+}
 
-	img, loggedIn, err := loginAction.FetchQrcodeImage(ctx)
-	if err != nil || loggedIn {
-		defer deferFunc()
+// Logout 退出登录：在网站上登出，删除保存的 cookies，并关闭账号池中的浏览器。
+// 网站登出失败时仍会清除本地会话，失败原因记录在返回结果中。
+func (s *XiaohongshuService) Logout(ctx context.Context) (*LogoutResponse, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
 	}
+
+	// 进行中的扫码登录会在成功后写入 cookies，先取消
+	s.logins.CancelAccount(acc.ID)
+
+	resp := &LogoutResponse{Account: acc.ID}
+
+	err = s.withBrowserPage(ctx, dispatcher.Write, func(page *rod.Page) error {
+		loggedOut, err := xiaohongshu.NewLogin(page).Logout(ctx)
+		// 使当前及其它借出的浏览器失效，避免登出前的 cookies 被写回
+		s.drainPool(acc)
+		resp.SiteLoggedOut = loggedOut
+		return err
+	})
 	if err != nil {
+		logrus.Warnf("logout on site failed for account %s: %v", acc.ID, err)
+		resp.Message = fmt.Sprintf("网站登出失败，已清除本地会话: %v", err)
+	}
+
+	if err := acc.Cookier().ClearCookies(); err != nil {
 		return nil, err
 	}
+	s.drainPool(acc)
 
-	timeout := 4 * time.Minute
+	resp.CookiesCleared = true
+	if resp.Message == "" {
+		resp.Message = "已退出登录"
+	}
 
-	if !loggedIn {
-		go func() {
-			ctxTimeout, cancel := context.WithTimeout(context.Background(), timeout)
-			defer cancel()
-			defer deferFunc()
+	return resp, nil
+}
 
-			if loginAction.WaitForLogin(ctxTimeout) {
-				if er := saveCookies(page); er != nil {
-					logrus.Errorf("failed to save cookies: %v", er)
-				}
-			}
-		}()
+// ErrImportedCookiesNotLoggedIn 导入的 cookies 无法登录
+var ErrImportedCookiesNotLoggedIn = errors.New("导入的 cookies 未处于登录状态")
+
+// ImportCookies 导入 cookies 并通过检查登录状态验证。
+// 验证失败时恢复导入前的 cookies。
+func (s *XiaohongshuService) ImportCookies(ctx context.Context, data []byte, format cookies.Format) (*ImportCookiesResponse, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return &LoginQrcodeResponse{
-		Timeout: func() string {
-			if loggedIn {
-				return "0s"
+	cookier := acc.Cookier()
+	previous, prevErr := cookier.LoadCookies()
+
+	cks, err := cookies.ImportTo(cookier, data, format)
+	if err != nil {
+		return nil, err
+	}
+	s.drainPool(acc)
+
+	status, err := s.CheckLoginStatus(ctx)
+	if err == nil && !status.IsLoggedIn {
+		err = ErrImportedCookiesNotLoggedIn
+	}
+	if err != nil {
+		restore := func() error {
+			if prevErr != nil {
+				return cookier.ClearCookies()
 			}
-			return timeout.String()
-		}(),
-		Img:        img,
-		IsLoggedIn: loggedIn,
+			return cookier.SaveCookies(previous)
+		}
+		if er := restore(); er != nil {
+			logrus.Errorf("failed to restore cookies for account %s: %v", acc.ID, er)
+		}
+		s.drainPool(acc)
+		return nil, errors.Wrap(err, "验证导入的 cookies 失败，已恢复原有 cookies")
+	}
+
+	return &ImportCookiesResponse{
+		Account:    acc.ID,
+		Count:      len(cks),
+		IsLoggedIn: true,
 	}, nil
 }
 
+// ExportCookies 按指定格式导出账号保存的 cookies
+func (s *XiaohongshuService) ExportCookies(ctx context.Context, format cookies.Format) ([]byte, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return cookies.ExportFrom(acc.Cookier(), format)
+}
+
+// GetLoginSession 查询当前账号的扫码登录会话状态
+func (s *XiaohongshuService) GetLoginSession(ctx context.Context, id string) (*LoginSessionInfo, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.logins.Get(acc.ID, id)
+}
+
+// CancelLoginSession 取消当前账号的扫码登录会话
+func (s *XiaohongshuService) CancelLoginSession(ctx context.Context, id string) (*LoginSessionInfo, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.logins.Cancel(acc.ID, id)
+}
+
+// RefreshLoginQrcode 为当前账号的登录会话重新获取二维码，会话 ID 保持不变
+func (s *XiaohongshuService) RefreshLoginQrcode(ctx context.Context, id string) (*LoginQrcodeResponse, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sess, loggedIn, err := s.logins.Refresh(ctx, acc, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLoginQrcodeResponse(acc.ID, sess, loggedIn), nil
+}
+
+func newLoginQrcodeResponse(accountID string, sess *LoginSessionInfo, loggedIn bool) *LoginQrcodeResponse {
+	if loggedIn {
+		return &LoginQrcodeResponse{
+			Account:    accountID,
+			Timeout:    "0s",
+			IsLoggedIn: true,
+		}
+	}
+
+	return &LoginQrcodeResponse{
+		Account:   accountID,
+		SessionID: sess.ID,
+		Status:    sess.Status,
+		Timeout:   time.Until(sess.ExpiresAt).Round(time.Second).String(),
+		ExpiresAt: &sess.ExpiresAt,
+		Img:       sess.Img,
+	}
+}
+
 // PublishContent 发布内容
 func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
 	// 验证标题长度
 	// 小红书限制：最大40个单位长度
 	// 中文/日文/韩文占2个单位，英文/数字占1个单位
 	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
-		return nil, fmt.Errorf("标题长度超过限制")
+		return nil, myerrors.Validation("标题长度超过限制")
 	}
 
 	// 处理图片：下载URL图片或使用本地路径
-	imagePaths, err := s.processImages(req.Images)
+	imagePaths, err := s.processImages(ctx, req.Images)
 	if err != nil {
 		return nil, err
 	}
@@ -197,42 +526,40 @@ func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishReq
 	return response, nil
 }
 
-// processImages 处理图片列表，支持URL下载和本地路径
-func (s *XiaohongshuService) processImages(images []string) ([]string, error) {
-	processor := downloader.NewImageProcessor()
+// processImages 处理图片列表，支持URL下载和本地路径。图片通过当前账号的代理下载。
+func (s *XiaohongshuService) processImages(ctx context.Context, images []string) ([]string, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	processor := downloader.NewImageProcessor(downloader.WithProxy(acc.Proxy()))
 	return processor.ProcessImages(images)
 }
 
 // publishContent 执行内容发布
 func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) error {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	return s.withQuota(ctx, quota.Publish, func(page *rod.Page) error {
+		action := xiaohongshu.NewPublishImageAction(page)
 
-	action, err := xiaohongshu.NewPublishImageAction(page)
-	if err != nil {
-		return err
-	}
-
-	// 执行发布
-	return action.Publish(ctx, content)
+		// 执行发布
+		return action.Publish(ctx, content)
+	})
 }
 
 // PublishVideo 发布视频（本地文件）
 func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideoRequest) (*PublishVideoResponse, error) {
 	// 标题长度校验
 	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
-		return nil, fmt.Errorf("标题长度超过限制")
+		return nil, myerrors.Validation("标题长度超过限制")
 	}
 
 	// 本地视频文件校验
 	if req.Video == "" {
-		return nil, fmt.Errorf("必须提供本地视频文件")
+		return nil, myerrors.Validation("必须提供本地视频文件")
 	}
 	if _, err := os.Stat(req.Video); err != nil {
-		return nil, fmt.Errorf("视频文件不存在或不可访问: %v", err)
+		return nil, myerrors.Validation(fmt.Sprintf("视频文件不存在或不可访问: %v", err))
 	}
 
 	// 构建发布内容
@@ -259,81 +586,100 @@ func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideo
 
 // publishVideo 执行视频发布
 func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) error {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
-
-	action, err := xiaohongshu.NewPublishVideoAction(page)
-	if err != nil {
-		return err
-	}
-
-	return action.PublishVideo(ctx, content)
+	return s.withQuota(ctx, quota.Publish, func(page *rod.Page) error {
+		action := xiaohongshu.NewPublishVideoAction(page)
+		return action.PublishVideo(ctx, content)
+	})
 }
 
-// ListFeeds 获取Feeds列表
-func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+// ListFeeds 获取Feeds列表，location 不为空时以该地理位置访问
+func (s *XiaohongshuService) ListFeeds(ctx context.Context, location *browser.Geolocation) (*FeedsListResponse, error) {
+	var feeds []xiaohongshu.Feed
+	err := s.withBrowserPage(ctx, dispatcher.Read, func(page *rod.Page) error {
+		if err := setGeolocation(page, location); err != nil {
+			return err
+		}
 
-	// 创建 Feeds 列表 action
-	action := xiaohongshu.NewFeedsListAction(page)
+		// 创建 Feeds 列表 action
+		action := xiaohongshu.NewFeedsListAction(page)
 
-	// 获取 Feeds 列表
-	feeds, err := action.GetFeedsList(ctx)
+		// 获取 Feeds 列表
+		var err error
+		feeds, err = action.GetFeedsList(ctx)
+		return err
+	})
 	if err != nil {
 		logrus.Errorf("获取 Feeds 列表失败: %v", err)
 		return nil, err
 	}
 
 	response := &FeedsListResponse{
-		Feeds: feeds,
-		Count: len(feeds),
+		Feeds:    feeds,
+		Count:    len(feeds),
+		Location: location,
 	}
 
 	return response, nil
 }
 
-func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string, filters ...xiaohongshu.FilterOption) (*FeedsListResponse, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+// SearchFeeds 搜索Feeds，location 不为空时以该地理位置搜索，用于"同城"、"附近"筛选。
+// limit 为本次最多返回的笔记数（0 使用默认值），cursor 为上一次返回的 next_cursor，为空时从头开始。
+func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string, location *browser.Geolocation, limit int, cursor string, filters ...xiaohongshu.FilterOption) (*FeedsListResponse, error) {
+	var result *xiaohongshu.SearchPage
+	err := s.withBrowserPage(ctx, dispatcher.Read, func(page *rod.Page) error {
+		if err := setGeolocation(page, location); err != nil {
+			return err
+		}
 
-	action := xiaohongshu.NewSearchAction(page)
+		action := xiaohongshu.NewSearchAction(page)
 
-	feeds, err := action.Search(ctx, keyword, filters...)
+		var err error
+		result, err = action.SearchWithCursor(ctx, keyword, limit, cursor, filters...)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	response := &FeedsListResponse{
-		Feeds: feeds,
-		Count: len(feeds),
+		Feeds:      result.Feeds,
+		Count:      len(result.Feeds),
+		Location:   location,
+		NextCursor: result.NextCursor,
 	}
 
 	return response, nil
 }
 
-// GetFeedDetail 获取Feed详情
-func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToken string) (*FeedDetailResponse, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+// parseLocation 解析请求中的地理位置参数，格式错误时返回 VALIDATION_FAILED
+func parseLocation(s string) (*browser.Geolocation, error) {
+	location, err := browser.ParseGeolocation(s)
+	if err != nil {
+		return nil, myerrors.Validation(err.Error())
+	}
+	return location, nil
+}
 
-	// 创建 Feed 详情 action
-	action := xiaohongshu.NewFeedDetailAction(page)
+// setGeolocation 在页面导航之前设置模拟的地理位置，location 为空时不处理
+func setGeolocation(page *rod.Page, location *browser.Geolocation) error {
+	if location == nil {
+		return nil
+	}
+	return browser.SetGeolocation(page, location)
+}
 
-	// 获取 Feed 详情
-	result, err := action.GetFeedDetail(ctx, feedID, xsecToken)
+// GetFeedDetail 获取Feed详情
+func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToken string) (*FeedDetailResponse, error) {
+	var result *xiaohongshu.FeedDetailResponse
+	err := s.withBrowserPage(ctx, dispatcher.Read, func(page *rod.Page) error {
+		// 创建 Feed 详情 action
+		action := xiaohongshu.NewFeedDetailAction(page)
+
+		// 获取 Feed 详情
+		var err error
+		result, err = action.GetFeedDetail(ctx, feedID, xsecToken)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -346,17 +692,16 @@ func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToke
 	return response, nil
 }
 
-// UserProfile 获取用户信息
-func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken string) (*UserProfileResponse, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
-
-	action := xiaohongshu.NewUserProfileAction(page)
+// UserProfile 获取用户信息，limit 为最多返回的笔记数（0 只返回主页首屏的笔记）
+func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken string, limit int) (*UserProfileResponse, error) {
+	var result *xiaohongshu.UserProfileResponse
+	err := s.withBrowserPage(ctx, dispatcher.Read, func(page *rod.Page) error {
+		action := xiaohongshu.NewUserProfileAction(page)
 
-	result, err := action.UserProfile(ctx, userID, xsecToken)
+		var err error
+		result, err = action.UserProfile(ctx, userID, xsecToken, limit)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -372,15 +717,11 @@ func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken
 
 // PostCommentToFeed 发表评论到Feed
 func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsecToken, content string) (*PostCommentResponse, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
-
-	action := xiaohongshu.NewCommentFeedAction(page)
-
-	if err := action.PostComment(ctx, feedID, xsecToken, content); err != nil {
+	err := s.withQuota(ctx, quota.Comment, func(page *rod.Page) error {
+		action := xiaohongshu.NewCommentFeedAction(page)
+		return action.PostComment(ctx, feedID, xsecToken, content)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -389,14 +730,11 @@ func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsec
 
 // LikeFeed 点赞笔记
 func (s *XiaohongshuService) LikeFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
-
-	action := xiaohongshu.NewLikeAction(page)
-	if err := action.Like(ctx, feedID, xsecToken); err != nil {
+	err := s.withQuota(ctx, quota.Like, func(page *rod.Page) error {
+		action := xiaohongshu.NewLikeAction(page)
+		return action.Like(ctx, feedID, xsecToken)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &ActionResult{FeedID: feedID, Success: true, Message: "点赞成功或已点赞"}, nil
@@ -404,14 +742,11 @@ func (s *XiaohongshuService) LikeFeed(ctx context.Context, feedID, xsecToken str
 
 // UnlikeFeed 取消点赞笔记
 func (s *XiaohongshuService) UnlikeFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
-
-	action := xiaohongshu.NewLikeAction(page)
-	if err := action.Unlike(ctx, feedID, xsecToken); err != nil {
+	err := s.withQuota(ctx, quota.Like, func(page *rod.Page) error {
+		action := xiaohongshu.NewLikeAction(page)
+		return action.Unlike(ctx, feedID, xsecToken)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &ActionResult{FeedID: feedID, Success: true, Message: "取消点赞成功或未点赞"}, nil
@@ -419,14 +754,11 @@ func (s *XiaohongshuService) UnlikeFeed(ctx context.Context, feedID, xsecToken s
 
 // FavoriteFeed 收藏笔记
 func (s *XiaohongshuService) FavoriteFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
-
-	action := xiaohongshu.NewFavoriteAction(page)
-	if err := action.Favorite(ctx, feedID, xsecToken); err != nil {
+	err := s.withQuota(ctx, quota.Favorite, func(page *rod.Page) error {
+		action := xiaohongshu.NewFavoriteAction(page)
+		return action.Favorite(ctx, feedID, xsecToken)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &ActionResult{FeedID: feedID, Success: true, Message: "收藏成功或已收藏"}, nil
@@ -434,24 +766,49 @@ func (s *XiaohongshuService) FavoriteFeed(ctx context.Context, feedID, xsecToken
 
 // UnfavoriteFeed 取消收藏笔记
 func (s *XiaohongshuService) UnfavoriteFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
-
-	action := xiaohongshu.NewFavoriteAction(page)
-	if err := action.Unfavorite(ctx, feedID, xsecToken); err != nil {
+	err := s.withQuota(ctx, quota.Favorite, func(page *rod.Page) error {
+		action := xiaohongshu.NewFavoriteAction(page)
+		return action.Unfavorite(ctx, feedID, xsecToken)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &ActionResult{FeedID: feedID, Success: true, Message: "取消收藏成功或未收藏"}, nil
 }
 
-func newBrowser() *headless_browser.Browser {
-	return browser.NewBrowser(configs.IsHeadless(), browser.WithBinPath(configs.GetBinPath()))
+func newBrowser(acc *accounts.Account) *browser.Browser {
+	headless := configs.IsHeadless()
+	if acc.Settings.Headless != nil {
+		headless = *acc.Settings.Headless
+	}
+
+	return browser.NewBrowser(headless,
+		browser.WithBinPath(configs.GetBinPath()),
+		browser.WithCookier(acc.Cookier()),
+		browser.WithProxy(acc.Proxy()),
+		browser.WithRemoteURL(configs.GetBrowserURL()),
+		browser.WithProfile(acc.ProfileDir()),
+		browser.WithDevice(acc.Settings.Device),
+	)
 }
 
-func saveCookies(page *rod.Page) error {
+// usesProfile 账号是否在本地浏览器中使用持久化 profile，连接远程浏览器时 profile 不生效
+func usesProfile(acc *accounts.Account) bool {
+	return acc.ProfileDir() != "" && configs.GetBrowserURL() == ""
+}
+
+// launchBrowser 启动账号对应的浏览器，将启动时的 panic 转换为错误
+func launchBrowser(acc *accounts.Account) (b *browser.Browser, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("launch browser panic: %v", r)
+		}
+	}()
+
+	return newBrowser(acc), nil
+}
+
+func saveCookies(page *rod.Page, cookieLoader cookies.Cookier) error {
 	cks, err := page.Browser().GetCookies()
 	if err != nil {
 		return err
@@ -462,19 +819,161 @@ func saveCookies(page *rod.Page) error {
 		return err
 	}
 
-	cookieLoader := cookies.NewLoadCookie(cookies.GetCookiesFilePath())
 	return cookieLoader.SaveCookies(data)
 }
 
-// withBrowserPage 执行需要浏览器页面的操作的通用函数
-func withBrowserPage(fn func(*rod.Page) error) error {
-	b := newBrowser()
-	defer b.Close()
+// withBrowserPage 按操作类型在当前账号的队列中排队，再从浏览器池中借出页面执行操作，结束后归还
+func (s *XiaohongshuService) withBrowserPage(ctx context.Context, kind dispatcher.Kind, fn func(*rod.Page) error) error {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 触发验证码的账号暂停操作，直到人工完成验证
+	if err := s.captcha.blockedErr(acc.ID); err != nil {
+		return myerrors.NotExecuted(err)
+	}
+
+	// 排队被拒绝或排队时取消的请求没有访问网站
+	started := false
+	err = s.dispatcher.Do(ctx, acc.ID, kind, func() error {
+		started = true
+		return s.runOnPage(ctx, acc, fn)
+	})
+	if err != nil && !started {
+		return myerrors.NotExecuted(err)
+	}
+	return err
+}
+
+// withQuota 占用当前账号的操作额度，按最小间隔等待后再执行写操作，还没有访问网站就失败时归还额度
+func (s *XiaohongshuService) withQuota(ctx context.Context, action quota.Action, fn func(*rod.Page) error) error {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.quotas.Do(ctx, acc.ID, action, func() error {
+		return s.withBrowserPage(ctx, dispatcher.Write, fn)
+	})
+}
+
+// runOnPage 从账号的浏览器池中借出页面执行操作，结束后归还
+func (s *XiaohongshuService) runOnPage(ctx context.Context, acc *accounts.Account, fn func(*rod.Page) error) error {
+	lease, err := s.poolFor(acc).Acquire(ctx)
+	if err != nil {
+		return myerrors.NotExecuted(err)
+	}
+	defer lease.Release()
+
+	defer func() {
+		// 操作中途 panic 的浏览器状态不可信，不再复用
+		if r := recover(); r != nil {
+			lease.Discard()
+			panic(r)
+		}
+	}()
+
+	err = fn(lease.Page())
+	if err != nil {
+		err = s.captureDiagnostics(lease.Page(), acc, err)
+	}
+	if myerrors.CodeOf(err) == myerrors.CodeCaptchaRequired {
+		s.captcha.Block(acc, lease.Page(), err)
+	}
+
+	// 网站在浏览过程中可能会轮换 cookies，写回存储以延长会话。
+	// 过期的实例持有的是登录/登出之前的会话，不能覆盖新的 cookies。
+	if !lease.Stale() {
+		syncCookies(lease.Page(), acc)
+	}
+
+	return err
+}
+
+// captureDiagnostics 保存操作失败时的页面现场，并在错误中附上诊断包 ID。
+// 参数校验失败和调用方取消的请求不保存。
+func (s *XiaohongshuService) captureDiagnostics(page *rod.Page, acc *accounts.Account, err error) error {
+	if s.diagnostics == nil ||
+		myerrors.CodeOf(err) == myerrors.CodeValidationFailed ||
+		errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	bundle, saveErr := s.diagnostics.Save(diagnostics.Bundle{
+		Account: acc.ID,
+		Step:    myerrors.StepOf(err),
+		Error:   err.Error(),
+	}, diagnostics.Capture(page))
+	if saveErr != nil {
+		logrus.Warnf("failed to save diagnostics for account %s: %v", acc.ID, saveErr)
+		return err
+	}
+
+	logrus.Infof("diagnostics %s saved for failed action %s", bundle.ID, bundle.Step)
+	return myerrors.WithDiagnostics(err, bundle.ID)
+}
+
+// ListDiagnostics 列出当前账号的诊断包
+func (s *XiaohongshuService) ListDiagnostics(ctx context.Context) ([]diagnostics.Bundle, error) {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.diagnostics == nil {
+		return []diagnostics.Bundle{}, nil
+	}
+
+	all, err := s.diagnostics.List()
+	if err != nil {
+		return nil, err
+	}
+
+	bundles := []diagnostics.Bundle{}
+	for _, b := range all {
+		if b.Account == acc.ID {
+			bundles = append(bundles, b)
+		}
+	}
+	return bundles, nil
+}
+
+// WriteDiagnostics 将当前账号的诊断包打包为 zip 写入 w，其他账号的诊断包视为不存在
+func (s *XiaohongshuService) WriteDiagnostics(ctx context.Context, w io.Writer, id string) error {
+	acc, err := s.account(ctx)
+	if err != nil {
+		return err
+	}
+	if s.diagnostics == nil {
+		return diagnostics.ErrNotFound
+	}
+
+	bundle, err := s.diagnostics.Get(id)
+	if err != nil {
+		return err
+	}
+	if bundle.Account != acc.ID {
+		return diagnostics.ErrNotFound
+	}
+	return s.diagnostics.WriteZip(w, id)
+}
 
-	page := b.NewPage()
-	defer page.Close()
+// syncCookies 将浏览器中发生变化的 cookies 写回账号的存储
+func syncCookies(page *rod.Page, acc *accounts.Account) {
+	cks, err := page.Browser().GetCookies()
+	if err != nil {
+		logrus.Warnf("failed to read cookies for account %s: %v", acc.ID, err)
+		return
+	}
 
-	return fn(page)
+	written, err := cookies.Sync(acc.Cookier(), cks)
+	if err != nil {
+		logrus.Warnf("failed to write back cookies for account %s: %v", acc.ID, err)
+		return
+	}
+	if written {
+		logrus.Debugf("cookies of account %s refreshed", acc.ID)
+	}
 }
 
 // GetMyProfile 获取当前登录用户的个人信息
@@ -482,7 +981,7 @@ func (s *XiaohongshuService) GetMyProfile(ctx context.Context) (*UserProfileResp
 	var result *xiaohongshu.UserProfileResponse
 	var err error
 
-	err = withBrowserPage(func(page *rod.Page) error {
+	err = s.withBrowserPage(ctx, dispatcher.Read, func(page *rod.Page) error {
 		action := xiaohongshu.NewUserProfileAction(page)
 		result, err = action.GetMyProfileViaSidebar(ctx)
 		return err
@@ -506,7 +1005,7 @@ func (s *XiaohongshuService) GetUserLikedFeeds(ctx context.Context) (*UserLikedF
 	var result *user_likes.UserLikesResponse
 	var err error
 
-	err = withBrowserPage(func(page *rod.Page) error {
+	err = s.withBrowserPage(ctx, dispatcher.Read, func(page *rod.Page) error {
 		action := user_likes.NewUserLikesAction(page)
 		result, err = action.GetUserLikedNotes(ctx)
 		return err
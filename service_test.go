@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/diagnostics"
+	"github.com/xpzouying/xiaohongshu-mcp/quota"
+)
+
+func TestDiagnosticsScopedToAccount(t *testing.T) {
+	a := &accounts.Account{ID: "a", CookiePath: filepath.Join(t.TempDir(), "a.json")}
+	b := &accounts.Account{ID: "b", CookiePath: filepath.Join(t.TempDir(), "b.json")}
+	registry, err := accounts.NewRegistry("a", a, b)
+	require.NoError(t, err)
+	s := NewXiaohongshuService(registry, quota.DefaultPolicies())
+	t.Cleanup(s.Close)
+
+	s.diagnostics = diagnostics.NewStore(t.TempDir(), time.Hour, 10)
+	bundle, err := s.diagnostics.Save(diagnostics.Bundle{Account: "a", Step: "like/click"}, &diagnostics.Snapshot{HTML: "<html></html>"})
+	require.NoError(t, err)
+
+	ctxA := accounts.WithAccount(context.Background(), "a")
+	ctxB := accounts.WithAccount(context.Background(), "b")
+
+	var buf bytes.Buffer
+	require.NoError(t, s.WriteDiagnostics(ctxA, &buf, bundle.ID))
+	require.NotZero(t, buf.Len())
+
+	// 其他账号既列不出也下载不到
+	bundles, err := s.ListDiagnostics(ctxB)
+	require.NoError(t, err)
+	require.Empty(t, bundles)
+	require.ErrorIs(t, s.WriteDiagnostics(ctxB, &bytes.Buffer{}, bundle.ID), diagnostics.ErrNotFound)
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+)
+
+// SessionStatus 会话健康状态
+type SessionStatus string
+
+const (
+	SessionValid       SessionStatus = "valid"         // 会话有效
+	SessionExpiring    SessionStatus = "expiring"      // 会话即将过期
+	SessionExpired     SessionStatus = "expired"       // 会话 cookies 已过期
+	SessionNotLoggedIn SessionStatus = "not_logged_in" // 没有 cookies 或网站显示未登录
+	SessionUnknown     SessionStatus = "unknown"       // 无法判断，例如检查失败
+	SessionBlocked     SessionStatus = "blocked"       // 触发验证码，操作已暂停，需要人工完成验证
+)
+
+// NeedRelogin 该状态是否需要重新登录
+func (s SessionStatus) NeedRelogin() bool {
+	return s == SessionExpired || s == SessionNotLoggedIn
+}
+
+// event 需要通知的事件，不需要人工处理时为空
+func (s SessionStatus) event() string {
+	switch {
+	case s.NeedRelogin():
+		return "relogin_required"
+	case s == SessionBlocked:
+		return "captcha_required"
+	default:
+		return ""
+	}
+}
+
+// SessionHealth 账号会话健康信息
+type SessionHealth struct {
+	Account        string        `json:"account"`
+	Status         SessionStatus `json:"status"`
+	IsLoggedIn     *bool         `json:"is_logged_in,omitempty"`
+	ExpiresAt      *time.Time    `json:"expires_at,omitempty"`
+	ExpiresInHours *float64      `json:"expires_in_hours,omitempty"`
+	CheckedAt      time.Time     `json:"checked_at"`
+	LoginCheckedAt *time.Time    `json:"login_checked_at,omitempty"`
+	BlockedSince   *time.Time    `json:"blocked_since,omitempty"`
+	Message        string        `json:"message,omitempty"`
+}
+
+// SessionMonitor 后台会话健康监控。
+// 定期解析各账号保存的 cookies 过期时间，并通过浏览器检查登录状态，
+// 会话需要重新登录时可以通过 webhook 通知。
+type SessionMonitor struct {
+	service *XiaohongshuService
+
+	interval   time.Duration
+	warnBefore time.Duration
+	webhookURL string
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	health   map[string]*SessionHealth
+	notified map[string]string // 最近一次通知的事件
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewSessionMonitor 创建会话监控，配置来自 configs
+func NewSessionMonitor(service *XiaohongshuService) *SessionMonitor {
+	return &SessionMonitor{
+		service:    service,
+		interval:   configs.GetSessionCheckInterval(),
+		warnBefore: configs.GetSessionExpiryWarning(),
+		webhookURL: configs.GetSessionWebhookURL(),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		health:     make(map[string]*SessionHealth),
+		notified:   make(map[string]string),
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Start 启动后台检查，间隔 <=0 时不启动
+func (m *SessionMonitor) Start() {
+	if m.interval <= 0 {
+		return
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		m.checkAll()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				m.checkAll()
+			}
+		}
+	}()
+
+	logrus.Infof("会话健康监控已启动，检查间隔: %s", m.interval)
+}
+
+// Stop 停止后台检查
+func (m *SessionMonitor) Stop() {
+	select {
+	case <-m.stopCh:
+		return
+	default:
+		close(m.stopCh)
+	}
+	m.wg.Wait()
+}
+
+func (m *SessionMonitor) checkAll() {
+	for _, acc := range m.service.Accounts() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		m.Check(ctx, acc, true)
+		cancel()
+	}
+}
+
+// Health 返回账号的会话健康状态。
+// refresh 为 true 时会通过浏览器实时检查登录状态，否则只解析 cookies 并附带最近一次登录检查的结果。
+func (m *SessionMonitor) Health(ctx context.Context, refresh bool) (*SessionHealth, error) {
+	acc, err := m.service.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Check(ctx, acc, refresh), nil
+}
+
+// Check 检查账号会话并更新缓存
+func (m *SessionMonitor) Check(ctx context.Context, acc *accounts.Account, checkLogin bool) *SessionHealth {
+	now := time.Now()
+	h := m.inspectCookies(acc, now)
+
+	if block := m.service.captcha.Blocked(acc.ID); block != nil {
+		// 暂停期间不访问网站，直接报告需要人工完成验证
+		h.Status = SessionBlocked
+		h.BlockedSince = &block.Since
+		h.Message = "触发验证码，操作已暂停，请调用 resolve_captcha 在浏览器中完成验证"
+	} else if checkLogin && m.service.logins.active(acc.ID) != nil {
+		// 扫码登录期间浏览器池让出了 profile，只根据 cookies 判断
+		if h.Message == "" {
+			h.Message = "正在扫码登录，暂不检查登录状态"
+		}
+	} else if checkLogin {
+		status, err := m.service.CheckLoginStatus(accounts.WithAccount(ctx, acc.ID))
+		checkedAt := time.Now()
+		h.LoginCheckedAt = &checkedAt
+		if err != nil {
+			logrus.Warnf("session check for account %s failed: %v", acc.ID, err)
+			if h.Status == SessionValid || h.Status == SessionExpiring {
+				h.Status = SessionUnknown
+			}
+			h.Message = fmt.Sprintf("检查登录状态失败: %v", err)
+		} else {
+			h.IsLoggedIn = &status.IsLoggedIn
+			if !status.IsLoggedIn {
+				h.Status = SessionNotLoggedIn
+				h.Message = "网站显示未登录，需要重新登录"
+			}
+		}
+	} else if prev := m.cached(acc.ID); prev != nil && prev.LoginCheckedAt != nil {
+		h.IsLoggedIn = prev.IsLoggedIn
+		h.LoginCheckedAt = prev.LoginCheckedAt
+		if prev.IsLoggedIn != nil && !*prev.IsLoggedIn && !h.Status.NeedRelogin() {
+			h.Status = SessionNotLoggedIn
+			h.Message = "最近一次检查显示未登录，需要重新登录"
+		}
+	}
+
+	m.mu.Lock()
+	m.health[acc.ID] = h
+	m.mu.Unlock()
+
+	m.notify(h)
+
+	return h
+}
+
+// inspectCookies 只根据保存的 cookies 判断会话状态
+func (m *SessionMonitor) inspectCookies(acc *accounts.Account, now time.Time) *SessionHealth {
+	h := &SessionHealth{Account: acc.ID, CheckedAt: now}
+
+	data, err := acc.Cookier().LoadCookies()
+	if err != nil {
+		h.Status = SessionNotLoggedIn
+		h.Message = "没有保存的 cookies，需要登录"
+		return h
+	}
+
+	expiresAt, found, err := cookies.SessionExpiry(data)
+	if err != nil {
+		h.Status = SessionUnknown
+		h.Message = fmt.Sprintf("解析 cookies 失败: %v", err)
+		return h
+	}
+	if !found {
+		h.Status = SessionNotLoggedIn
+		h.Message = "cookies 中没有登录会话，需要登录"
+		return h
+	}
+
+	h.Status = SessionValid
+	if expiresAt.IsZero() {
+		return h
+	}
+
+	left := expiresAt.Sub(now)
+	hours := left.Hours()
+	h.ExpiresAt = &expiresAt
+	h.ExpiresInHours = &hours
+
+	switch {
+	case left <= 0:
+		h.Status = SessionExpired
+		h.Message = "登录会话已过期，需要重新登录"
+	case left <= m.warnBefore:
+		h.Status = SessionExpiring
+		h.Message = fmt.Sprintf("登录会话将在 %.1f 小时后过期", hours)
+	}
+
+	return h
+}
+
+func (m *SessionMonitor) cached(id string) *SessionHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.health[id]
+}
+
+// notify 会话进入需要重新登录或完成验证的状态时调用 webhook，同一次失效只通知一次
+func (m *SessionMonitor) notify(h *SessionHealth) {
+	event := h.Status.event()
+
+	m.mu.Lock()
+	already := m.notified[h.Account] == event
+	m.notified[h.Account] = event
+	m.mu.Unlock()
+
+	if event == "" || already || m.webhookURL == "" {
+		return
+	}
+
+	go func() {
+		if err := m.postWebhook(event, h); err != nil {
+			logrus.Warnf("session webhook for account %s failed: %v", h.Account, err)
+		}
+	}()
+}
+
+// sessionWebhookPayload webhook 请求体
+type sessionWebhookPayload struct {
+	Event  string         `json:"event"`
+	Health *SessionHealth `json:"health"`
+}
+
+func (m *SessionMonitor) postWebhook(event string, h *SessionHealth) error {
+	body, err := json.Marshal(sessionWebhookPayload{Event: event, Health: h})
+	if err != nil {
+		return err
+	}
+
+	resp, err := m.httpClient.Post(m.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
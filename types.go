@@ -8,7 +8,10 @@ import "github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Code    string `json:"code"`
+	Step    string `json:"step,omitempty"` // 浏览器操作失败的步骤，如 search/navigate
 	Details any    `json:"details,omitempty"`
+
+	DiagnosticsID string `json:"diagnostics_id,omitempty"` // 失败时保存的诊断包，可通过 /api/v1/diagnostics/:id 下载
 }
 
 // SuccessResponse 成功响应
@@ -22,8 +25,18 @@ type SuccessResponse struct {
 
 // MCPToolResult MCP 工具结果（内部使用）
 type MCPToolResult struct {
-	Content []MCPContent `json:"content"`
-	IsError bool         `json:"isError,omitempty"`
+	Content           []MCPContent `json:"content"`
+	IsError           bool         `json:"isError,omitempty"`
+	StructuredContent any          `json:"structuredContent,omitempty"`
+}
+
+// MCPError 工具执行失败时 structuredContent.error 的内容，Code 与 REST 接口的错误码一致
+type MCPError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Step    string `json:"step,omitempty"`
+
+	DiagnosticsID string `json:"diagnostics_id,omitempty"`
 }
 
 // MCPContent MCP 内容（内部使用）
@@ -41,8 +54,11 @@ type FeedDetailRequest struct {
 }
 
 type SearchFeedsRequest struct {
-	Keyword string                   `json:"keyword" binding:"required"`
-	Filters xiaohongshu.FilterOption `json:"filters,omitempty"`
+	Keyword  string                   `json:"keyword" binding:"required"`
+	Filters  xiaohongshu.FilterOption `json:"filters,omitempty"`
+	Location string                   `json:"location,omitempty"` // 模拟的地理位置：城市名或 "纬度,经度"
+	Limit    int                      `json:"limit,omitempty"`    // 最多返回的笔记数，默认 20，最大 100
+	Cursor   string                   `json:"cursor,omitempty"`   // 上一次返回的 next_cursor
 }
 
 // FeedDetailResponse Feed详情响应
@@ -69,6 +85,7 @@ type PostCommentResponse struct {
 type UserProfileRequest struct {
 	UserID    string `json:"user_id" binding:"required"`
 	XsecToken string `json:"xsec_token" binding:"required"`
+	Limit     int    `json:"limit,omitempty"` // 最多返回的笔记数，默认只返回首屏，最大 300
 }
 
 // ActionResult 通用动作响应（点赞/收藏等）
@@ -77,3 +94,9 @@ type ActionResult struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
 }
+
+// AccountInfo 账号信息
+type AccountInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
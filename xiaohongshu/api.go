@@ -0,0 +1,256 @@
+package xiaohongshu
+
+// 小红书 Web 接口的响应结构，字段为下划线风格，通过 to* 方法转换为页面状态使用的 Feed、Comment 等类型
+
+// 通过网络拦截收集响应的接口路径
+const (
+	apiHomefeed    = "/api/sns/web/v1/homefeed"
+	apiSearchNotes = "/api/sns/web/v1/search/notes"
+	apiFeed        = "/api/sns/web/v1/feed"
+	apiComments    = "/api/sns/web/v2/comment/page"
+	apiUserPosted  = "/api/sns/web/v1/user_posted"
+)
+
+// apiResponse 接口的通用响应
+type apiResponse[T any] struct {
+	Code    int    `json:"code"`
+	Success bool   `json:"success"`
+	Msg     string `json:"msg"`
+	Data    T      `json:"data"`
+}
+
+// apiFeedsData 首页推荐和搜索接口的数据
+type apiFeedsData struct {
+	Items   []apiFeedItem `json:"items"`
+	HasMore bool          `json:"has_more"`
+}
+
+// apiFeedItem 首页推荐和搜索接口中的笔记卡片
+type apiFeedItem struct {
+	ID        string      `json:"id"`
+	ModelType string      `json:"model_type"`
+	XsecToken string      `json:"xsec_token"`
+	NoteCard  apiNoteCard `json:"note_card"`
+}
+
+// apiNoteCard 笔记卡片，用户主页接口直接返回卡片，笔记 ID 和 xsec_token 在卡片上
+type apiNoteCard struct {
+	NoteID       string          `json:"note_id"`
+	XsecToken    string          `json:"xsec_token"`
+	Type         string          `json:"type"`
+	DisplayTitle string          `json:"display_title"`
+	User         apiUser         `json:"user"`
+	InteractInfo apiInteractInfo `json:"interact_info"`
+	Cover        apiCover        `json:"cover"`
+	Video        *struct {
+		Capa VideoCapability `json:"capa"`
+	} `json:"video,omitempty"`
+}
+
+type apiUser struct {
+	UserID   string `json:"user_id"`
+	Nickname string `json:"nickname"`
+	NickName string `json:"nick_name"`
+	Avatar   string `json:"avatar"`
+	Image    string `json:"image"` // 评论接口中的头像字段
+}
+
+type apiInteractInfo struct {
+	Liked          bool   `json:"liked"`
+	LikedCount     string `json:"liked_count"`
+	SharedCount    string `json:"shared_count"`
+	CommentCount   string `json:"comment_count"`
+	CollectedCount string `json:"collected_count"`
+	Collected      bool   `json:"collected"`
+}
+
+type apiCover struct {
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	URL        string `json:"url"`
+	FileID     string `json:"file_id"`
+	URLPre     string `json:"url_pre"`
+	URLDefault string `json:"url_default"`
+	InfoList   []struct {
+		ImageScene string `json:"image_scene"`
+		URL        string `json:"url"`
+	} `json:"info_list"`
+}
+
+// apiFeedDetailData 笔记详情接口的数据
+type apiFeedDetailData struct {
+	Items []apiFeedDetailItem `json:"items"`
+}
+
+type apiFeedDetailItem struct {
+	ID        string        `json:"id"`
+	ModelType string        `json:"model_type"`
+	NoteCard  apiNoteDetail `json:"note_card"`
+}
+
+// apiNoteDetail 笔记详情接口中的笔记正文
+type apiNoteDetail struct {
+	NoteID       string           `json:"note_id"`
+	XsecToken    string           `json:"xsec_token"`
+	Title        string           `json:"title"`
+	Desc         string           `json:"desc"`
+	Type         string           `json:"type"`
+	Time         int64            `json:"time"`
+	IPLocation   string           `json:"ip_location"`
+	User         apiUser          `json:"user"`
+	InteractInfo apiInteractInfo  `json:"interact_info"`
+	ImageList    []apiDetailImage `json:"image_list"`
+}
+
+type apiDetailImage struct {
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	URLDefault string `json:"url_default"`
+	URLPre     string `json:"url_pre"`
+	LivePhoto  bool   `json:"live_photo"`
+}
+
+// apiUserPostedData 用户主页笔记接口的数据
+type apiUserPostedData struct {
+	Notes   []apiNoteCard `json:"notes"`
+	Cursor  string        `json:"cursor"`
+	HasMore bool          `json:"has_more"`
+}
+
+// apiCommentsData 评论接口的数据
+type apiCommentsData struct {
+	Comments []apiComment `json:"comments"`
+	Cursor   string       `json:"cursor"`
+	HasMore  bool         `json:"has_more"`
+}
+
+type apiComment struct {
+	ID              string       `json:"id"`
+	NoteID          string       `json:"note_id"`
+	Content         string       `json:"content"`
+	LikeCount       string       `json:"like_count"`
+	CreateTime      int64        `json:"create_time"`
+	IPLocation      string       `json:"ip_location"`
+	Liked           bool         `json:"liked"`
+	UserInfo        apiUser      `json:"user_info"`
+	SubCommentCount string       `json:"sub_comment_count"`
+	SubComments     []apiComment `json:"sub_comments"`
+	ShowTags        []string     `json:"show_tags"`
+}
+
+func (i apiFeedItem) toFeed() Feed {
+	return Feed{
+		XsecToken: i.XsecToken,
+		ID:        i.ID,
+		ModelType: i.ModelType,
+		NoteCard:  i.NoteCard.toNoteCard(),
+	}
+}
+
+// toFeed 用户主页接口的卡片转换为 Feed
+func (c apiNoteCard) toFeed() Feed {
+	return Feed{
+		XsecToken: c.XsecToken,
+		ID:        c.NoteID,
+		ModelType: "note",
+		NoteCard:  c.toNoteCard(),
+	}
+}
+
+func (c apiNoteCard) toNoteCard() NoteCard {
+	card := NoteCard{
+		Type:         c.Type,
+		DisplayTitle: c.DisplayTitle,
+		User:         c.User.toUser(),
+		InteractInfo: c.InteractInfo.toInteractInfo(),
+		Cover: Cover{
+			Width:      c.Cover.Width,
+			Height:     c.Cover.Height,
+			URL:        c.Cover.URL,
+			FileID:     c.Cover.FileID,
+			URLPre:     c.Cover.URLPre,
+			URLDefault: c.Cover.URLDefault,
+		},
+	}
+	for _, info := range c.Cover.InfoList {
+		card.Cover.InfoList = append(card.Cover.InfoList, ImageInfo{ImageScene: info.ImageScene, URL: info.URL})
+	}
+	if c.Video != nil {
+		card.Video = &Video{Capa: c.Video.Capa}
+	}
+	return card
+}
+
+// toFeedDetail 详情接口的笔记转换为页面状态中的 FeedDetail，接口中的笔记 ID 在外层
+func (n apiNoteDetail) toFeedDetail(id string) FeedDetail {
+	detail := FeedDetail{
+		NoteID:       n.NoteID,
+		XsecToken:    n.XsecToken,
+		Title:        n.Title,
+		Desc:         n.Desc,
+		Type:         n.Type,
+		Time:         n.Time,
+		IPLocation:   n.IPLocation,
+		User:         n.User.toUser(),
+		InteractInfo: n.InteractInfo.toInteractInfo(),
+	}
+	if detail.NoteID == "" {
+		detail.NoteID = id
+	}
+	for _, img := range n.ImageList {
+		detail.ImageList = append(detail.ImageList, DetailImageInfo{
+			Width:      img.Width,
+			Height:     img.Height,
+			URLDefault: img.URLDefault,
+			URLPre:     img.URLPre,
+			LivePhoto:  img.LivePhoto,
+		})
+	}
+	return detail
+}
+
+func (i apiInteractInfo) toInteractInfo() InteractInfo {
+	return InteractInfo{
+		Liked:          i.Liked,
+		LikedCount:     i.LikedCount,
+		SharedCount:    i.SharedCount,
+		CommentCount:   i.CommentCount,
+		CollectedCount: i.CollectedCount,
+		Collected:      i.Collected,
+	}
+}
+
+func (u apiUser) toUser() User {
+	user := User{
+		UserID:   u.UserID,
+		Nickname: u.Nickname,
+		NickName: u.NickName,
+		Avatar:   u.Avatar,
+	}
+	if user.Avatar == "" {
+		user.Avatar = u.Image
+	}
+	if user.NickName == "" {
+		user.NickName = u.Nickname
+	}
+	return user
+}
+
+func (c apiComment) toComment() Comment {
+	comment := Comment{
+		ID:              c.ID,
+		NoteID:          c.NoteID,
+		Content:         c.Content,
+		LikeCount:       c.LikeCount,
+		CreateTime:      c.CreateTime,
+		IPLocation:      c.IPLocation,
+		Liked:           c.Liked,
+		UserInfo:        c.UserInfo.toUser(),
+		SubCommentCount: c.SubCommentCount,
+		ShowTags:        c.ShowTags,
+	}
+	for _, sub := range c.SubComments {
+		comment.SubComments = append(comment.SubComments, sub.toComment())
+	}
+	return comment
+}
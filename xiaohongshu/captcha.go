@@ -0,0 +1,43 @@
+package xiaohongshu
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/go-rod/rod"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+// selectorCaptcha 滑块验证码、安全验证弹层等风控验证元素
+const selectorCaptcha = `.red-captcha, #red-captcha, .red-captcha-slider, .captcha-container, iframe[src*="captcha"]`
+
+// CaptchaPresent 页面是否处于验证码或安全验证状态：
+// 被重定向到了验证页，或者页面上弹出了验证层。
+func CaptchaPresent(page *rod.Page) (bool, error) {
+	info, err := page.Info()
+	if err != nil {
+		return false, err
+	}
+
+	if u, err := url.Parse(info.URL); err == nil && strings.Contains(u.Path, "/captcha") {
+		return true, nil
+	}
+
+	exists, _, err := page.Has(selectorCaptcha)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// checkCaptcha 页面出现验证码时返回 CAPTCHA_REQUIRED
+func checkCaptcha(page *rod.Page) error {
+	present, err := CaptchaPresent(page)
+	if err != nil {
+		return err
+	}
+	if present {
+		return myerrors.ErrCaptchaRequired
+	}
+	return nil
+}
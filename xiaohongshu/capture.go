@@ -0,0 +1,286 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/sirupsen/logrus"
+)
+
+// apiCapture 通过 CDP Network 事件收集页面请求的小红书接口响应。
+// __INITIAL_STATE__ 只包含首屏数据，且前端改版时容易失效，接口响应可以作为更完整、更稳定的补充。
+// 只监听网络事件不拦截请求，不影响代理认证使用的 Fetch 拦截。
+type apiCapture struct {
+	paths map[string]bool
+	stop  context.CancelFunc
+
+	mu      sync.Mutex
+	pending map[proto.NetworkRequestID]string // 已收到响应头、等待响应体的请求
+	bodies  map[string][][]byte               // 接口路径 -> 按顺序收到的响应体
+}
+
+// captureAPI 开始收集 paths 接口的响应，需要在页面导航之前调用，用完后调用 stop
+func captureAPI(page *rod.Page, paths ...string) (*apiCapture, error) {
+	if err := (proto.NetworkEnable{}).Call(page); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(page.GetContext())
+	c := &apiCapture{
+		paths:   make(map[string]bool, len(paths)),
+		stop:    cancel,
+		pending: make(map[proto.NetworkRequestID]string),
+		bodies:  make(map[string][][]byte),
+	}
+	for _, path := range paths {
+		c.paths[path] = true
+	}
+
+	p := page.Context(ctx)
+	wait := p.EachEvent(func(e *proto.NetworkResponseReceived) {
+		if e.Type != proto.NetworkResourceTypeXHR && e.Type != proto.NetworkResourceTypeFetch {
+			return
+		}
+		if e.Response == nil || e.Response.Status != http.StatusOK {
+			return
+		}
+		u, err := url.Parse(e.Response.URL)
+		if err != nil || !c.paths[u.Path] {
+			return
+		}
+
+		c.mu.Lock()
+		c.pending[e.RequestID] = u.Path
+		c.mu.Unlock()
+	}, func(e *proto.NetworkLoadingFinished) {
+		c.mu.Lock()
+		path, ok := c.pending[e.RequestID]
+		delete(c.pending, e.RequestID)
+		c.mu.Unlock()
+		if !ok {
+			return
+		}
+
+		// 响应体需要在加载完成后、页面导航之前获取
+		res, err := proto.NetworkGetResponseBody{RequestID: e.RequestID}.Call(p)
+		if err != nil {
+			logrus.Debugf("get response body of %s: %v", path, err)
+			return
+		}
+		body := []byte(res.Body)
+		if res.Base64Encoded {
+			if body, err = base64.StdEncoding.DecodeString(res.Body); err != nil {
+				logrus.Debugf("decode response body of %s: %v", path, err)
+				return
+			}
+		}
+
+		c.mu.Lock()
+		c.bodies[path] = append(c.bodies[path], body)
+		c.mu.Unlock()
+	})
+	go wait()
+
+	return c, nil
+}
+
+// count 已收到的 path 接口响应数
+func (c *apiCapture) count(path string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.bodies[path])
+}
+
+// reset 丢弃已收到的响应，用于页面切换筛选条件等之前的数据不再适用时
+func (c *apiCapture) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bodies = make(map[string][][]byte)
+}
+
+func (c *apiCapture) responses(path string) [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]byte(nil), c.bodies[path]...)
+}
+
+// maxLoadScrolls 滚动加载评论、用户笔记时最多滚动的次数
+const maxLoadScrolls = 10
+
+// scrollToLoad 反复将 scroller（为空时为整个页面）滚动到底部，让页面请求 path 接口的后续分页，
+// 直到出现 end 元素、done（可以为 nil）返回 true、连续 maxStalledScrolls 次没有新的响应、滚动 maxLoadScrolls 次或操作剩余时间不足
+func scrollToLoad(ctx context.Context, page *rod.Page, capture *apiCapture, path, scroller, end string, done func() bool) error {
+	stalled := 0
+	for i := 0; i < maxLoadScrolls && stalled < maxStalledScrolls; i++ {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < scrollWait+scrollReserve {
+			return nil
+		}
+		if done != nil && done() {
+			return nil
+		}
+
+		ended, _, err := page.Has(end)
+		if err != nil {
+			return err
+		}
+		if ended {
+			return nil
+		}
+
+		n := capture.count(path)
+		if _, err := page.Eval(`(selector) => {
+			const el = selector ? document.querySelector(selector) : document.scrollingElement;
+			if (el) {
+				el.scrollTop = el.scrollHeight;
+			}
+		}`, scroller); err != nil {
+			return err
+		}
+
+		stalled++
+		for deadline := time.Now().Add(scrollWait); time.Now().Before(deadline); {
+			if err := sleep(ctx, scrollPoll); err != nil {
+				return err
+			}
+			if capture.count(path) > n {
+				stalled = 0
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// feeds 首页推荐或搜索接口返回的笔记，按收到的顺序排列
+func (c *apiCapture) feeds(path string) []Feed {
+	var feeds []Feed
+	for _, data := range decodeAPI[apiFeedsData](c.responses(path)) {
+		for _, item := range data.Items {
+			feeds = append(feeds, item.toFeed())
+		}
+	}
+	return feeds
+}
+
+// userNotes 用户主页笔记接口返回的笔记
+func (c *apiCapture) userNotes() []Feed {
+	var feeds []Feed
+	for _, data := range decodeAPI[apiUserPostedData](c.responses(apiUserPosted)) {
+		for _, note := range data.Notes {
+			feeds = append(feeds, note.toFeed())
+		}
+	}
+	return feeds
+}
+
+// comments 评论接口返回的评论，游标和是否有更多取最后一次响应，没有收到响应时 ok 为 false
+func (c *apiCapture) comments() (list CommentList, ok bool) {
+	pages := decodeAPI[apiCommentsData](c.responses(apiComments))
+	for _, data := range pages {
+		for _, comment := range data.Comments {
+			list.List = append(list.List, comment.toComment())
+		}
+		list.Cursor = data.Cursor
+		list.HasMore = data.HasMore
+	}
+	return list, len(pages) > 0
+}
+
+// noteDetail 笔记详情接口返回的 id 笔记，有多次响应时取最后一次，没有收到时 ok 为 false
+func (c *apiCapture) noteDetail(id string) (detail FeedDetail, ok bool) {
+	for _, data := range decodeAPI[apiFeedDetailData](c.responses(apiFeed)) {
+		for _, item := range data.Items {
+			if item.ID == id {
+				detail, ok = item.NoteCard.toFeedDetail(item.ID), true
+			}
+		}
+	}
+	return detail, ok
+}
+
+// decodeAPI 解析接口响应，跳过无法解析或返回失败的响应
+func decodeAPI[T any](bodies [][]byte) []T {
+	var result []T
+	for _, body := range bodies {
+		var resp apiResponse[T]
+		if err := json.Unmarshal(body, &resp); err != nil {
+			logrus.Debugf("unmarshal api response: %v", err)
+			continue
+		}
+		if !resp.Success {
+			logrus.Debugf("api response failed: code=%d msg=%s", resp.Code, resp.Msg)
+			continue
+		}
+		result = append(result, resp.Data)
+	}
+	return result
+}
+
+// appendNewFeeds 将 more 中 feeds 里还没有的笔记按顺序追加到 feeds 后面
+func appendNewFeeds(feeds, more []Feed) []Feed {
+	seen := make(map[string]bool, len(feeds))
+	for _, f := range feeds {
+		seen[f.ID] = true
+	}
+	for _, f := range more {
+		if f.ID == "" || seen[f.ID] {
+			continue
+		}
+		seen[f.ID] = true
+		feeds = append(feeds, f)
+	}
+	return feeds
+}
+
+// mergeComments 将接口返回的评论合并到页面状态的评论列表中，游标以接口为准
+func mergeComments(list, captured CommentList) CommentList {
+	seen := make(map[string]bool, len(list.List))
+	for _, c := range list.List {
+		seen[c.ID] = true
+	}
+	for _, c := range captured.List {
+		if seen[c.ID] {
+			continue
+		}
+		seen[c.ID] = true
+		list.List = append(list.List, c)
+	}
+	list.Cursor = captured.Cursor
+	list.HasMore = captured.HasMore
+	return list
+}
+
+// mergeNoteDetail 用接口返回的笔记补全页面状态中缺失的字段，两边都有时以页面状态为准
+func mergeNoteDetail(note, captured FeedDetail) FeedDetail {
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	fill(&note.NoteID, captured.NoteID)
+	fill(&note.XsecToken, captured.XsecToken)
+	fill(&note.Title, captured.Title)
+	fill(&note.Desc, captured.Desc)
+	fill(&note.Type, captured.Type)
+	fill(&note.IPLocation, captured.IPLocation)
+	if note.Time == 0 {
+		note.Time = captured.Time
+	}
+	if note.User.UserID == "" {
+		note.User = captured.User
+	}
+	if note.InteractInfo == (InteractInfo{}) {
+		note.InteractInfo = captured.InteractInfo
+	}
+	if len(note.ImageList) == 0 {
+		note.ImageList = captured.ImageList
+	}
+	return note
+}
@@ -0,0 +1,119 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPICaptureDecode(t *testing.T) {
+	c := &apiCapture{bodies: map[string][][]byte{
+		apiSearchNotes: {
+			[]byte(`{"code":0,"success":true,"data":{"has_more":true,"items":[
+				{"id":"n1","model_type":"note","xsec_token":"t1","note_card":{"type":"video","display_title":"标题",
+				 "user":{"user_id":"u1","nickname":"作者","avatar":"a.png"},
+				 "interact_info":{"liked":true,"liked_count":"10","comment_count":"2"},
+				 "cover":{"url_default":"c.png","info_list":[{"image_scene":"WB_DFT","url":"c.png"}]},
+				 "video":{"capa":{"duration":15}}}}]}}`),
+			[]byte(`{"code":-1,"success":false,"msg":"访问频次异常"}`),
+			[]byte(`not json`),
+		},
+		apiHomefeed: {
+			[]byte(`{"success":true,"data":{"cursor_score":"","has_more":false,"items":[
+				{"id":"h1","model_type":"note","xsec_token":"th","note_card":{"type":"normal","display_title":"推荐"}}]}}`),
+		},
+		apiFeed: {
+			[]byte(`{"success":true,"data":{"items":[{"id":"n1","model_type":"note","note_card":{
+				"title":"详情标题","desc":"正文","type":"normal","time":1700000000000,"ip_location":"上海",
+				"user":{"user_id":"u1","nickname":"作者"},"interact_info":{"liked_count":"10"},
+				"image_list":[{"width":1080,"height":1440,"url_default":"d.png","url_pre":"p.png"}]}}]}}`),
+		},
+		apiComments: {
+			[]byte(`{"success":true,"data":{"cursor":"c1","has_more":true,"comments":[
+				{"id":"c1","note_id":"n1","content":"第一","like_count":"3","user_info":{"user_id":"u2","nickname":"路人","image":"b.png"},
+				 "sub_comments":[{"id":"c1-1","content":"回复"}]}]}}`),
+			[]byte(`{"success":true,"data":{"cursor":"","has_more":false,"comments":[{"id":"c2","content":"第二"}]}}`),
+		},
+		apiUserPosted: {
+			[]byte(`{"success":true,"data":{"notes":[{"note_id":"n2","xsec_token":"t2","type":"normal","display_title":"主页笔记"}]}}`),
+		},
+	}}
+
+	feeds := c.feeds(apiSearchNotes)
+	require.Len(t, feeds, 1)
+	require.Equal(t, "n1", feeds[0].ID)
+	require.Equal(t, "t1", feeds[0].XsecToken)
+	require.Equal(t, "作者", feeds[0].NoteCard.User.NickName)
+	require.Equal(t, "10", feeds[0].NoteCard.InteractInfo.LikedCount)
+	require.Equal(t, "c.png", feeds[0].NoteCard.Cover.InfoList[0].URL)
+	require.Equal(t, 15, feeds[0].NoteCard.Video.Capa.Duration)
+
+	feeds = c.feeds(apiHomefeed)
+	require.Len(t, feeds, 1)
+	require.Equal(t, "h1", feeds[0].ID)
+	require.Equal(t, "推荐", feeds[0].NoteCard.DisplayTitle)
+
+	detail, ok := c.noteDetail("n1")
+	require.True(t, ok)
+	require.Equal(t, "n1", detail.NoteID)
+	require.Equal(t, "正文", detail.Desc)
+	require.Equal(t, "上海", detail.IPLocation)
+	require.Equal(t, "作者", detail.User.Nickname)
+	require.Equal(t, "10", detail.InteractInfo.LikedCount)
+	require.Equal(t, "d.png", detail.ImageList[0].URLDefault)
+	_, ok = c.noteDetail("n2")
+	require.False(t, ok)
+
+	comments, ok := c.comments()
+	require.True(t, ok)
+	require.Len(t, comments.List, 2)
+	require.Equal(t, "b.png", comments.List[0].UserInfo.Avatar)
+	require.Equal(t, "回复", comments.List[0].SubComments[0].Content)
+	require.False(t, comments.HasMore)
+
+	notes := c.userNotes()
+	require.Len(t, notes, 1)
+	require.Equal(t, "n2", notes[0].ID)
+	require.Equal(t, "主页笔记", notes[0].NoteCard.DisplayTitle)
+
+	c.reset()
+	require.Empty(t, c.feeds(apiSearchNotes))
+	_, ok = c.comments()
+	require.False(t, ok)
+}
+
+func TestMergeCapturedData(t *testing.T) {
+	feeds := appendNewFeeds([]Feed{{ID: "a"}, {ID: "b"}}, []Feed{{ID: "b"}, {ID: "c"}, {}})
+	require.Equal(t, []Feed{{ID: "a"}, {ID: "b"}, {ID: "c"}}, feeds)
+	require.Nil(t, appendNewFeeds(nil, nil))
+
+	list := mergeComments(
+		CommentList{List: []Comment{{ID: "1"}}, Cursor: "old", HasMore: true},
+		CommentList{List: []Comment{{ID: "1"}, {ID: "2"}}, Cursor: "new", HasMore: false},
+	)
+	require.Len(t, list.List, 2)
+	require.Equal(t, "new", list.Cursor)
+	require.False(t, list.HasMore)
+}
+
+func TestMergeNoteDetail(t *testing.T) {
+	captured := FeedDetail{
+		NoteID:       "n1",
+		Title:        "接口标题",
+		Desc:         "接口正文",
+		Time:         1700000000000,
+		User:         User{UserID: "u1", Nickname: "作者"},
+		InteractInfo: InteractInfo{LikedCount: "10"},
+		ImageList:    []DetailImageInfo{{URLDefault: "d.png"}},
+	}
+
+	note := mergeNoteDetail(FeedDetail{NoteID: "n1", Title: "页面标题"}, captured)
+	require.Equal(t, "页面标题", note.Title)
+	require.Equal(t, "接口正文", note.Desc)
+	require.Equal(t, int64(1700000000000), note.Time)
+	require.Equal(t, "u1", note.User.UserID)
+	require.Equal(t, "10", note.InteractInfo.LikedCount)
+	require.Len(t, note.ImageList, 1)
+
+	require.Equal(t, captured, mergeNoteDetail(FeedDetail{}, captured))
+}
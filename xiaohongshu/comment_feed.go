@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/go-rod/rod"
-	"github.com/sirupsen/logrus"
 )
 
 // CommentFeedAction 表示 Feed 评论动作
@@ -13,6 +12,8 @@ type CommentFeedAction struct {
 	page *rod.Page
 }
 
+const actionComment = "post_comment"
+
 // NewCommentFeedAction 创建 Feed 评论动作
 func NewCommentFeedAction(page *rod.Page) *CommentFeedAction {
 	return &CommentFeedAction{page: page}
@@ -20,31 +21,41 @@ func NewCommentFeedAction(page *rod.Page) *CommentFeedAction {
 
 // PostComment 发表评论到 Feed
 func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken, content string) error {
-	page := f.page.Context(ctx).Timeout(60 * time.Second)
-
-	// 构建详情页 URL
-	url := makeFeedDetailURL(feedID, xsecToken)
-
-	logrus.Infof("Opening feed detail page: %s", url)
-
-	// 导航到详情页
-	page.MustNavigate(url)
-	page.MustWaitDOMStable()
-
-	time.Sleep(1 * time.Second)
-
-	elem := page.MustElement("div.input-box div.content-edit span")
-	elem.MustClick()
-
-	elem2 := page.MustElement("div.input-box div.content-edit p.content-input")
-	elem2.MustInput(content)
-
-	time.Sleep(1 * time.Second)
-
-	submitButton := page.MustElement("div.bottom button.submit")
-	submitButton.MustClick()
-
-	time.Sleep(1 * time.Second)
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
+
+	page := f.page.Context(ctx)
+
+	if err := openFeedDetail(ctx, page, actionComment, feedID, xsecToken); err != nil {
+		return err
+	}
+	if err := requireLogin(page); err != nil {
+		return stepErr(actionComment, "check_login", err)
+	}
+
+	if err := click(page, "div.input-box div.content-edit span"); err != nil {
+		return stepErr(actionComment, "click_input_box", err)
+	}
+
+	input, err := page.Element("div.input-box div.content-edit p.content-input")
+	if err != nil {
+		return stepErr(actionComment, "find_input", err)
+	}
+	if err := input.Input(content); err != nil {
+		return stepErr(actionComment, "input_content", err)
+	}
+
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return stepErr(actionComment, "input_content", err)
+	}
+
+	if err := click(page, "div.bottom button.submit"); err != nil {
+		return stepErr(actionComment, "click_submit", err)
+	}
+
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return stepErr(actionComment, "wait_submit", err)
+	}
 
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-rod/rod"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
@@ -16,6 +17,8 @@ type FeedDetailAction struct {
 	page *rod.Page
 }
 
+const actionFeedDetail = "get_feed_detail"
+
 // NewFeedDetailAction 创建 Feed 详情页动作
 func NewFeedDetailAction(page *rod.Page) *FeedDetailAction {
 	return &FeedDetailAction{page: page}
@@ -23,19 +26,22 @@ func NewFeedDetailAction(page *rod.Page) *FeedDetailAction {
 
 // GetFeedDetail 获取 Feed 详情页数据
 func (f *FeedDetailAction) GetFeedDetail(ctx context.Context, feedID, xsecToken string) (*FeedDetailResponse, error) {
-	page := f.page.Context(ctx).Timeout(60 * time.Second)
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
 
-	// 构建详情页 URL
-	url := makeFeedDetailURL(feedID, xsecToken)
+	page := f.page.Context(ctx)
 
-	logrus.Infof("打开 feed 详情页: %s", url)
+	capture, err := captureAPI(page, apiFeed, apiComments)
+	if err != nil {
+		return nil, stepErr(actionFeedDetail, "capture_api", err)
+	}
+	defer capture.stop()
 
-	// 导航到详情页
-	page.MustNavigate(url)
-	page.MustWaitDOMStable()
-	time.Sleep(1 * time.Second)
+	if err := openFeedDetail(ctx, page, actionFeedDetail, feedID, xsecToken); err != nil {
+		return nil, err
+	}
 
-	result := page.MustEval(`() => {
+	result, err := evalString(page, `() => {
 		if (window.__INITIAL_STATE__ &&
 		    window.__INITIAL_STATE__.note &&
 		    window.__INITIAL_STATE__.note.noteDetailMap) {
@@ -43,32 +49,75 @@ func (f *FeedDetailAction) GetFeedDetail(ctx context.Context, feedID, xsecToken
 			return JSON.stringify(noteDetailMap);
 		}
 		return "";
-	}`).String()
-
-	if result == "" {
-		return nil, errors.ErrNoFeedDetail
+	}`)
+	if err != nil {
+		return nil, stepErr(actionFeedDetail, "read_state", err)
 	}
 
 	var noteDetailMap map[string]struct {
 		Note     FeedDetail  `json:"note"`
 		Comments CommentList `json:"comments"`
 	}
-
-	if err := json.Unmarshal([]byte(result), &noteDetailMap); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal noteDetailMap: %w", err)
+	if result != "" {
+		if err := json.Unmarshal([]byte(result), &noteDetailMap); err != nil {
+			return nil, stepErr(actionFeedDetail, "parse_state", fmt.Errorf("failed to unmarshal noteDetailMap: %w", err))
+		}
 	}
 
+	// 页面打开后还会请求详情接口，页面状态中缺失的笔记或字段用接口响应补全
 	noteDetail, exists := noteDetailMap[feedID]
+	if apiNote, ok := capture.noteDetail(feedID); ok {
+		noteDetail.Note = mergeNoteDetail(noteDetail.Note, apiNote)
+		exists = true
+	}
 	if !exists {
-		return nil, fmt.Errorf("feed %s not found in noteDetailMap", feedID)
+		if result == "" {
+			return nil, stepErr(actionFeedDetail, "read_state", errors.ErrNoFeedDetail)
+		}
+		return nil, stepErr(actionFeedDetail, "parse_state", errors.WithCode(errors.CodeNoteNotFound, fmt.Errorf("feed %s not found in noteDetailMap", feedID)))
+	}
+
+	// 页面状态中只有首屏的评论，还有更多时滚动评论区，通过接口响应收集后续分页
+	captured, ok := capture.comments()
+	if noteDetail.Comments.HasMore || captured.HasMore {
+		if err := scrollToLoad(ctx, page, capture, apiComments, ".note-scroller", ".comments-container .end-container", nil); err != nil {
+			return nil, stepErr(actionFeedDetail, "scroll_comments", err)
+		}
+		captured, ok = capture.comments()
+	}
+
+	comments := noteDetail.Comments
+	if ok {
+		comments = mergeComments(comments, captured)
 	}
 
 	return &FeedDetailResponse{
 		Note:     noteDetail.Note,
-		Comments: noteDetail.Comments,
+		Comments: comments,
 	}, nil
 }
 
+// openFeedDetail 打开 Feed 详情页并等待页面渲染完成，点赞、收藏、评论共用
+func openFeedDetail(ctx context.Context, page *rod.Page, action, feedID, xsecToken string) error {
+	url := makeFeedDetailURL(feedID, xsecToken)
+
+	logrus.Infof("打开 feed 详情页: %s", url)
+
+	if err := page.Navigate(url); err != nil {
+		return stepErr(action, "navigate", err)
+	}
+	if err := page.WaitDOMStable(time.Second, 0); err != nil {
+		return stepErr(action, "wait_dom_stable", err)
+	}
+	if err := checkPage(page); err != nil {
+		return stepErr(action, "check_page", err)
+	}
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return stepErr(action, "wait_state", err)
+	}
+	return nil
+}
+
 func makeFeedDetailURL(feedID, xsecToken string) string {
-	return fmt.Sprintf("https://www.xiaohongshu.com/explore/%s?xsec_token=%s&xsec_source=pc_feed", feedID, xsecToken)
+	return configs.SiteURL(fmt.Sprintf("/explore/%s?xsec_token=%s&xsec_source=pc_feed", feedID, xsecToken))
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
@@ -14,22 +15,41 @@ type FeedsListAction struct {
 	page *rod.Page
 }
 
-func NewFeedsListAction(page *rod.Page) *FeedsListAction {
-	pp := page.Timeout(60 * time.Second)
-
-	pp.MustNavigate("https://www.xiaohongshu.com")
-	pp.MustWaitDOMStable()
+const actionFeeds = "list_feeds"
 
-	return &FeedsListAction{page: pp}
+func NewFeedsListAction(page *rod.Page) *FeedsListAction {
+	return &FeedsListAction{page: page}
 }
 
-// GetFeedsList 获取页面的 Feed 列表数据
+// GetFeedsList 打开首页并获取页面的 Feed 列表数据
 func (f *FeedsListAction) GetFeedsList(ctx context.Context) ([]Feed, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
+
 	page := f.page.Context(ctx)
 
-	time.Sleep(1 * time.Second)
+	// 首屏之外的推荐由页面加载后请求 homefeed 接口补充，不一定写回页面状态
+	capture, err := captureAPI(page, apiHomefeed)
+	if err != nil {
+		return nil, stepErr(actionFeeds, "capture_api", err)
+	}
+	defer capture.stop()
 
-	result := page.MustEval(`() => {
+	if err := page.Navigate(configs.GetSiteURL()); err != nil {
+		return nil, stepErr(actionFeeds, "navigate", err)
+	}
+	if err := page.WaitDOMStable(time.Second, 0); err != nil {
+		return nil, stepErr(actionFeeds, "wait_dom_stable", err)
+	}
+	if err := checkPage(page); err != nil {
+		return nil, stepErr(actionFeeds, "check_page", err)
+	}
+
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return nil, stepErr(actionFeeds, "wait_state", err)
+	}
+
+	result, err := evalString(page, `() => {
 		if (window.__INITIAL_STATE__ &&
 		    window.__INITIAL_STATE__.feed &&
 		    window.__INITIAL_STATE__.feed.feeds) {
@@ -40,15 +60,21 @@ func (f *FeedsListAction) GetFeedsList(ctx context.Context) ([]Feed, error) {
 			}
 		}
 		return "";
-	}`).String()
-
-	if result == "" {
-		return nil, errors.ErrNoFeeds
+	}`)
+	if err != nil {
+		return nil, stepErr(actionFeeds, "read_state", err)
 	}
 
 	var feeds []Feed
-	if err := json.Unmarshal([]byte(result), &feeds); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal feeds: %w", err)
+	if result != "" {
+		if err := json.Unmarshal([]byte(result), &feeds); err != nil {
+			return nil, stepErr(actionFeeds, "parse_state", fmt.Errorf("failed to unmarshal feeds: %w", err))
+		}
+	}
+
+	feeds = appendNewFeeds(feeds, capture.feeds(apiHomefeed))
+	if len(feeds) == 0 {
+		return nil, stepErr(actionFeeds, "read_state", errors.ErrNoFeeds)
 	}
 
 	return feeds, nil
@@ -39,25 +39,46 @@ type interactAction struct {
 	page *rod.Page
 }
 
+// name 返回错误信息中使用的操作名
+func (t interactActionType) name() string {
+	switch t {
+	case actionLike:
+		return "like"
+	case actionUnlike:
+		return "unlike"
+	case actionFavorite:
+		return "favorite"
+	default:
+		return "unfavorite"
+	}
+}
+
 func newInteractAction(page *rod.Page) *interactAction {
 	return &interactAction{page: page}
 }
 
-func (a *interactAction) preparePage(ctx context.Context, actionType interactActionType, feedID, xsecToken string) *rod.Page {
-	page := a.page.Context(ctx).Timeout(60 * time.Second)
-	url := makeFeedDetailURL(feedID, xsecToken)
-	logrus.Infof("Opening feed detail page for %s: %s", actionType, url)
+func (a *interactAction) preparePage(ctx context.Context, actionType interactActionType, feedID, xsecToken string) (*rod.Page, error) {
+	page := a.page.Context(ctx)
+	logrus.Infof("Opening feed detail page for %s", actionType)
 
-	page.MustNavigate(url)
-	page.MustWaitDOMStable()
-	time.Sleep(1 * time.Second)
-
-	return page
+	if err := openFeedDetail(ctx, page, actionType.name(), feedID, xsecToken); err != nil {
+		return nil, err
+	}
+	if err := requireLogin(page); err != nil {
+		return nil, stepErr(actionType.name(), "check_login", err)
+	}
+	return page, nil
 }
 
-func (a *interactAction) performClick(page *rod.Page, selector string) {
-	element := page.MustElement(selector)
-	element.MustClick()
+// clickAndWait 点击交互按钮并等待状态更新
+func (a *interactAction) clickAndWait(ctx context.Context, page *rod.Page, actionType interactActionType, selector string, wait time.Duration) error {
+	if err := click(page, selector); err != nil {
+		return stepErr(actionType.name(), "click_button", err)
+	}
+	if err := sleep(ctx, wait); err != nil {
+		return stepErr(actionType.name(), "wait_state", err)
+	}
+	return nil
 }
 
 // LikeAction 负责处理点赞相关交互
@@ -85,12 +106,18 @@ func (a *LikeAction) perform(ctx context.Context, feedID, xsecToken string, targ
 		actionType = actionUnlike
 	}
 
-	page := a.preparePage(ctx, actionType, feedID, xsecToken)
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
+
+	page, err := a.preparePage(ctx, actionType, feedID, xsecToken)
+	if err != nil {
+		return err
+	}
 
 	liked, _, err := a.getInteractState(page, feedID)
 	if err != nil {
 		logrus.Warnf("failed to read interact state: %v (continue to try clicking)", err)
-		return a.toggleLike(page, feedID, targetLiked, actionType)
+		return a.toggleLike(ctx, page, feedID, targetLiked, actionType)
 	}
 
 	if targetLiked && liked {
@@ -102,12 +129,13 @@ func (a *LikeAction) perform(ctx context.Context, feedID, xsecToken string, targ
 		return nil
 	}
 
-	return a.toggleLike(page, feedID, targetLiked, actionType)
+	return a.toggleLike(ctx, page, feedID, targetLiked, actionType)
 }
 
-func (a *LikeAction) toggleLike(page *rod.Page, feedID string, targetLiked bool, actionType interactActionType) error {
-	a.performClick(page, SelectorLikeButton)
-	time.Sleep(3 * time.Second)
+func (a *LikeAction) toggleLike(ctx context.Context, page *rod.Page, feedID string, targetLiked bool, actionType interactActionType) error {
+	if err := a.clickAndWait(ctx, page, actionType, SelectorLikeButton, 3*time.Second); err != nil {
+		return err
+	}
 
 	liked, _, err := a.getInteractState(page, feedID)
 	if err != nil {
@@ -120,8 +148,9 @@ func (a *LikeAction) toggleLike(page *rod.Page, feedID string, targetLiked bool,
 	}
 
 	logrus.Warnf("feed %s %s可能未成功，状态未变化，尝试再次点击", feedID, actionType)
-	a.performClick(page, SelectorLikeButton)
-	time.Sleep(2 * time.Second)
+	if err := a.clickAndWait(ctx, page, actionType, SelectorLikeButton, 2*time.Second); err != nil {
+		return err
+	}
 
 	liked, _, err = a.getInteractState(page, feedID)
 	if err != nil {
@@ -161,12 +190,18 @@ func (a *FavoriteAction) perform(ctx context.Context, feedID, xsecToken string,
 		actionType = actionUnfavorite
 	}
 
-	page := a.preparePage(ctx, actionType, feedID, xsecToken)
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
+
+	page, err := a.preparePage(ctx, actionType, feedID, xsecToken)
+	if err != nil {
+		return err
+	}
 
 	_, collected, err := a.getInteractState(page, feedID)
 	if err != nil {
 		logrus.Warnf("failed to read interact state: %v (continue to try clicking)", err)
-		return a.toggleFavorite(page, feedID, targetCollected, actionType)
+		return a.toggleFavorite(ctx, page, feedID, targetCollected, actionType)
 	}
 
 	if targetCollected && collected {
@@ -178,12 +213,13 @@ func (a *FavoriteAction) perform(ctx context.Context, feedID, xsecToken string,
 		return nil
 	}
 
-	return a.toggleFavorite(page, feedID, targetCollected, actionType)
+	return a.toggleFavorite(ctx, page, feedID, targetCollected, actionType)
 }
 
-func (a *FavoriteAction) toggleFavorite(page *rod.Page, feedID string, targetCollected bool, actionType interactActionType) error {
-	a.performClick(page, SelectorCollectButton)
-	time.Sleep(3 * time.Second)
+func (a *FavoriteAction) toggleFavorite(ctx context.Context, page *rod.Page, feedID string, targetCollected bool, actionType interactActionType) error {
+	if err := a.clickAndWait(ctx, page, actionType, SelectorCollectButton, 3*time.Second); err != nil {
+		return err
+	}
 
 	_, collected, err := a.getInteractState(page, feedID)
 	if err != nil {
@@ -196,8 +232,9 @@ func (a *FavoriteAction) toggleFavorite(page *rod.Page, feedID string, targetCol
 	}
 
 	logrus.Warnf("feed %s %s可能未成功，状态未变化，尝试再次点击", feedID, actionType)
-	a.performClick(page, SelectorCollectButton)
-	time.Sleep(2 * time.Second)
+	if err := a.clickAndWait(ctx, page, actionType, SelectorCollectButton, 2*time.Second); err != nil {
+		return err
+	}
 
 	_, collected, err = a.getInteractState(page, feedID)
 	if err != nil {
@@ -215,14 +252,17 @@ func (a *FavoriteAction) toggleFavorite(page *rod.Page, feedID string, targetCol
 // getInteractState 从 __INITIAL_STATE__ 读取笔记的点赞/收藏状态
 func (a *interactAction) getInteractState(page *rod.Page, feedID string) (liked bool, collected bool, err error) {
 
-	result := page.MustEval(`() => {
+	result, err := evalString(page, `() => {
 		if (window.__INITIAL_STATE__ &&
 		    window.__INITIAL_STATE__.note &&
 		    window.__INITIAL_STATE__.note.noteDetailMap) {
 			return JSON.stringify(window.__INITIAL_STATE__.note.noteDetailMap);
 		}
 		return "";
-	}`).String()
+	}`)
+	if err != nil {
+		return false, false, err
+	}
 	if result == "" {
 		return false, false, myerrors.ErrNoFeedDetail
 	}
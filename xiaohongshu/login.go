@@ -2,81 +2,208 @@ package xiaohongshu
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 )
 
 type LoginAction struct {
 	page *rod.Page
 }
 
+const (
+	actionCheckLogin   = "check_login_status"
+	actionLogin        = "login"
+	actionFetchQrcode  = "fetch_qrcode"
+	actionLogout       = "logout"
+	selectorLoggedUser = ".main-container .user .link-wrapper .channel"
+)
+
 func NewLogin(page *rod.Page) *LoginAction {
 	return &LoginAction{page: page}
 }
 
-func (a *LoginAction) CheckLoginStatus(ctx context.Context) (bool, error) {
+// urlOfExplore 主站的发现页，未登录时会弹出登录二维码
+func urlOfExplore() string {
+	return configs.SiteURL("/explore")
+}
+
+// LoginUser 当前登录的用户
+type LoginUser struct {
+	UserID   string `json:"user_id"`
+	RedID    string `json:"red_id,omitempty"`
+	Nickname string `json:"nickname,omitempty"`
+	Avatar   string `json:"avatar,omitempty"`
+}
+
+// LoginStatus 登录状态，User 为页面状态中读取到的当前用户，读取失败时为 nil
+type LoginStatus struct {
+	IsLoggedIn bool
+	User       *LoginUser
+}
+
+// CheckLoginStatus 检查登录状态，已登录时从 __INITIAL_STATE__ 中读取当前用户信息
+func (a *LoginAction) CheckLoginStatus(ctx context.Context) (*LoginStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
+
 	pp := a.page.Context(ctx)
-	pp.MustNavigate("https://www.xiaohongshu.com/explore").MustWaitLoad()
+	if err := navigate(pp, urlOfExplore()); err != nil {
+		return nil, stepErr(actionCheckLogin, "navigate", err)
+	}
 
-	time.Sleep(1 * time.Second)
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return nil, stepErr(actionCheckLogin, "wait_load", err)
+	}
 
-	exists, _, err := pp.Has(`.main-container .user .link-wrapper .channel`)
+	exists, _, err := pp.Has(selectorLoggedUser)
 	if err != nil {
-		return false, errors.Wrap(err, "check login status failed")
+		return nil, stepErr(actionCheckLogin, "find_user", err)
 	}
 
 	if !exists {
-		return false, errors.Wrap(err, "login status element not found")
+		return &LoginStatus{}, nil
 	}
 
-	return true, nil
+	user, err := currentUser(pp)
+	if err != nil {
+		logrus.Warnf("read current user failed: %v", err)
+	}
+
+	return &LoginStatus{IsLoggedIn: true, User: user}, nil
 }
 
+// currentUser 从 __INITIAL_STATE__.user.userInfo 读取当前登录用户
+func currentUser(page *rod.Page) (*LoginUser, error) {
+	result, err := page.Eval(`() => {
+		if (window.__INITIAL_STATE__ &&
+		    window.__INITIAL_STATE__.user &&
+		    window.__INITIAL_STATE__.user.userInfo) {
+			const userInfo = window.__INITIAL_STATE__.user.userInfo;
+			const data = userInfo.value !== undefined ? userInfo.value : userInfo._value;
+			if (data) {
+				return JSON.stringify(data);
+			}
+		}
+		return "";
+	}`)
+	if err != nil {
+		return nil, errors.Wrap(err, "eval user info failed")
+	}
+
+	raw := result.Value.String()
+	if raw == "" {
+		return nil, errors.New("user.userInfo not found in __INITIAL_STATE__")
+	}
+
+	return parseLoginUser(raw)
+}
+
+// parseLoginUser 解析 userInfo，兼容下划线和驼峰两种字段命名
+func parseLoginUser(raw string) (*LoginUser, error) {
+	var info struct {
+		Guest     bool   `json:"guest"`
+		UserID    string `json:"user_id"`
+		UserIDAlt string `json:"userId"`
+		RedID     string `json:"red_id"`
+		RedIDAlt  string `json:"redId"`
+		Nickname  string `json:"nickname"`
+		Images    string `json:"images"`
+		Avatar    string `json:"avatar"`
+	}
+	if err := json.Unmarshal([]byte(raw), &info); err != nil {
+		return nil, errors.Wrap(err, "unmarshal user info failed")
+	}
+
+	user := &LoginUser{
+		UserID:   firstNonEmpty(info.UserID, info.UserIDAlt),
+		RedID:    firstNonEmpty(info.RedID, info.RedIDAlt),
+		Nickname: info.Nickname,
+		Avatar:   firstNonEmpty(info.Images, info.Avatar),
+	}
+	if info.Guest || user.UserID == "" {
+		return nil, errors.New("user info is guest")
+	}
+
+	return user, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Login 打开登录弹窗并等待扫码登录完成，等待时长由调用方的 ctx 控制
 func (a *LoginAction) Login(ctx context.Context) error {
 	pp := a.page.Context(ctx)
 
 	// 导航到小红书首页，这会触发二维码弹窗
-	pp.MustNavigate("https://www.xiaohongshu.com/explore").MustWaitLoad()
+	if err := navigate(pp, urlOfExplore()); err != nil {
+		return stepErr(actionLogin, "navigate", err)
+	}
 
 	// 等待一小段时间让页面完全加载
-	time.Sleep(2 * time.Second)
+	if err := sleep(ctx, 2*time.Second); err != nil {
+		return stepErr(actionLogin, "wait_load", err)
+	}
 
 	// 检查是否已经登录
-	if exists, _, _ := pp.Has(".main-container .user .link-wrapper .channel"); exists {
+	if exists, _, _ := pp.Has(selectorLoggedUser); exists {
 		// 已经登录，直接返回
 		return nil
 	}
 
 	// 等待扫码成功提示或者登录完成
 	// 这里我们等待登录成功的元素出现，这样更简单可靠
-	pp.MustElement(".main-container .user .link-wrapper .channel")
+	if _, err := pp.Element(selectorLoggedUser); err != nil {
+		return stepErr(actionLogin, "wait_scan", err)
+	}
 
 	return nil
 }
 
 func (a *LoginAction) FetchQrcodeImage(ctx context.Context) (string, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
+
 	pp := a.page.Context(ctx)
 
 	// 导航到小红书首页，这会触发二维码弹窗
-	pp.MustNavigate("https://www.xiaohongshu.com/explore").MustWaitLoad()
+	if err := navigate(pp, urlOfExplore()); err != nil {
+		return "", false, stepErr(actionFetchQrcode, "navigate", err)
+	}
 
 	// 等待一小段时间让页面完全加载
-	time.Sleep(2 * time.Second)
+	if err := sleep(ctx, 2*time.Second); err != nil {
+		return "", false, stepErr(actionFetchQrcode, "wait_load", err)
+	}
 
 	// 检查是否已经登录
-	if exists, _, _ := pp.Has(".main-container .user .link-wrapper .channel"); exists {
+	if exists, _, _ := pp.Has(selectorLoggedUser); exists {
 		return "", true, nil
 	}
 
 	// 获取二维码图片
-	src, err := pp.MustElement(".login-container .qrcode-img").Attribute("src")
+	img, err := pp.Element(".login-container .qrcode-img")
+	if err != nil {
+		return "", false, stepErr(actionFetchQrcode, "find_qrcode", err)
+	}
+	src, err := img.Attribute("src")
 	if err != nil {
-		return "", false, errors.Wrap(err, "get qrcode src failed")
+		return "", false, stepErr(actionFetchQrcode, "read_qrcode", err)
 	}
 	if src == nil || len(*src) == 0 {
-		return "", false, errors.New("qrcode src is empty")
+		return "", false, stepErr(actionFetchQrcode, "read_qrcode", errors.New("qrcode src is empty"))
 	}
 
 	return *src, false, nil
@@ -92,10 +219,97 @@ func (a *LoginAction) WaitForLogin(ctx context.Context) bool {
 		case <-ctx.Done():
 			return false
 		case <-ticker.C:
-			el, err := pp.Element(".main-container .user .link-wrapper .channel")
+			el, err := pp.Element(selectorLoggedUser)
 			if err == nil && el != nil {
 				return true
 			}
 		}
 	}
 }
+
+// Logout 在网站上退出登录：打开侧边栏的「更多」菜单并点击「退出登录」。
+// 当前未登录时返回 false。
+func (a *LoginAction) Logout(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
+
+	pp := a.page.Context(ctx)
+
+	if err := navigate(pp, urlOfExplore()); err != nil {
+		return false, stepErr(actionLogout, "navigate", err)
+	}
+
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return false, stepErr(actionLogout, "wait_load", err)
+	}
+
+	if exists, _, _ := pp.Has(selectorLoggedUser); !exists {
+		return false, nil
+	}
+
+	tp := pp.Timeout(10 * time.Second)
+
+	more, err := tp.ElementR(".side-bar div, .side-bar span", "^更多$")
+	if err != nil {
+		return false, stepErr(actionLogout, "find_more_menu", err)
+	}
+	if err := more.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return false, stepErr(actionLogout, "click_more_menu", err)
+	}
+
+	item, err := tp.ElementR("div, span", "^退出登录$")
+	if err != nil {
+		return false, stepErr(actionLogout, "find_logout_item", err)
+	}
+	if err := item.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return false, stepErr(actionLogout, "click_logout_item", err)
+	}
+
+	if err := sleep(ctx, 2*time.Second); err != nil {
+		return false, stepErr(actionLogout, "wait_logout", err)
+	}
+
+	return true, nil
+}
+
+// QrcodeState 扫码登录二维码的状态
+type QrcodeState string
+
+const (
+	QrcodeWaiting  QrcodeState = "waiting"   // 等待扫码
+	QrcodeScanned  QrcodeState = "scanned"   // 已扫码，等待在 App 上确认
+	QrcodeExpired  QrcodeState = "expired"   // 二维码已过期
+	QrcodeLoggedIn QrcodeState = "logged_in" // 登录成功
+)
+
+// GetQrcodeState 读取登录弹窗中二维码的当前状态
+func (a *LoginAction) GetQrcodeState(ctx context.Context) (QrcodeState, error) {
+	pp := a.page.Context(ctx)
+
+	if exists, _, err := pp.Has(selectorLoggedUser); err != nil {
+		return "", errors.Wrap(err, "check login element failed")
+	} else if exists {
+		return QrcodeLoggedIn, nil
+	}
+
+	result, err := pp.Eval(`() => {
+		const container = document.querySelector('.login-container');
+		if (!container) {
+			return "";
+		}
+		return container.innerText || "";
+	}`)
+	if err != nil {
+		return "", errors.Wrap(err, "read login container failed")
+	}
+
+	text := result.Value.String()
+	switch {
+	case strings.Contains(text, "过期") || strings.Contains(text, "失效"):
+		return QrcodeExpired, nil
+	case strings.Contains(text, "扫码成功") || strings.Contains(text, "已扫码") || strings.Contains(text, "确认登录"):
+		return QrcodeScanned, nil
+	default:
+		return QrcodeWaiting, nil
+	}
+}
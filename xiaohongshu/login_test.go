@@ -0,0 +1,20 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLoginUser(t *testing.T) {
+	user, err := parseLoginUser(`{"guest":false,"user_id":"u1","red_id":"r1","nickname":"小红薯","images":"https://example.com/a.jpg"}`)
+	require.NoError(t, err)
+	require.Equal(t, &LoginUser{UserID: "u1", RedID: "r1", Nickname: "小红薯", Avatar: "https://example.com/a.jpg"}, user)
+
+	user, err = parseLoginUser(`{"userId":"u2","redId":"r2","nickname":"n","avatar":"a"}`)
+	require.NoError(t, err)
+	require.Equal(t, &LoginUser{UserID: "u2", RedID: "r2", Nickname: "n", Avatar: "a"}, user)
+
+	_, err = parseLoginUser(`{"guest":true}`)
+	require.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package mocksite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+)
+
+// NewBrowser 启动模拟站点和访问它的本地无头浏览器，用于端到端测试，找不到本地 Chrome 时跳过测试。
+// 站点地址和创作服务平台地址在测试期间指向模拟站点，站点和浏览器在测试结束时关闭。
+func NewBrowser(t testing.TB, opts ...browser.Option) (*Site, *browser.Browser) {
+	t.Helper()
+	lookChrome(t)
+
+	site := New()
+	t.Cleanup(site.Close)
+
+	if err := configs.SetSiteURL(site.URL()); err != nil {
+		t.Fatal(err)
+	}
+	if err := configs.SetCreatorURL(site.URL()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = configs.SetSiteURL("")
+		_ = configs.SetCreatorURL("")
+	})
+
+	return site, site.NewBrowser(t, opts...)
+}
+
+// NewBrowser 再启动一个本地无头浏览器，用于同一个测试中需要多个浏览器的情况。
+// 默认使用临时目录中独立的 cookies 文件，不会读写本机的登录态；opts 在默认选项之后生效。
+func (s *Site) NewBrowser(t testing.TB, opts ...browser.Option) *browser.Browser {
+	t.Helper()
+	bin := lookChrome(t)
+
+	cookier := cookies.NewLoadCookie(filepath.Join(t.TempDir(), "cookies.json"))
+	opts = append([]browser.Option{browser.WithBinPath(bin), browser.WithCookier(cookier)}, opts...)
+
+	b := browser.NewBrowser(true, opts...)
+	t.Cleanup(b.Close)
+	return b
+}
+
+// lookChrome 查找本地浏览器，找不到时跳过测试
+func lookChrome(t testing.TB) string {
+	t.Helper()
+
+	bin, ok := LookChrome()
+	if !ok {
+		t.Skip("SKIP: 没有找到本地 Chrome，可以通过 ROD_BROWSER_BIN 指定")
+	}
+	return bin
+}
@@ -0,0 +1,310 @@
+package mocksite
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// loadFixtures 加载默认的用户和笔记
+func (s *Site) loadFixtures() {
+	users := []*User{
+		{
+			UserID: "5f3a1b2c000000000101d8e4", RedID: "95270001", Nickname: "小红薯测试号",
+			Desc: "用于离线测试的账号", Gender: 1, IPLocation: "上海",
+			Follows: 12, Fans: 34, Interaction: 56,
+		},
+		{
+			UserID: "60a7c3d4000000000100a1b2", RedID: "95270002", Nickname: "爱做饭的阿杰",
+			Desc: "一日三餐，记录生活", Gender: 0, IPLocation: "广东",
+			Follows: 210, Fans: 10234, Interaction: 88231,
+		},
+		{
+			UserID: "62b8e5f6000000001001c3d4", RedID: "95270003", Nickname: "城市漫游指南",
+			Desc: "周末去哪儿", Gender: 1, IPLocation: "北京",
+			Follows: 98, Fans: 5421, Interaction: 30112,
+		},
+	}
+	for _, u := range users {
+		u.Avatar = "/images/avatar-" + u.UserID + ".png"
+		s.users[u.UserID] = u
+	}
+	s.me = users[0].UserID
+
+	now := time.Now()
+	notes := []Note{
+		{
+			ID: "66f1a2b3000000001e02a4c1", XsecToken: "ABmockToken0001", Type: "normal",
+			Title: "十分钟搞定的番茄炒蛋", Desc: "番茄先炒出汁再下蛋，酸甜刚好 #家常菜[话题]#",
+			Time: now.Add(-72 * time.Hour), IPLocation: "广东", AuthorID: users[1].UserID,
+			LikedCount: 1024, CollectedCount: 512, SharedCount: 32,
+			Comments: []Comment{
+				{ID: "66f1b0c1000000001c01d2e3", UserID: users[2].UserID, Content: "今晚就做！", CreateTime: now.Add(-48 * time.Hour), IPLocation: "北京", LikeCount: 3},
+			},
+		},
+		{
+			ID: "66f2b3c4000000001e03b5d2", XsecToken: "ABmockToken0002", Type: "video",
+			Title: "北京胡同骑行路线", Desc: "从鼓楼出发，一路骑到后海", Duration: 95,
+			Time: now.Add(-48 * time.Hour), IPLocation: "北京", AuthorID: users[2].UserID,
+			LikedCount: 233, CollectedCount: 120, SharedCount: 9,
+		},
+		{
+			ID: "66f3c4d5000000001e01c6e3", XsecToken: "ABmockToken0003", Type: "normal",
+			Title: "上海周末咖啡馆合集", Desc: "安福路附近五家安静的咖啡馆",
+			Time: now.Add(-24 * time.Hour), IPLocation: "上海", AuthorID: users[2].UserID,
+			Liked: true, LikedCount: 89, CollectedCount: 40, SharedCount: 2,
+		},
+		{
+			ID: "66f4d5e6000000001e00d7f4", XsecToken: "ABmockToken0004", Type: "normal",
+			Title: "我的第一篇笔记", Desc: "你好，小红书",
+			Time: now.Add(-2 * time.Hour), IPLocation: "上海", AuthorID: users[0].UserID,
+			LikedCount: 5, CollectedCount: 1,
+		},
+	}
+	for i := range notes {
+		notes[i].Images = []string{"/images/" + notes[i].ID + ".png"}
+		s.addNote(notes[i])
+	}
+}
+
+// ref 与站点序列化 Vue ref 的结构保持一致，xiaohongshu 包会读取 value 或 _value
+func ref(v any) map[string]any {
+	return map[string]any{
+		"__v_isShallow": false,
+		"__v_isRef":     true,
+		"_rawValue":     v,
+		"_value":        v,
+	}
+}
+
+func (s *Site) userCard(id string) map[string]any {
+	u := s.users[id]
+	if u == nil {
+		u = &User{UserID: id}
+	}
+	return map[string]any{
+		"userId":   u.UserID,
+		"nickname": u.Nickname,
+		"nickName": u.Nickname,
+		"avatar":   u.Avatar,
+	}
+}
+
+func interactInfo(n *Note) map[string]any {
+	return map[string]any{
+		"liked":          n.Liked,
+		"likedCount":     strconv.Itoa(n.LikedCount),
+		"collected":      n.Collected,
+		"collectedCount": strconv.Itoa(n.CollectedCount),
+		"commentCount":   strconv.Itoa(len(n.Comments)),
+		"sharedCount":    strconv.Itoa(n.SharedCount),
+		"followed":       false,
+		"relation":       "none",
+	}
+}
+
+// feedItem 发现页、搜索页和用户主页中的笔记卡片
+func (s *Site) feedItem(n *Note, index int) map[string]any {
+	cover := ""
+	if len(n.Images) > 0 {
+		cover = n.Images[0]
+	}
+
+	card := map[string]any{
+		"type":         n.Type,
+		"displayTitle": n.Title,
+		"user":         s.userCard(n.AuthorID),
+		"interactInfo": interactInfo(n),
+		"cover": map[string]any{
+			"width":      1080,
+			"height":     1440,
+			"url":        cover,
+			"fileId":     "",
+			"urlPre":     cover,
+			"urlDefault": cover,
+			"infoList": []map[string]any{
+				{"imageScene": "WB_PRV", "url": cover},
+				{"imageScene": "WB_DFT", "url": cover},
+			},
+		},
+	}
+	if n.Type == "video" {
+		card["video"] = map[string]any{"capa": map[string]any{"duration": n.Duration}}
+	}
+
+	return map[string]any{
+		"id":        n.ID,
+		"xsecToken": n.XsecToken,
+		"modelType": "note",
+		"noteCard":  card,
+		"index":     index,
+	}
+}
+
+func (s *Site) feedItems(notes []*Note) []map[string]any {
+	items := make([]map[string]any, 0, len(notes))
+	for i, n := range notes {
+		items = append(items, s.feedItem(n, i))
+	}
+	return items
+}
+
+func (s *Site) comment(c Comment) map[string]any {
+	return map[string]any{
+		"id":              c.ID,
+		"noteId":          c.NoteID,
+		"content":         c.Content,
+		"likeCount":       strconv.Itoa(c.LikeCount),
+		"createTime":      c.CreateTime.UnixMilli(),
+		"ipLocation":      c.IPLocation,
+		"liked":           false,
+		"userInfo":        s.userCard(c.UserID),
+		"subCommentCount": "0",
+		"subComments":     []any{},
+		"showTags":        []string{},
+	}
+}
+
+// noteDetail 详情页 note.noteDetailMap 中的一项
+func (s *Site) noteDetail(n *Note) map[string]any {
+	images := make([]map[string]any, 0, len(n.Images))
+	for _, img := range n.Images {
+		images = append(images, map[string]any{
+			"width":      1080,
+			"height":     1440,
+			"urlDefault": img,
+			"urlPre":     img,
+		})
+	}
+
+	comments, cursor, hasMore := s.commentPage(n, 0)
+
+	return map[string]any{
+		"note": map[string]any{
+			"noteId":       n.ID,
+			"xsecToken":    n.XsecToken,
+			"title":        n.Title,
+			"desc":         n.Desc,
+			"type":         n.Type,
+			"time":         n.Time.UnixMilli(),
+			"ipLocation":   n.IPLocation,
+			"user":         s.userCard(n.AuthorID),
+			"interactInfo": interactInfo(n),
+			"imageList":    images,
+		},
+		"comments": map[string]any{
+			"list":    comments,
+			"cursor":  cursor,
+			"hasMore": hasMore,
+		},
+	}
+}
+
+// commentPageSize 每页的评论数，详情页首屏只有第一页，滚动评论区后加载下一页
+const commentPageSize = 10
+
+// commentPage 从第 start 条开始的一页评论，cursor 为下一页的起始位置
+func (s *Site) commentPage(n *Note, start int) (comments []map[string]any, cursor string, hasMore bool) {
+	start = min(start, len(n.Comments))
+	end := min(start+commentPageSize, len(n.Comments))
+
+	comments = make([]map[string]any, 0, end-start)
+	for _, c := range n.Comments[start:end] {
+		c.NoteID = n.ID
+		comments = append(comments, s.comment(c))
+	}
+	if end < len(n.Comments) {
+		return comments, strconv.Itoa(end), true
+	}
+	return comments, "", false
+}
+
+// userNotesPageSize 用户主页每页的笔记数，首屏只有第一页，滚动到底部后加载下一页
+const userNotesPageSize = 30
+
+// userPostedNote 用户主页笔记接口返回的笔记卡片，笔记 ID 和 xsec_token 在卡片上
+func (s *Site) userPostedNote(n *Note) map[string]any {
+	card := s.feedItem(n, 0)["noteCard"].(map[string]any)
+	card["noteId"] = n.ID
+	card["xsecToken"] = n.XsecToken
+	return card
+}
+
+// userInfo 当前登录用户，未登录时为访客
+func (s *Site) userInfo(loggedIn bool) map[string]any {
+	if !loggedIn {
+		return map[string]any{"guest": true}
+	}
+
+	u := s.users[s.me]
+	return map[string]any{
+		"guest":    false,
+		"user_id":  u.UserID,
+		"red_id":   u.RedID,
+		"nickname": u.Nickname,
+		"desc":     u.Desc,
+		"gender":   u.Gender,
+		"images":   u.Avatar,
+		"imageb":   u.Avatar,
+	}
+}
+
+// userPageData 用户主页的基本信息和互动数据
+func userPageData(u *User) map[string]any {
+	return map[string]any{
+		"basicInfo": map[string]any{
+			"gender":     u.Gender,
+			"ipLocation": u.IPLocation,
+			"desc":       u.Desc,
+			"imageb":     u.Avatar,
+			"nickname":   u.Nickname,
+			"images":     u.Avatar,
+			"redId":      u.RedID,
+		},
+		"interactions": []map[string]any{
+			{"type": "follows", "name": "关注", "count": strconv.Itoa(u.Follows)},
+			{"type": "fans", "name": "粉丝", "count": strconv.Itoa(u.Fans)},
+			{"type": "interaction", "name": "获赞与收藏", "count": strconv.Itoa(u.Interaction)},
+		},
+		"tags": []any{},
+	}
+}
+
+// snakeKeys 将页面状态使用的驼峰字段转换为接口响应使用的下划线字段，如 xsecToken -> xsec_token
+func snakeKeys(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[snakeCase(k)] = snakeKeys(val)
+		}
+		return m
+	case []map[string]any:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, snakeKeys(item))
+		}
+		return items
+	case []any:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, snakeKeys(item))
+		}
+		return items
+	default:
+		return v
+	}
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			b.WriteByte('_')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
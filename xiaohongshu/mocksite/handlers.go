@@ -0,0 +1,631 @@
+package mocksite
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func (s *Site) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	// 主站页面
+	mux.HandleFunc("GET /{$}", s.limited(s.handleExplore))
+	mux.HandleFunc("GET /explore", s.limited(s.handleExplore))
+	mux.HandleFunc("GET /explore/{id}", s.limited(s.handleNoteDetail))
+	mux.HandleFunc("GET /search_result", s.limited(s.handleSearch))
+	mux.HandleFunc("GET /user/profile/{id}", s.limited(s.handleUserProfile))
+	mux.HandleFunc("GET /images/{name}", handleImage)
+	mux.HandleFunc("GET /404", handleErrorPage)
+
+	// 创作服务平台页面
+	mux.HandleFunc("GET /publish/publish", s.handlePublishPage)
+	mux.HandleFunc("GET /login", handleCreatorLogin)
+
+	// 页面脚本调用的接口
+	mux.HandleFunc("GET /api/sns/web/v1/login/qrcode/status", s.handleQrcodeStatus)
+	mux.HandleFunc("POST /api/sns/web/v1/user/logout", s.handleLogout)
+	mux.HandleFunc("POST /api/sns/web/v1/note/like", s.handleInteract(func(n *Note) { setLiked(n, true) }))
+	mux.HandleFunc("POST /api/sns/web/v1/note/dislike", s.handleInteract(func(n *Note) { setLiked(n, false) }))
+	mux.HandleFunc("POST /api/sns/web/v1/note/collect", s.handleInteract(func(n *Note) { setCollected(n, true) }))
+	mux.HandleFunc("POST /api/sns/web/v1/note/uncollect", s.handleInteract(func(n *Note) { setCollected(n, false) }))
+	mux.HandleFunc("POST /api/sns/web/v1/comment/post", s.handlePostComment)
+	mux.HandleFunc("POST /api/sns/web/v1/homefeed", s.handleHomefeed)
+	mux.HandleFunc("POST /api/sns/web/v1/search/notes", s.handleSearchNotes)
+	mux.HandleFunc("POST /api/sns/web/v1/feed", s.handleFeed)
+	mux.HandleFunc("GET /api/sns/web/v2/comment/page", s.handleCommentPage)
+	mux.HandleFunc("GET /api/sns/web/v1/user_posted", s.handleUserPosted)
+	mux.HandleFunc("POST /web_api/sns/v2/note", s.handlePublish)
+
+	return mux
+}
+
+// pageData 页面模板数据
+type pageData struct {
+	Title    string
+	Creator  bool // 创作服务平台页面，没有主站的侧边栏和登录弹窗
+	LoggedIn bool
+	Me       User
+	Qrcode   template.URL
+	State    map[string]any
+	Message  string // 错误页的提示
+	Captcha  bool   // 弹出安全验证层
+
+	NoteID   string
+	Filters  []searchFilter
+	TabFeeds [][]map[string]any
+}
+
+// newPageData 调用方需要持有锁。未登录时生成新的登录二维码。
+func (s *Site) newPageData(title string, loggedIn bool) *pageData {
+	data := &pageData{
+		Title:    title,
+		LoggedIn: loggedIn,
+		Captcha:  s.captcha,
+		Me:       *s.users[s.me],
+		State: map[string]any{
+			"global": map[string]any{"appSettings": map[string]any{"notificationInterval": 30}},
+			"user": map[string]any{
+				"loggedIn": loggedIn,
+				"userInfo": ref(s.userInfo(loggedIn)),
+			},
+		},
+	}
+
+	if !loggedIn {
+		s.qrcode = QrcodeWaiting
+		data.Qrcode = template.URL(qrcodeDataURL())
+	}
+	return data
+}
+
+// 错误页的 error_code，与真实站点一致
+const (
+	errorCodeNoteNotFound     = "-510001"
+	errorCodeRateLimited      = "300013"
+	errorCodeXsecTokenInvalid = "300031"
+)
+
+// redirectError 与真实站点一样重定向到错误页，错误原因放在 error_code 和 error_msg 参数中
+func redirectError(w http.ResponseWriter, r *http.Request, code, msg string) {
+	q := url.Values{}
+	q.Set("source", r.URL.Path)
+	q.Set("error_code", code)
+	q.Set("error_msg", msg)
+	http.Redirect(w, r, "/404?"+q.Encode(), http.StatusFound)
+}
+
+// limited 开启频次限制时主站页面都重定向到错误页
+func (s *Site) limited(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		limited := s.rateLimited
+		s.mu.Unlock()
+
+		if limited {
+			redirectError(w, r, errorCodeRateLimited, "访问频次异常，请勿频繁操作或重启试试")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func handleErrorPage(w http.ResponseWriter, r *http.Request) {
+	render(w, errorPage, &pageData{Title: "小红书", Creator: true, Message: r.URL.Query().Get("error_msg")})
+}
+
+func handleCreatorLogin(w http.ResponseWriter, r *http.Request) {
+	render(w, errorPage, &pageData{Title: "小红书创作服务平台", Creator: true, Message: "请登录后使用创作服务平台"})
+}
+
+func (s *Site) handleExplore(w http.ResponseWriter, r *http.Request) {
+	loggedIn := s.loggedIn(r)
+
+	s.mu.Lock()
+	data := s.newPageData("小红书 - 你的生活指南", loggedIn)
+	data.State["feed"] = map[string]any{
+		"currentChannel": "homefeed_recommend",
+		"feeds":          ref(s.homefeed(0)),
+	}
+	s.mu.Unlock()
+
+	render(w, explorePage, data)
+}
+
+func (s *Site) handleNoteDetail(w http.ResponseWriter, r *http.Request) {
+	loggedIn := s.loggedIn(r)
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	n := s.findNote(id)
+	if n == nil {
+		s.mu.Unlock()
+		redirectError(w, r, errorCodeNoteNotFound, "笔记不存在")
+		return
+	}
+	if r.URL.Query().Get("xsec_token") != n.XsecToken {
+		s.mu.Unlock()
+		redirectError(w, r, errorCodeXsecTokenInvalid, "当前笔记暂时无法浏览")
+		return
+	}
+
+	data := s.newPageData(n.Title+" - 小红书", loggedIn)
+	data.NoteID = id
+	data.State["note"] = map[string]any{
+		"currentNoteId": id,
+		"noteDetailMap": map[string]any{id: s.noteDetail(n)},
+	}
+	s.mu.Unlock()
+
+	render(w, noteDetailPage, data)
+}
+
+// homefeedPageSize 首页推荐每页的笔记数，首屏只有第一页，页面加载后通过接口请求下一页
+const homefeedPageSize = 20
+
+type homefeedRequest struct {
+	CursorScore string `json:"cursor_score"`
+}
+
+// handleHomefeed 首页推荐加载下一页的接口，cursor_score 为起始位置
+func (s *Site) handleHomefeed(w http.ResponseWriter, r *http.Request) {
+	var req homefeedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeFailure(w, http.StatusBadRequest, "参数错误")
+		return
+	}
+	start, err := strconv.Atoi(req.CursorScore)
+	if err != nil || start < 0 {
+		writeFailure(w, http.StatusBadRequest, "参数错误")
+		return
+	}
+
+	s.mu.Lock()
+	items := s.homefeed(start)
+	end := start + len(items)
+	hasMore := end < len(s.notes)
+	s.mu.Unlock()
+
+	cursor := ""
+	if hasMore {
+		cursor = strconv.Itoa(end)
+	}
+	writeSuccess(w, map[string]any{"items": snakeKeys(items), "cursor_score": cursor, "has_more": hasMore})
+}
+
+// homefeed 从第 start 条开始的一页推荐，调用方需要持有锁
+func (s *Site) homefeed(start int) []map[string]any {
+	start = min(start, len(s.notes))
+	end := min(start+homefeedPageSize, len(s.notes))
+
+	items := make([]map[string]any, 0, end-start)
+	for i, n := range s.notes[start:end] {
+		items = append(items, s.feedItem(n, start+i))
+	}
+	return items
+}
+
+type feedRequest struct {
+	SourceNoteID string `json:"source_note_id"`
+	XsecToken    string `json:"xsec_token"`
+}
+
+// handleFeed 详情页加载后请求的笔记详情接口，与线上接口一样返回下划线风格的字段
+func (s *Site) handleFeed(w http.ResponseWriter, r *http.Request) {
+	var req feedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeFailure(w, http.StatusBadRequest, "参数错误")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.findNote(req.SourceNoteID)
+	if n == nil {
+		writeFailure(w, http.StatusNotFound, "笔记不存在")
+		return
+	}
+	if req.XsecToken != n.XsecToken {
+		writeFailure(w, http.StatusForbidden, "当前笔记暂时无法浏览")
+		return
+	}
+	item := map[string]any{
+		"id":        n.ID,
+		"modelType": "note",
+		"noteCard":  s.noteDetail(n)["note"],
+	}
+	writeSuccess(w, map[string]any{"items": snakeKeys([]any{item})})
+}
+
+// searchFilter 搜索页的筛选组，顺序与 xiaohongshu 包中的 filterOptionsMap 一致
+type searchFilter struct {
+	Title string
+	Tags  []string
+}
+
+var searchFilters = []searchFilter{
+	{Title: "排序依据", Tags: []string{"综合", "最新", "最多点赞", "最多评论", "最多收藏"}},
+	{Title: "笔记类型", Tags: []string{"不限", "视频", "图文"}},
+	{Title: "发布时间", Tags: []string{"不限", "一天内", "一周内", "半年内"}},
+	{Title: "搜索范围", Tags: []string{"不限", "已看过", "未看过", "已关注"}},
+	{Title: "位置距离", Tags: []string{"不限", "同城", "附近"}},
+}
+
+func (s *Site) handleSearch(w http.ResponseWriter, r *http.Request) {
+	loggedIn := s.loggedIn(r)
+	keyword := r.URL.Query().Get("keyword")
+
+	s.mu.Lock()
+	data := s.newPageData(keyword+" - 小红书搜索", loggedIn)
+	data.Filters = searchFilters
+	items, hasMore := s.searchNotes(keyword, 1)
+	data.State["search"] = map[string]any{
+		"keyword": keyword,
+		"feeds":   ref(items),
+		"hasMore": hasMore,
+	}
+	s.mu.Unlock()
+
+	render(w, searchPage, data)
+}
+
+// searchPageSize 搜索结果每页的笔记数，首屏只有第一页，滚动到底部后加载下一页
+const searchPageSize = 20
+
+type searchNotesRequest struct {
+	Keyword string `json:"keyword"`
+	Page    int    `json:"page"`
+}
+
+// handleSearchNotes 搜索结果滚动加载的接口，与线上接口一样返回下划线风格的字段
+func (s *Site) handleSearchNotes(w http.ResponseWriter, r *http.Request) {
+	var req searchNotesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Page < 1 {
+		writeFailure(w, http.StatusBadRequest, "参数错误")
+		return
+	}
+
+	s.mu.Lock()
+	items, hasMore := s.searchNotes(req.Keyword, req.Page)
+	s.mu.Unlock()
+
+	writeSuccess(w, map[string]any{"items": snakeKeys(items), "has_more": hasMore})
+}
+
+// searchNotes 返回第 page 页（从 1 开始）的搜索结果，调用方需要持有锁
+func (s *Site) searchNotes(keyword string, page int) ([]map[string]any, bool) {
+	notes := s.matchNotes(keyword)
+	start := min((page-1)*searchPageSize, len(notes))
+	end := min(start+searchPageSize, len(notes))
+
+	items := make([]map[string]any, 0, end-start)
+	for i, n := range notes[start:end] {
+		items = append(items, s.feedItem(n, start+i))
+	}
+	return items, end < len(notes)
+}
+
+func (s *Site) handleUserProfile(w http.ResponseWriter, r *http.Request) {
+	loggedIn := s.loggedIn(r)
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	data := s.newPageData("小红书 - 用户主页", loggedIn)
+
+	if u := s.users[id]; u != nil {
+		data.Title = u.Nickname + " - 小红书"
+
+		var posted, collected, liked []*Note
+		for _, n := range s.notes {
+			if n.AuthorID == id {
+				posted = append(posted, n)
+			}
+			// 只有自己的主页可以看到收藏和点赞
+			if loggedIn && id == s.me {
+				if n.Collected {
+					collected = append(collected, n)
+				}
+				if n.Liked {
+					liked = append(liked, n)
+				}
+			}
+		}
+
+		// 与真实站点一致，收藏和点赞在切换标签后才加载，笔记首屏只有第一页
+		hasMore := len(posted) > userNotesPageSize
+		data.TabFeeds = [][]map[string]any{s.feedItems(posted[:min(userNotesPageSize, len(posted))]), s.feedItems(collected), s.feedItems(liked)}
+
+		user := data.State["user"].(map[string]any)
+		user["userPageData"] = ref(userPageData(u))
+		user["notes"] = ref([][]map[string]any{data.TabFeeds[0], {}, {}})
+		user["noteQueries"] = []map[string]any{
+			{"cursor": strconv.Itoa(min(userNotesPageSize, len(posted))), "hasMore": hasMore},
+			{"cursor": "", "hasMore": false},
+			{"cursor": "", "hasMore": false},
+		}
+		user["activeTab"] = 0
+	}
+	s.mu.Unlock()
+
+	render(w, userProfilePage, data)
+}
+
+// handleCommentPage 详情页滚动评论区时加载下一页评论的接口，cursor 为起始位置
+func (s *Site) handleCommentPage(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.Atoi(r.URL.Query().Get("cursor"))
+	if err != nil || start < 0 {
+		writeFailure(w, http.StatusBadRequest, "参数错误")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.findNote(r.URL.Query().Get("note_id"))
+	if n == nil {
+		writeFailure(w, http.StatusNotFound, "笔记不存在")
+		return
+	}
+	comments, cursor, hasMore := s.commentPage(n, start)
+	writeSuccess(w, map[string]any{"comments": snakeKeys(comments), "cursor": cursor, "has_more": hasMore})
+}
+
+// handleUserPosted 用户主页滚动到底部时加载下一页笔记的接口，cursor 为起始位置
+func (s *Site) handleUserPosted(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.Atoi(r.URL.Query().Get("cursor"))
+	if err != nil || start < 0 {
+		writeFailure(w, http.StatusBadRequest, "参数错误")
+		return
+	}
+	userID := r.URL.Query().Get("user_id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var posted []*Note
+	for _, n := range s.notes {
+		if n.AuthorID == userID {
+			posted = append(posted, n)
+		}
+	}
+	start = min(start, len(posted))
+	end := min(start+userNotesPageSize, len(posted))
+
+	notes := make([]map[string]any, 0, end-start)
+	for _, n := range posted[start:end] {
+		notes = append(notes, s.userPostedNote(n))
+	}
+	cursor := ""
+	if end < len(posted) {
+		cursor = strconv.Itoa(end)
+	}
+	writeSuccess(w, map[string]any{"notes": snakeKeys(notes), "cursor": cursor, "has_more": end < len(posted)})
+}
+
+func (s *Site) handlePublishPage(w http.ResponseWriter, r *http.Request) {
+	loggedIn := s.loggedIn(r)
+	if !loggedIn {
+		// 创作服务平台未登录时跳转到登录页
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return
+	}
+
+	s.mu.Lock()
+	data := s.newPageData("小红书创作服务平台", loggedIn)
+	data.Creator = true
+	s.mu.Unlock()
+
+	render(w, publishPage, data)
+}
+
+func (s *Site) handleQrcodeStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	status := s.qrcode
+	token := ""
+	if status == QrcodeConfirmed {
+		token = s.newSession()
+		s.qrcode = QrcodeWaiting
+	}
+	s.mu.Unlock()
+
+	if token != "" {
+		http.SetCookie(w, &http.Cookie{Name: SessionCookieName, Value: token, Path: "/", HttpOnly: true})
+	}
+	writeSuccess(w, map[string]any{"code_status": status})
+}
+
+func (s *Site) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if ck, err := r.Cookie(SessionCookieName); err == nil {
+		s.mu.Lock()
+		delete(s.sessions, ck.Value)
+		s.mu.Unlock()
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: SessionCookieName, Value: "", Path: "/", MaxAge: -1})
+	writeSuccess(w, nil)
+}
+
+// interactRequest 点赞、收藏接口的请求体，不同接口使用的字段名不同
+type interactRequest struct {
+	NoteOID string `json:"note_oid"`
+	NoteID  string `json:"note_id"`
+	NoteIDs string `json:"note_ids"`
+}
+
+func (r interactRequest) id() string {
+	for _, id := range []string{r.NoteOID, r.NoteID, r.NoteIDs} {
+		if id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func (s *Site) handleInteract(apply func(n *Note)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.loggedIn(r) {
+			writeNotLoggedIn(w)
+			return
+		}
+
+		var req interactRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeFailure(w, http.StatusBadRequest, "参数错误")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		n := s.findNote(req.id())
+		if n == nil {
+			writeFailure(w, http.StatusNotFound, "笔记不存在")
+			return
+		}
+		apply(n)
+		writeSuccess(w, map[string]any{"liked": n.Liked, "collected": n.Collected})
+	}
+}
+
+func setLiked(n *Note, liked bool) {
+	if n.Liked == liked {
+		return
+	}
+	n.Liked = liked
+	if liked {
+		n.LikedCount++
+	} else if n.LikedCount > 0 {
+		n.LikedCount--
+	}
+}
+
+func setCollected(n *Note, collected bool) {
+	if n.Collected == collected {
+		return
+	}
+	n.Collected = collected
+	if collected {
+		n.CollectedCount++
+	} else if n.CollectedCount > 0 {
+		n.CollectedCount--
+	}
+}
+
+func (s *Site) handlePostComment(w http.ResponseWriter, r *http.Request) {
+	if !s.loggedIn(r) {
+		writeNotLoggedIn(w)
+		return
+	}
+
+	var req struct {
+		NoteID  string `json:"note_id"`
+		Content string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Content) == "" {
+		writeFailure(w, http.StatusBadRequest, "评论内容不能为空")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.findNote(req.NoteID)
+	if n == nil {
+		writeFailure(w, http.StatusNotFound, "笔记不存在")
+		return
+	}
+
+	c := Comment{
+		ID:         newID(12),
+		NoteID:     n.ID,
+		UserID:     s.me,
+		Content:    strings.TrimSpace(req.Content),
+		CreateTime: time.Now(),
+		IPLocation: s.users[s.me].IPLocation,
+	}
+	n.Comments = append([]Comment{c}, n.Comments...)
+
+	logrus.Debugf("mocksite: comment on %s: %s", n.ID, c.Content)
+	writeSuccess(w, map[string]any{"comment": s.comment(c)})
+}
+
+func (s *Site) handlePublish(w http.ResponseWriter, r *http.Request) {
+	if !s.loggedIn(r) {
+		writeNotLoggedIn(w)
+		return
+	}
+
+	var req struct {
+		Type   string   `json:"type"`
+		Title  string   `json:"title"`
+		Desc   string   `json:"desc"`
+		Topics []string `json:"topics"`
+		Files  []string `json:"files"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeFailure(w, http.StatusBadRequest, "参数错误")
+		return
+	}
+	if len(req.Files) == 0 {
+		writeFailure(w, http.StatusBadRequest, "请上传图片或视频")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.addNote(Note{
+		Type:       req.Type,
+		Title:      req.Title,
+		Desc:       req.Desc,
+		IPLocation: s.users[s.me].IPLocation,
+	})
+	n.Images = []string{"/images/" + n.ID + ".png"}
+
+	s.publications = append(s.publications, Publication{
+		NoteID:  n.ID,
+		Type:    n.Type,
+		Title:   req.Title,
+		Content: req.Desc,
+		Topics:  req.Topics,
+		Files:   req.Files,
+		Time:    n.Time,
+	})
+
+	logrus.Debugf("mocksite: published %s note %s: %s", n.Type, n.ID, n.Title)
+	writeSuccess(w, map[string]any{"id": n.ID})
+}
+
+func render(w http.ResponseWriter, t *template.Template, data *pageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := t.ExecuteTemplate(w, "layout", data); err != nil {
+		logrus.Errorf("mocksite: render page failed: %v", err)
+	}
+}
+
+// writeSuccess 与真实站点接口的响应格式一致
+func writeSuccess(w http.ResponseWriter, data any) {
+	writeJSON(w, http.StatusOK, map[string]any{"code": 0, "success": true, "msg": "成功", "data": data})
+}
+
+func writeFailure(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]any{"code": -1, "success": false, "msg": msg})
+}
+
+// writeNotLoggedIn 真实站点在登录失效时返回 200 和 code -100
+func writeNotLoggedIn(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, map[string]any{"code": -100, "success": false, "msg": "登录已过期"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
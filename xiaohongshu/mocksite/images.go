@@ -0,0 +1,90 @@
+package mocksite
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"net/http"
+)
+
+const (
+	qrcodeSize   = 25 // 模块数，对应版本 2 的二维码
+	qrcodeScale  = 6  // 每个模块的像素
+	qrcodeBorder = 4  // 静区的模块数
+)
+
+// placeholderPNG 笔记图片和头像使用的纯色图片
+var placeholderPNG = encodePNG(solidImage(54, 72, color.RGBA{R: 0xfe, G: 0x2c, B: 0x55, A: 0xff}))
+
+func handleImage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "max-age=3600")
+	_, _ = w.Write(placeholderPNG)
+}
+
+// qrcodeDataURL 生成登录弹窗中的二维码图片。
+// 图片带有三个定位图案，其余模块随机，只用于模拟二维码的外观，扫描后没有实际内容。
+func qrcodeDataURL() string {
+	modules := make([][]bool, qrcodeSize)
+	for r := range modules {
+		modules[r] = make([]bool, qrcodeSize)
+		for c := range modules[r] {
+			modules[r][c] = rand.Intn(2) == 0
+		}
+	}
+
+	finder := func(r0, c0 int) {
+		// 定位图案及其外侧一圈分隔符
+		for r := -1; r <= 7; r++ {
+			for c := -1; c <= 7; c++ {
+				rr, cc := r0+r, c0+c
+				if rr < 0 || cc < 0 || rr >= qrcodeSize || cc >= qrcodeSize {
+					continue
+				}
+				inside := r >= 0 && r <= 6 && c >= 0 && c <= 6
+				ring := r == 0 || r == 6 || c == 0 || c == 6
+				center := r >= 2 && r <= 4 && c >= 2 && c <= 4
+				modules[rr][cc] = inside && (ring || center)
+			}
+		}
+	}
+	finder(0, 0)
+	finder(0, qrcodeSize-7)
+	finder(qrcodeSize-7, 0)
+
+	size := (qrcodeSize + qrcodeBorder*2) * qrcodeScale
+	img := solidImage(size, size, color.White)
+	for r, row := range modules {
+		for c, dark := range row {
+			if !dark {
+				continue
+			}
+			for y := 0; y < qrcodeScale; y++ {
+				for x := 0; x < qrcodeScale; x++ {
+					img.Set((qrcodeBorder+c)*qrcodeScale+x, (qrcodeBorder+r)*qrcodeScale+y, color.Black)
+				}
+			}
+		}
+	}
+
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(encodePNG(img))
+}
+
+func solidImage(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodePNG(img image.Image) []byte {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
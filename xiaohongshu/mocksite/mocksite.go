@@ -0,0 +1,315 @@
+// Package mocksite 基于 httptest 的离线小红书模拟站点。
+//
+// 模拟站点提供发现页、搜索页、笔记详情页、用户主页和创作服务平台的发布页，
+// 页面中带有与真实站点结构一致的 window.__INITIAL_STATE__ 以及 xiaohongshu 包依赖的 DOM 选择器，
+// 用于在 CI 中配合本地 Chrome 端到端测试各个操作，不需要真实的登录账号。
+//
+// 使用方式：
+//
+//	site, b := mocksite.NewBrowser(t)
+//	page := b.NewPage()
+//	page.MustSetCookies(site.Login())
+package mocksite
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// SessionCookieName 登录态 cookie 的名称，与真实站点一致
+const SessionCookieName = "web_session"
+
+// QrcodeStatus 登录二维码状态，取值与真实站点接口中的 code_status 一致
+type QrcodeStatus int
+
+const (
+	QrcodeWaiting   QrcodeStatus = 0 // 等待扫码
+	QrcodeScanned   QrcodeStatus = 1 // 已扫码，等待确认
+	QrcodeConfirmed QrcodeStatus = 2 // 已确认，下一次轮询时下发登录 cookie
+	QrcodeExpired   QrcodeStatus = 3 // 二维码已过期
+)
+
+// User 模拟站点中的用户
+type User struct {
+	UserID      string
+	RedID       string
+	Nickname    string
+	Avatar      string
+	Desc        string
+	Gender      int
+	IPLocation  string
+	Follows     int
+	Fans        int
+	Interaction int // 获赞与收藏
+}
+
+// Note 模拟站点中的笔记
+type Note struct {
+	ID             string
+	XsecToken      string
+	Type           string // normal 图文，video 视频
+	Title          string
+	Desc           string
+	Time           time.Time
+	IPLocation     string
+	AuthorID       string
+	Images         []string
+	Duration       int // 视频时长，单位秒
+	Liked          bool
+	Collected      bool
+	LikedCount     int
+	CollectedCount int
+	SharedCount    int
+	Comments       []Comment
+}
+
+// Comment 笔记下的评论
+type Comment struct {
+	ID         string
+	NoteID     string
+	UserID     string
+	Content    string
+	CreateTime time.Time
+	IPLocation string
+	LikeCount  int
+}
+
+// Publication 通过发布页提交的笔记
+type Publication struct {
+	NoteID  string    `json:"note_id"`
+	Type    string    `json:"type"` // normal 图文，video 视频
+	Title   string    `json:"title"`
+	Content string    `json:"content"`
+	Topics  []string  `json:"topics"`
+	Files   []string  `json:"files"`
+	Time    time.Time `json:"time"`
+}
+
+// Site 模拟站点。主站和创作服务平台由同一个服务提供，路径互不冲突。
+type Site struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	me           string
+	users        map[string]*User
+	notes        []*Note // 按发布时间倒序
+	sessions     map[string]bool
+	qrcode       QrcodeStatus
+	publications []Publication
+	rateLimited  bool
+	captcha      bool
+}
+
+// New 创建并启动带有默认数据的模拟站点，使用完毕后需要调用 Close。
+func New() *Site {
+	s := &Site{
+		users:    make(map[string]*User),
+		sessions: make(map[string]bool),
+	}
+	s.loadFixtures()
+	s.server = httptest.NewServer(s.routes())
+	return s
+}
+
+// URL 模拟站点地址，不带末尾的斜杠
+func (s *Site) URL() string {
+	return s.server.URL
+}
+
+// Close 关闭模拟站点
+func (s *Site) Close() {
+	s.server.Close()
+}
+
+// Me 当前登录账号对应的用户
+func (s *Site) Me() User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return *s.users[s.me]
+}
+
+// Notes 返回所有笔记的副本，按发布时间倒序
+func (s *Site) Notes() []Note {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	notes := make([]Note, 0, len(s.notes))
+	for _, n := range s.notes {
+		notes = append(notes, copyNote(n))
+	}
+	return notes
+}
+
+// Note 返回指定笔记的副本
+func (s *Site) Note(id string) (Note, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.findNote(id)
+	if n == nil {
+		return Note{}, false
+	}
+	return copyNote(n), true
+}
+
+// AddNote 添加一篇笔记，ID、XsecToken 和作者为空时自动生成，返回添加后的笔记
+func (s *Site) AddNote(n Note) Note {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return copyNote(s.addNote(n))
+}
+
+// Publications 通过发布页提交的笔记
+func (s *Site) Publications() []Publication {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Publication(nil), s.publications...)
+}
+
+// Login 直接创建一个登录会话，返回对应的 cookie，用于跳过扫码登录。
+func (s *Site) Login() *proto.NetworkCookieParam {
+	s.mu.Lock()
+	token := s.newSession()
+	s.mu.Unlock()
+
+	return &proto.NetworkCookieParam{
+		Name:     SessionCookieName,
+		Value:    token,
+		URL:      s.URL(),
+		Path:     "/",
+		HTTPOnly: true,
+	}
+}
+
+// LoggedIn 是否存在有效的登录会话
+func (s *Site) LoggedIn() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.sessions) > 0
+}
+
+// SetQrcodeStatus 设置登录二维码状态，模拟在手机上扫码、确认或二维码过期。
+// 每次打开未登录的页面都会生成新的二维码，状态重置为等待扫码。
+func (s *Site) SetQrcodeStatus(status QrcodeStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.qrcode = status
+}
+
+// SetRateLimited 模拟访问频次异常，开启后主站页面都会重定向到错误页
+func (s *Site) SetRateLimited(limited bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rateLimited = limited
+}
+
+// SetCaptcha 模拟触发风控验证，开启后主站和发布页都会弹出滑块验证层，关闭即视为验证通过
+func (s *Site) SetCaptcha(captcha bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.captcha = captcha
+}
+
+func (s *Site) findNote(id string) *Note {
+	for _, n := range s.notes {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+func (s *Site) addNote(n Note) *Note {
+	if n.ID == "" {
+		n.ID = newID(12)
+	}
+	if n.XsecToken == "" {
+		n.XsecToken = "AB" + newID(16)
+	}
+	if n.AuthorID == "" {
+		n.AuthorID = s.me
+	}
+	if n.Type == "" {
+		n.Type = "normal"
+	}
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+
+	note := &n
+	s.notes = append([]*Note{note}, s.notes...)
+	return note
+}
+
+func (s *Site) newSession() string {
+	token := "040069" + newID(16)
+	s.sessions[token] = true
+	return token
+}
+
+// loggedIn 请求是否带有有效的登录 cookie
+func (s *Site) loggedIn(r *http.Request) bool {
+	ck, err := r.Cookie(SessionCookieName)
+	if err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sessions[ck.Value]
+}
+
+// matchNotes 标题或正文包含关键字的笔记，关键字为空时返回全部
+func (s *Site) matchNotes(keyword string) []*Note {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+
+	var notes []*Note
+	for _, n := range s.notes {
+		if keyword == "" ||
+			strings.Contains(strings.ToLower(n.Title), keyword) ||
+			strings.Contains(strings.ToLower(n.Desc), keyword) {
+			notes = append(notes, n)
+		}
+	}
+	return notes
+}
+
+func copyNote(n *Note) Note {
+	c := *n
+	c.Images = append([]string(nil), n.Images...)
+	c.Comments = append([]Comment(nil), n.Comments...)
+	return c
+}
+
+// LookChrome 查找本地浏览器，优先使用环境变量 ROD_BROWSER_BIN。
+// 端到端测试在找不到浏览器时应当跳过，而不是让 rod 自动下载。
+func LookChrome() (string, bool) {
+	if bin := os.Getenv("ROD_BROWSER_BIN"); bin != "" {
+		return bin, true
+	}
+	return launcher.LookPath()
+}
+
+// newID 生成 n 字节的十六进制 ID，小红书的笔记和用户 ID 为 12 字节
+func newID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
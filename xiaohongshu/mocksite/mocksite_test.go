@@ -0,0 +1,334 @@
+package mocksite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const statePrefix = "window.__INITIAL_STATE__ = "
+
+func get(t *testing.T, site *Site, path, session string) string {
+	req, err := http.NewRequest(http.MethodGet, site.URL()+path, nil)
+	require.NoError(t, err)
+	if session != "" {
+		req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: session})
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func post(t *testing.T, site *Site, path, session string, body any) map[string]any {
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, site.URL()+path, bytes.NewReader(data))
+	require.NoError(t, err)
+	if session != "" {
+		req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: session})
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	return result
+}
+
+// initialState 解析页面中的 window.__INITIAL_STATE__
+func initialState(t *testing.T, page string) map[string]any {
+	i := strings.Index(page, statePrefix)
+	require.GreaterOrEqual(t, i, 0, "__INITIAL_STATE__ not found")
+
+	var state map[string]any
+	require.NoError(t, json.NewDecoder(strings.NewReader(page[i+len(statePrefix):])).Decode(&state))
+	return state
+}
+
+func refValue(t *testing.T, v any) any {
+	m, ok := v.(map[string]any)
+	require.True(t, ok, "expect ref object, got %T", v)
+	return m["_value"]
+}
+
+func TestExplorePage(t *testing.T) {
+	site := New()
+	defer site.Close()
+
+	page := get(t, site, "/explore", "")
+	require.Contains(t, page, `class="login-container"`)
+	require.Contains(t, page, `class="qrcode-img" src="data:image/png;base64,`)
+
+	state := initialState(t, page)
+	feeds := refValue(t, state["feed"].(map[string]any)["feeds"]).([]any)
+	require.Len(t, feeds, len(site.Notes()))
+
+	first := feeds[0].(map[string]any)
+	require.Equal(t, site.Notes()[0].ID, first["id"])
+	require.NotEmpty(t, first["xsecToken"])
+	require.NotEmpty(t, first["noteCard"].(map[string]any)["displayTitle"])
+
+	userInfo := refValue(t, state["user"].(map[string]any)["userInfo"]).(map[string]any)
+	require.Equal(t, true, userInfo["guest"])
+
+	session := site.Login().Value
+	page = get(t, site, "/explore", session)
+	require.Contains(t, page, `li class="user side-bar-component"`)
+	require.NotContains(t, page, `class="login-container"`)
+
+	userInfo = refValue(t, initialState(t, page)["user"].(map[string]any)["userInfo"]).(map[string]any)
+	require.Equal(t, site.Me().UserID, userInfo["user_id"])
+	require.Equal(t, site.Me().RedID, userInfo["red_id"])
+}
+
+func TestQrcodeLogin(t *testing.T) {
+	site := New()
+	defer site.Close()
+
+	get(t, site, "/explore", "")
+	require.False(t, site.LoggedIn())
+
+	site.SetQrcodeStatus(QrcodeScanned)
+	result := post(t, site, "/api/sns/web/v1/note/like", "", map[string]string{"note_oid": site.Notes()[0].ID})
+	require.Equal(t, float64(-100), result["code"])
+
+	site.SetQrcodeStatus(QrcodeConfirmed)
+	resp, err := http.Get(site.URL() + "/api/sns/web/v1/login/qrcode/status")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var session string
+	for _, ck := range resp.Cookies() {
+		if ck.Name == SessionCookieName {
+			session = ck.Value
+		}
+	}
+	require.NotEmpty(t, session)
+	require.True(t, site.LoggedIn())
+
+	post(t, site, "/api/sns/web/v1/user/logout", session, nil)
+	require.False(t, site.LoggedIn())
+}
+
+func TestNoteDetailInteractions(t *testing.T) {
+	site := New()
+	defer site.Close()
+
+	session := site.Login().Value
+	note := site.Notes()[0]
+
+	state := initialState(t, get(t, site, "/explore/"+note.ID+"?xsec_token="+note.XsecToken, session))
+	detail := state["note"].(map[string]any)["noteDetailMap"].(map[string]any)[note.ID].(map[string]any)
+	require.Equal(t, note.Title, detail["note"].(map[string]any)["title"])
+
+	result := post(t, site, "/api/sns/web/v1/note/like", session, map[string]string{"note_oid": note.ID})
+	require.Equal(t, true, result["success"])
+	result = post(t, site, "/api/sns/web/v1/note/collect", session, map[string]string{"note_id": note.ID})
+	require.Equal(t, true, result["success"])
+	result = post(t, site, "/api/sns/web/v1/comment/post", session, map[string]string{"note_id": note.ID, "content": "写得真好"})
+	require.Equal(t, true, result["success"])
+
+	got, ok := site.Note(note.ID)
+	require.True(t, ok)
+	require.True(t, got.Liked)
+	require.True(t, got.Collected)
+	require.Equal(t, note.LikedCount+1, got.LikedCount)
+	require.Equal(t, "写得真好", got.Comments[0].Content)
+	require.Equal(t, site.Me().UserID, got.Comments[0].UserID)
+
+	post(t, site, "/api/sns/web/v1/note/uncollect", session, map[string]string{"note_ids": note.ID})
+	got, _ = site.Note(note.ID)
+	require.False(t, got.Collected)
+}
+
+func TestSearchAndProfile(t *testing.T) {
+	site := New()
+	defer site.Close()
+
+	state := initialState(t, get(t, site, "/search_result?keyword=咖啡&source=web_explore_feed", ""))
+	feeds := refValue(t, state["search"].(map[string]any)["feeds"]).([]any)
+	require.Len(t, feeds, 1)
+	require.Equal(t, "上海周末咖啡馆合集", feeds[0].(map[string]any)["noteCard"].(map[string]any)["displayTitle"])
+
+	session := site.Login().Value
+	result := post(t, site, "/web_api/sns/v2/note", session, map[string]any{
+		"type": "normal", "title": "离线发布", "desc": "正文 #测试", "topics": []string{"测试"}, "files": []string{"1.jpg"},
+	})
+	require.Equal(t, true, result["success"])
+
+	publications := site.Publications()
+	require.Len(t, publications, 1)
+	require.Equal(t, "离线发布", publications[0].Title)
+	require.Equal(t, []string{"测试"}, publications[0].Topics)
+
+	me := site.Me()
+	user := initialState(t, get(t, site, "/user/profile/"+me.UserID, session))["user"].(map[string]any)
+	basic := refValue(t, user["userPageData"]).(map[string]any)["basicInfo"].(map[string]any)
+	require.Equal(t, me.Nickname, basic["nickname"])
+
+	notes := refValue(t, user["notes"]).([]any)
+	posted := notes[0].([]any)
+	require.Len(t, posted, 2)
+	require.Equal(t, publications[0].NoteID, posted[0].(map[string]any)["id"])
+}
+
+func TestSearchPagination(t *testing.T) {
+	site := New()
+	defer site.Close()
+
+	for i := 0; i < searchPageSize+5; i++ {
+		site.AddNote(Note{Title: fmt.Sprintf("分页笔记 %d", i)})
+	}
+
+	search := initialState(t, get(t, site, "/search_result?keyword=分页笔记", ""))["search"].(map[string]any)
+	require.Len(t, refValue(t, search["feeds"]), searchPageSize)
+	require.Equal(t, true, search["hasMore"])
+
+	result := post(t, site, "/api/sns/web/v1/search/notes", "", map[string]any{"keyword": "分页笔记", "page": 2})
+	data := result["data"].(map[string]any)
+	require.Len(t, data["items"], 5)
+	require.Equal(t, false, data["has_more"])
+	require.Equal(t, float64(searchPageSize), data["items"].([]any)[0].(map[string]any)["index"])
+}
+
+func TestHomefeedAndFeedAPI(t *testing.T) {
+	site := New()
+	defer site.Close()
+
+	for i := 0; i < homefeedPageSize; i++ {
+		site.AddNote(Note{Title: fmt.Sprintf("推荐笔记 %d", i)})
+	}
+	total := len(site.Notes())
+
+	feed := initialState(t, get(t, site, "/explore", ""))["feed"].(map[string]any)
+	require.Len(t, refValue(t, feed["feeds"]), homefeedPageSize)
+
+	result := post(t, site, "/api/sns/web/v1/homefeed", "", map[string]any{"cursor_score": strconv.Itoa(homefeedPageSize), "num": homefeedPageSize})
+	data := result["data"].(map[string]any)
+	require.Len(t, data["items"], total-homefeedPageSize)
+	require.Equal(t, false, data["has_more"])
+	first := data["items"].([]any)[0].(map[string]any)
+	require.Equal(t, site.Notes()[homefeedPageSize].ID, first["id"])
+	require.NotEmpty(t, first["xsec_token"])
+
+	note := site.Notes()[0]
+	result = post(t, site, "/api/sns/web/v1/feed", "", map[string]any{"source_note_id": note.ID, "xsec_token": note.XsecToken})
+	items := result["data"].(map[string]any)["items"].([]any)
+	require.Len(t, items, 1)
+	card := items[0].(map[string]any)["note_card"].(map[string]any)
+	require.Equal(t, note.ID, card["note_id"])
+	require.Equal(t, note.Title, card["title"])
+	require.NotEmpty(t, card["interact_info"])
+
+	result = post(t, site, "/api/sns/web/v1/feed", "", map[string]any{"source_note_id": note.ID, "xsec_token": "invalid"})
+	require.Equal(t, false, result["success"])
+}
+
+func TestCommentAndUserNotesPagination(t *testing.T) {
+	site := New()
+	defer site.Close()
+
+	session := site.Login().Value
+	me := site.Me()
+
+	var comments []Comment
+	for i := 0; i < commentPageSize+5; i++ {
+		comments = append(comments, Comment{ID: fmt.Sprintf("c%02d", i), UserID: me.UserID, Content: fmt.Sprintf("评论 %d", i)})
+	}
+	note := site.AddNote(Note{Title: "评论分页", Comments: comments})
+
+	state := initialState(t, get(t, site, "/explore/"+note.ID+"?xsec_token="+note.XsecToken, session))
+	detail := state["note"].(map[string]any)["noteDetailMap"].(map[string]any)[note.ID].(map[string]any)
+	first := detail["comments"].(map[string]any)
+	require.Len(t, first["list"], commentPageSize)
+	require.Equal(t, true, first["hasMore"])
+
+	var page map[string]any
+	require.NoError(t, json.Unmarshal([]byte(get(t, site, "/api/sns/web/v2/comment/page?note_id="+note.ID+"&cursor="+first["cursor"].(string), session)), &page))
+	data := page["data"].(map[string]any)
+	require.Len(t, data["comments"], 5)
+	require.Equal(t, "c10", data["comments"].([]any)[0].(map[string]any)["id"])
+	require.Equal(t, false, data["has_more"])
+
+	for i := 0; i < userNotesPageSize; i++ {
+		site.AddNote(Note{Title: fmt.Sprintf("主页笔记 %d", i)})
+	}
+	mine := 0
+	for _, n := range site.Notes() {
+		if n.AuthorID == me.UserID {
+			mine++
+		}
+	}
+
+	user := initialState(t, get(t, site, "/user/profile/"+me.UserID, session))["user"].(map[string]any)
+	require.Len(t, refValue(t, user["notes"]).([]any)[0], userNotesPageSize)
+
+	require.NoError(t, json.Unmarshal([]byte(get(t, site, fmt.Sprintf("/api/sns/web/v1/user_posted?num=30&cursor=%d&user_id=%s", userNotesPageSize, me.UserID), session)), &page))
+	data = page["data"].(map[string]any)
+	require.Len(t, data["notes"], mine-userNotesPageSize)
+	require.NotEmpty(t, data["notes"].([]any)[0].(map[string]any)["xsec_token"])
+	require.Equal(t, false, data["has_more"])
+}
+
+func TestErrorRedirects(t *testing.T) {
+	site := New()
+	defer site.Close()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	location := func(path string) string {
+		resp, err := client.Get(site.URL() + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+		return resp.Header.Get("Location")
+	}
+
+	note := site.Notes()[0]
+	require.Contains(t, location("/explore/"+note.ID+"?xsec_token=invalid"), "error_code=300031")
+	require.Contains(t, location("/explore/000000000000000000000000"), "error_code=-510001")
+	require.Equal(t, "/login", location("/publish/publish?source=official"))
+
+	site.SetRateLimited(true)
+	require.Contains(t, location("/explore"), "error_code=300013")
+
+	site.SetRateLimited(false)
+	require.Contains(t, get(t, site, "/404?error_code=300013&error_msg=访问频次异常", ""), "访问频次异常")
+}
+
+func TestCaptchaOverlay(t *testing.T) {
+	site := New()
+	defer site.Close()
+
+	body := func() string {
+		resp, err := http.Get(site.URL() + "/explore")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	require.NotContains(t, body(), `class="red-captcha"`)
+
+	site.SetCaptcha(true)
+	require.Contains(t, body(), `class="red-captcha"`)
+}
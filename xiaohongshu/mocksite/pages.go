@@ -0,0 +1,712 @@
+package mocksite
+
+import "html/template"
+
+// 页面只保留 xiaohongshu 包依赖的结构和选择器，页面内容由脚本根据 window.__INITIAL_STATE__ 渲染，
+// 交互（点赞、收藏、评论、发布）与真实站点一样通过接口提交。
+var (
+	explorePage     = newPage(explorePageHTML)
+	noteDetailPage  = newPage(noteDetailPageHTML)
+	searchPage      = newPage(searchPageHTML)
+	userProfilePage = newPage(userProfilePageHTML)
+	publishPage     = newPage(publishPageHTML)
+	errorPage       = newPage(errorPageHTML)
+)
+
+func newPage(content string) *template.Template {
+	t := template.Must(template.New("layout").Parse(layoutHTML))
+	return template.Must(t.Parse(content))
+}
+
+const layoutHTML = `{{define "layout"}}<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; font-family: sans-serif; font-size: 14px; }
+.main-container { display: flex; }
+.side-bar { width: 200px; padding: 16px; }
+.side-bar ul { list-style: none; margin: 0; padding: 0; }
+.side-bar li { height: 40px; line-height: 40px; }
+.side-bar .avatar { width: 24px; height: 24px; vertical-align: middle; }
+.information-container, .more-menu { display: inline-block; cursor: pointer; }
+.main-content { flex: 1; padding: 16px; }
+.feeds-container { display: flex; flex-wrap: wrap; }
+.note-item { width: 180px; margin: 8px; }
+.note-item img { width: 180px; height: 240px; }
+.login-modal { position: fixed; left: 0; top: 0; right: 0; bottom: 0; background: rgba(0, 0, 0, 0.4); }
+.login-container { width: 360px; margin: 80px auto; padding: 24px; background: #fff; text-align: center; }
+.login-container .qrcode-img { width: 180px; height: 180px; }
+.filter { position: relative; display: inline-block; padding: 8px; cursor: pointer; }
+.filter-panel { display: none; position: absolute; top: 32px; left: 0; width: 480px; background: #fff; z-index: 10; }
+.filter-panel.show { display: block; }
+.tags { display: inline-block; margin: 4px; padding: 4px 8px; cursor: pointer; }
+.tags.active { color: #fe2c55; }
+.end-container { width: 100%; padding: 16px; text-align: center; color: #999; }
+.note-scroller { height: 480px; overflow-y: auto; }
+.comment-item { min-height: 60px; }
+.like-lottie, .reds-icon { display: inline-block; width: 24px; height: 24px; background: #ddd; cursor: pointer; }
+.like-active .like-lottie, .collect-active .collect-icon { background: #fe2c55; }
+.content-edit span { display: block; color: #999; cursor: text; }
+.content-input { min-height: 20px; min-width: 240px; margin: 4px 0; border: 1px solid #eee; }
+.reds-tab-item { display: inline-block; padding: 8px 16px; cursor: pointer; }
+.reds-tab-item.active { font-weight: bold; }
+.creator-tab { display: inline-block; padding: 8px 16px; cursor: pointer; }
+.creator-tab.active { color: #fe2c55; }
+.upload-content { padding: 24px; border: 1px dashed #ccc; }
+.img-preview-area .pr { display: inline-block; width: 80px; height: 80px; margin: 4px; background: #eee; overflow: hidden; }
+.tiptap { min-height: 120px; border: 1px solid #eee; }
+#creator-editor-topic-container .item { padding: 4px 8px; cursor: pointer; }
+</style>
+</head>
+<body>
+<div id="app">
+{{if .Creator}}{{template "content" .}}{{else}}
+<div class="main-container">
+<div class="side-bar">
+<ul class="channel-list">
+<li class="explore side-bar-component"><a class="link-wrapper" href="/explore"><span class="channel">发现</span></a></li>
+<li class="publish side-bar-component"><a class="link-wrapper" href="/publish/publish?source=official"><span class="channel">发布</span></a></li>
+{{if .LoggedIn}}<li class="user side-bar-component"><a class="link-wrapper" href="/user/profile/{{.Me.UserID}}"><img class="avatar" src="{{.Me.Avatar}}"><span class="channel">我</span></a></li>
+{{else}}<li class="login side-bar-component"><button class="login-btn">登录</button></li>{{end}}
+</ul>
+{{if .LoggedIn}}<div class="information-container"><div class="more-button">更多</div></div>{{end}}
+</div>
+<div class="main-content">{{template "content" .}}</div>
+</div>
+{{if not .LoggedIn}}<div class="login-modal"><div class="login-container">
+<div class="title">登录后推荐更懂你的笔记</div>
+<img class="qrcode-img" src="{{.Qrcode}}">
+<div class="status-text">可用 小红书 或 微信 扫码</div>
+</div></div>{{end}}
+{{end}}
+{{if .Captcha}}<div class="red-captcha"><div class="red-captcha-title">请完成安全验证</div><div class="red-captcha-slider"></div></div>{{end}}
+</div>
+<script>
+window.__INITIAL_STATE__ = {{.State}};
+
+function el(tag, className, text) {
+	var e = document.createElement(tag);
+	if (className) {
+		e.className = className;
+	}
+	if (text !== undefined) {
+		e.textContent = text;
+	}
+	return e;
+}
+
+// camelize 将接口返回的下划线字段转换为页面状态使用的驼峰字段
+function camelize(value) {
+	if (Array.isArray(value)) {
+		return value.map(camelize);
+	}
+	if (value && typeof value === 'object') {
+		var result = {};
+		Object.keys(value).forEach(function (key) {
+			result[key.replace(/_([a-z])/g, function (m, c) {
+				return c.toUpperCase();
+			})] = camelize(value[key]);
+		});
+		return result;
+	}
+	return value;
+}
+
+function refValue(ref) {
+	if (!ref) {
+		return undefined;
+	}
+	return ref.value !== undefined ? ref.value : ref._value;
+}
+
+function setRefValue(ref, value) {
+	ref._rawValue = value;
+	ref._value = value;
+}
+
+function post(url, body) {
+	return fetch(url, {
+		method: 'POST',
+		headers: {'Content-Type': 'application/json'},
+		body: JSON.stringify(body || {})
+	}).then(function (res) {
+		return res.json();
+	});
+}
+
+function addCount(count, delta) {
+	return String(Math.max(0, (parseInt(count, 10) || 0) + delta));
+}
+
+function renderCards(container, feeds) {
+	container.textContent = '';
+	(feeds || []).forEach(function (feed) {
+		var card = feed.noteCard;
+		var href = '/explore/' + feed.id + '?xsec_token=' + encodeURIComponent(feed.xsecToken) + '&xsec_source=pc_feed';
+
+		var item = el('section', 'note-item');
+		var cover = el('a', 'cover');
+		cover.href = href;
+		var img = el('img');
+		img.src = card.cover.urlDefault;
+		cover.appendChild(img);
+		item.appendChild(cover);
+
+		var footer = el('div', 'footer');
+		var title = el('a', 'title', card.displayTitle);
+		title.href = href;
+		footer.appendChild(title);
+		var author = el('a', 'author', card.user.nickname);
+		author.href = '/user/profile/' + card.user.userId;
+		footer.appendChild(author);
+		item.appendChild(footer);
+
+		container.appendChild(item);
+	});
+}
+
+(function () {
+	var more = document.querySelector('.side-bar .information-container');
+	if (!more) {
+		return;
+	}
+	more.addEventListener('click', function () {
+		if (document.querySelector('.more-menu')) {
+			return;
+		}
+		var menu = el('div', 'more-menu');
+		var logout = el('div', 'menu-item', '退出登录');
+		logout.addEventListener('click', function () {
+			post('/api/sns/web/v1/user/logout').then(function () {
+				location.href = '/explore';
+			});
+		});
+		menu.appendChild(logout);
+		more.parentNode.appendChild(menu);
+	});
+})();
+
+(function () {
+	var text = document.querySelector('.login-container .status-text');
+	if (!text) {
+		return;
+	}
+	var timer = setInterval(function () {
+		fetch('/api/sns/web/v1/login/qrcode/status').then(function (res) {
+			return res.json();
+		}).then(function (res) {
+			var status = res.data.code_status;
+			if (status === 1) {
+				text.textContent = '扫码成功，请在手机上确认登录';
+			} else if (status === 3) {
+				text.textContent = '二维码已过期，点击刷新';
+			} else if (status === 2) {
+				clearInterval(timer);
+				location.reload();
+			}
+		});
+	}, 500);
+})();
+</script>
+<script>
+{{template "script" .}}
+</script>
+</body>
+</html>{{end}}`
+
+const explorePageHTML = `{{define "content"}}<div class="feeds-page">
+<div class="channel-container"><div class="channel active">推荐</div></div>
+<div class="feeds-container"></div>
+</div>{{end}}
+
+{{define "script"}}
+(function () {
+	var container = document.querySelector('.feeds-container');
+	var feeds = refValue(window.__INITIAL_STATE__.feed.feeds) || [];
+	renderCards(container, feeds);
+
+	// 首屏之后的推荐由接口加载，只渲染到页面上，不写回页面状态
+	post('/api/sns/web/v1/homefeed', {cursor_score: String(feeds.length), num: 20}).then(function (res) {
+		if (res.success) {
+			renderCards(container, feeds.concat(camelize(res.data.items)));
+		}
+	});
+})();
+{{end}}`
+
+const noteDetailPageHTML = `{{define "content"}}<div id="noteContainer" class="note-container">
+<div class="media-container"><img class="note-slider-img"></div>
+<div class="interaction-container">
+<div class="author-container"><div class="info"><a class="name"></a></div></div>
+<div class="note-scroller">
+<div class="note-content">
+<div id="detail-title" class="title"></div>
+<div id="detail-desc" class="desc"></div>
+<div class="bottom-container"><span class="date"></span></div>
+</div>
+<div class="comments-container"><div class="total"></div><div class="list-container"></div></div>
+</div>
+<div class="interactions engage-bar">
+<div class="input-box">
+<div class="content-edit"><span>说点什么...</span><p class="content-input" contenteditable="true"></p></div>
+</div>
+<div class="interact-container">
+<div class="left">
+<span class="like-wrapper"><span class="like-lottie"></span><span class="count"></span></span>
+<span class="collect-wrapper"><span class="reds-icon collect-icon"></span><span class="count"></span></span>
+<span class="chat-wrapper"><span class="reds-icon chat-icon"></span><span class="count"></span></span>
+</div>
+</div>
+<div class="bottom"><button class="btn submit">发送</button><button class="btn cancel">取消</button></div>
+</div>
+</div>
+</div>{{end}}
+
+{{define "script"}}
+(function () {
+	var noteId = {{.NoteID}};
+	var detail = window.__INITIAL_STATE__.note.noteDetailMap[noteId];
+	if (!detail) {
+		document.getElementById('noteContainer').textContent = '当前笔记暂时无法浏览';
+		return;
+	}
+
+	var note = detail.note;
+	var info = note.interactInfo;
+
+	// 与线上页面一样，打开详情页后再请求一次详情接口
+	post('/api/sns/web/v1/feed', {source_note_id: noteId, xsec_token: note.xsecToken, image_formats: ['jpg', 'webp']});
+
+	var author = document.querySelector('.author-container .name');
+	author.textContent = note.user.nickname;
+	author.href = '/user/profile/' + note.user.userId;
+	document.getElementById('detail-title').textContent = note.title;
+	document.getElementById('detail-desc').textContent = note.desc;
+	document.querySelector('.bottom-container .date').textContent = new Date(note.time).toLocaleDateString() + ' ' + note.ipLocation;
+	if (note.imageList.length > 0) {
+		document.querySelector('.note-slider-img').src = note.imageList[0].urlDefault;
+	}
+
+	var likeWrapper = document.querySelector('.like-wrapper');
+	var collectWrapper = document.querySelector('.collect-wrapper');
+	var chatWrapper = document.querySelector('.chat-wrapper');
+
+	function renderInteract() {
+		likeWrapper.classList.toggle('like-active', info.liked);
+		likeWrapper.querySelector('.count').textContent = info.likedCount;
+		collectWrapper.classList.toggle('collect-active', info.collected);
+		collectWrapper.querySelector('.count').textContent = info.collectedCount;
+		chatWrapper.querySelector('.count').textContent = info.commentCount;
+	}
+
+	function renderComments() {
+		document.querySelector('.comments-container .total').textContent = '共 ' + info.commentCount + ' 条评论';
+		var list = document.querySelector('.comments-container .list-container');
+		list.textContent = '';
+		detail.comments.list.forEach(function (comment) {
+			var item = el('div', 'comment-item');
+			item.appendChild(el('a', 'name', comment.userInfo.nickname));
+			item.appendChild(el('div', 'content', comment.content));
+			list.appendChild(item);
+		});
+	}
+
+	likeWrapper.querySelector('.like-lottie').addEventListener('click', function () {
+		var liked = !info.liked;
+		info.liked = liked;
+		info.likedCount = addCount(info.likedCount, liked ? 1 : -1);
+		renderInteract();
+
+		post(liked ? '/api/sns/web/v1/note/like' : '/api/sns/web/v1/note/dislike', {note_oid: noteId}).then(function (res) {
+			if (!res.success) {
+				info.liked = !liked;
+				info.likedCount = addCount(info.likedCount, liked ? -1 : 1);
+				renderInteract();
+			}
+		});
+	});
+
+	collectWrapper.querySelector('.collect-icon').addEventListener('click', function () {
+		var collected = !info.collected;
+		info.collected = collected;
+		info.collectedCount = addCount(info.collectedCount, collected ? 1 : -1);
+		renderInteract();
+
+		var req = collected ? post('/api/sns/web/v1/note/collect', {note_id: noteId}) : post('/api/sns/web/v1/note/uncollect', {note_ids: noteId});
+		req.then(function (res) {
+			if (!res.success) {
+				info.collected = !collected;
+				info.collectedCount = addCount(info.collectedCount, collected ? -1 : 1);
+				renderInteract();
+			}
+		});
+	});
+
+	var inputBox = document.querySelector('.input-box');
+	var input = inputBox.querySelector('p.content-input');
+	inputBox.querySelector('.content-edit span').addEventListener('click', function () {
+		inputBox.classList.add('active');
+		input.focus();
+	});
+
+	document.querySelector('div.bottom button.submit').addEventListener('click', function () {
+		var content = input.innerText.trim();
+		if (!content) {
+			return;
+		}
+		post('/api/sns/web/v1/comment/post', {note_id: noteId, content: content, at_users: []}).then(function (res) {
+			if (!res.success) {
+				return;
+			}
+			detail.comments.list.unshift(res.data.comment);
+			info.commentCount = addCount(info.commentCount, 1);
+			input.textContent = '';
+			inputBox.classList.remove('active');
+			renderInteract();
+			renderComments();
+		});
+	});
+
+	// 滚动评论区到底部时加载下一页评论，没有更多评论时显示 THE END
+	var scroller = document.querySelector('.note-scroller');
+	var loading = false;
+
+	function renderEnd() {
+		if (!detail.comments.hasMore && !document.querySelector('.comments-container .end-container')) {
+			document.querySelector('.comments-container').appendChild(el('div', 'end-container', '- THE END -'));
+		}
+	}
+
+	function loadMore() {
+		if (loading || !detail.comments.hasMore) {
+			return;
+		}
+		if (scroller.scrollTop + scroller.clientHeight < scroller.scrollHeight - 100) {
+			return;
+		}
+		loading = true;
+		fetch('/api/sns/web/v2/comment/page?note_id=' + noteId + '&cursor=' + detail.comments.cursor).then(function (res) {
+			return res.json();
+		}).then(function (res) {
+			detail.comments.list = detail.comments.list.concat(camelize(res.data.comments));
+			detail.comments.cursor = res.data.cursor;
+			detail.comments.hasMore = res.data.has_more;
+			renderComments();
+			renderEnd();
+		}).finally(function () {
+			loading = false;
+		});
+	}
+
+	scroller.addEventListener('scroll', loadMore);
+	scroller.addEventListener('wheel', loadMore);
+
+	renderInteract();
+	renderComments();
+	renderEnd();
+})();
+{{end}}`
+
+const searchPageHTML = `{{define "content"}}<div class="search-layout">
+<div class="filter-box">
+<div class="filter"><span class="filter-text">筛选</span>
+<div class="filter-panel">{{range .Filters}}<div class="filters"><span class="filters-title">{{.Title}}</span><div class="tag-container">{{range $i, $tag := .Tags}}<div class="tags{{if eq $i 0}} active{{end}}"><span>{{$tag}}</span></div>{{end}}</div></div>{{end}}</div>
+</div>
+</div>
+<div class="feeds-container"></div>
+</div>{{end}}
+
+{{define "script"}}
+(function () {
+	var search = window.__INITIAL_STATE__.search;
+	var all = refValue(search.feeds).slice();
+	var container = document.querySelector('.feeds-container');
+	var panel = document.querySelector('div.filter-panel');
+	var groups = panel.querySelectorAll('div.filters');
+
+	document.querySelector('div.filter').addEventListener('mouseenter', function () {
+		panel.classList.add('show');
+	});
+
+	function selected(index) {
+		return groups[index].querySelector('div.tags.active').textContent.trim();
+	}
+
+	// 只模拟笔记类型筛选和按点赞数排序，其余筛选项只切换选中状态
+	function apply() {
+		var noteType = selected(1);
+		var feeds = all.filter(function (feed) {
+			if (noteType === '视频') {
+				return feed.noteCard.type === 'video';
+			}
+			if (noteType === '图文') {
+				return feed.noteCard.type === 'normal';
+			}
+			return true;
+		});
+		if (selected(0) === '最多点赞') {
+			feeds.sort(function (a, b) {
+				return parseInt(b.noteCard.interactInfo.likedCount, 10) - parseInt(a.noteCard.interactInfo.likedCount, 10);
+			});
+		}
+		setRefValue(search.feeds, feeds);
+		renderCards(container, feeds);
+	}
+
+	Array.prototype.forEach.call(groups, function (group) {
+		var tags = group.querySelectorAll('div.tags');
+		Array.prototype.forEach.call(tags, function (tag) {
+			tag.addEventListener('click', function () {
+				Array.prototype.forEach.call(tags, function (t) {
+					t.classList.remove('active');
+				});
+				tag.classList.add('active');
+				apply();
+			});
+		});
+	});
+
+	// 滚动到底部时加载下一页，没有更多结果时显示 THE END
+	var page = 1;
+	var loading = false;
+
+	function renderEnd() {
+		if (!search.hasMore && !document.querySelector('.end-container')) {
+			container.parentNode.appendChild(el('div', 'end-container', '- THE END -'));
+		}
+	}
+
+	function loadMore() {
+		if (loading || !search.hasMore) {
+			return;
+		}
+		if (window.innerHeight + window.scrollY < document.documentElement.scrollHeight - 200) {
+			return;
+		}
+		loading = true;
+		post('/api/sns/web/v1/search/notes', {keyword: search.keyword, page: page + 1}).then(function (res) {
+			page++;
+			all = all.concat(camelize(res.data.items));
+			search.hasMore = res.data.has_more;
+			apply();
+			renderEnd();
+		}).finally(function () {
+			loading = false;
+		});
+	}
+
+	window.addEventListener('scroll', loadMore);
+	window.addEventListener('wheel', loadMore);
+
+	renderCards(container, all);
+	renderEnd();
+})();
+{{end}}`
+
+const userProfilePageHTML = `{{define "content"}}<div class="user-page">
+<div class="user-info">
+<img class="avatar">
+<div class="user-name"></div>
+<div class="user-redId"></div>
+<div class="user-desc"></div>
+<div class="user-interactions"></div>
+</div>
+<div class="reds-tabs-list"><div class="reds-tab-item sub-tab-list"><span>笔记</span></div><div class="reds-tab-item sub-tab-list"><span>收藏</span></div><div class="reds-tab-item sub-tab-list"><span>点赞</span></div></div>
+<div class="feeds-container"></div>
+</div>{{end}}
+
+{{define "script"}}
+(function () {
+	var user = window.__INITIAL_STATE__.user;
+	var data = refValue(user.userPageData);
+	if (!data) {
+		document.querySelector('.user-page').textContent = '该用户不存在';
+		return;
+	}
+	var tabFeeds = {{.TabFeeds}};
+
+	var basic = data.basicInfo;
+	document.querySelector('.user-info .avatar').src = basic.images;
+	document.querySelector('.user-info .user-name').textContent = basic.nickname;
+	document.querySelector('.user-info .user-redId').textContent = '小红书号：' + basic.redId;
+	document.querySelector('.user-info .user-desc').textContent = basic.desc;
+	document.querySelector('.user-info .user-interactions').textContent = data.interactions.map(function (item) {
+		return item.count + ' ' + item.name;
+	}).join(' ');
+
+	var container = document.querySelector('.feeds-container');
+	var tabs = document.querySelectorAll('div.reds-tab-item.sub-tab-list');
+
+	function activate(index) {
+		Array.prototype.forEach.call(tabs, function (tab, i) {
+			tab.classList.toggle('active', i === index);
+		});
+		var notes = refValue(user.notes);
+		if (notes[index].length === 0 && tabFeeds[index].length > 0) {
+			notes[index] = tabFeeds[index];
+		}
+		setRefValue(user.notes, notes);
+		user.activeTab = index;
+		renderCards(container, notes[index]);
+	}
+
+	Array.prototype.forEach.call(tabs, function (tab, i) {
+		tab.addEventListener('click', function () {
+			activate(i);
+		});
+	});
+
+	// 笔记标签滚动到底部时加载下一页，没有更多笔记时显示 THE END
+	var query = user.noteQueries[0];
+	var loading = false;
+
+	function renderEnd() {
+		if (!query.hasMore && !document.querySelector('.end-container')) {
+			container.parentNode.appendChild(el('div', 'end-container', '- THE END -'));
+		}
+	}
+
+	function loadMore() {
+		if (loading || !query.hasMore || user.activeTab !== 0) {
+			return;
+		}
+		if (window.innerHeight + window.scrollY < document.documentElement.scrollHeight - 200) {
+			return;
+		}
+		loading = true;
+		fetch('/api/sns/web/v1/user_posted?num=30&cursor=' + query.cursor + '&user_id=' + location.pathname.split('/').pop()).then(function (res) {
+			return res.json();
+		}).then(function (res) {
+			var notes = refValue(user.notes);
+			notes[0] = notes[0].concat(camelize(res.data.notes).map(function (note) {
+				return {id: note.noteId, xsecToken: note.xsecToken, modelType: 'note', noteCard: note};
+			}));
+			setRefValue(user.notes, notes);
+			query.cursor = res.data.cursor;
+			query.hasMore = res.data.has_more;
+			renderCards(container, notes[0]);
+			renderEnd();
+		}).finally(function () {
+			loading = false;
+		});
+	}
+
+	window.addEventListener('scroll', loadMore);
+	window.addEventListener('wheel', loadMore);
+
+	activate(0);
+	renderEnd();
+})();
+{{end}}`
+
+const publishPageHTML = `{{define "content"}}<div class="publish-page">
+<div class="header">
+<div class="creator-tab active"><span class="title">上传视频</span></div>
+<div class="creator-tab"><span class="title">上传图文</span></div>
+<div class="creator-tab" style="position: absolute; left: -9999px;"><span class="title">上传图文</span></div>
+</div>
+<div class="upload-content">
+<input class="upload-input" type="file" accept=".mp4,.mov,.flv,.f4v,.mkv,.rm,.rmvb,.m4v,.mpg,.mpeg,.ts">
+<div class="upload-tip">拖拽视频到此或点击上传</div>
+</div>
+<div class="post-editor" style="display: none;">
+<div class="img-preview-area"></div>
+<div class="d-input"><input class="d-text" type="text" placeholder="填写标题会有更多赞哦～"></div>
+<div class="editor-container"><div class="tiptap-container">
+<div class="tiptap ProseMirror" role="textbox" contenteditable="true"><p data-placeholder="输入正文描述，真诚有价值的分享予人温暖" class="is-empty"></p></div>
+</div></div>
+<div id="creator-editor-topic-container" style="display: none;"></div>
+<div class="submit"><button class="publishBtn" disabled><div class="d-button-content"><span class="d-text">发布</span></div></button></div>
+</div>
+<div class="publish-result" style="display: none;">发布成功</div>
+</div>{{end}}
+
+{{define "script"}}
+(function () {
+	var mode = 'video';
+	var files = [];
+	var topics = [];
+
+	var tabs = document.querySelectorAll('div.creator-tab');
+	var input = document.querySelector('.upload-input');
+	var tip = document.querySelector('.upload-tip');
+	var editor = document.querySelector('.post-editor');
+	var previews = document.querySelector('.img-preview-area');
+	var title = document.querySelector('div.d-input input');
+	var textbox = document.querySelector('[role="textbox"]');
+	var topicContainer = document.getElementById('creator-editor-topic-container');
+	var button = document.querySelector('button.publishBtn');
+
+	Array.prototype.forEach.call(tabs, function (tab) {
+		tab.addEventListener('click', function () {
+			Array.prototype.forEach.call(tabs, function (t) {
+				t.classList.remove('active');
+			});
+			tab.classList.add('active');
+
+			mode = tab.textContent.trim() === '上传图文' ? 'image' : 'video';
+			input.multiple = mode === 'image';
+			input.accept = mode === 'image' ? '.jpg,.jpeg,.png,.webp' : '.mp4,.mov,.flv,.f4v,.mkv,.rm,.rmvb,.m4v,.mpg,.mpeg,.ts';
+			tip.textContent = mode === 'image' ? '拖拽图片到此或点击上传' : '拖拽视频到此或点击上传';
+		});
+	});
+
+	input.addEventListener('change', function () {
+		Array.prototype.forEach.call(input.files, function (file) {
+			files.push(file.name);
+			if (mode === 'image') {
+				previews.appendChild(el('div', 'pr', file.name));
+			}
+		});
+		editor.style.display = 'block';
+		// 视频需要转码，稍后才能发布
+		setTimeout(function () {
+			button.disabled = false;
+		}, mode === 'video' ? 800 : 0);
+	});
+
+	// 输入 # 后展示话题联想
+	var topicPattern = new RegExp('#([^\\s#]+)$');
+	textbox.addEventListener('input', function () {
+		var match = textbox.innerText.replace(/\n+$/, '').match(topicPattern);
+		topicContainer.textContent = '';
+		if (!match) {
+			topicContainer.style.display = 'none';
+			return;
+		}
+
+		var item = el('div', 'item', '#' + match[1]);
+		item.addEventListener('mousedown', function (e) {
+			e.preventDefault();
+		});
+		item.addEventListener('click', function () {
+			topics.push(match[1]);
+			topicContainer.textContent = '';
+			topicContainer.style.display = 'none';
+		});
+		topicContainer.appendChild(item);
+		topicContainer.style.display = 'block';
+	});
+
+	button.addEventListener('click', function () {
+		post('/web_api/sns/v2/note', {
+			type: mode === 'image' ? 'normal' : 'video',
+			title: title.value,
+			desc: textbox.innerText.trim(),
+			topics: topics,
+			files: files
+		}).then(function (res) {
+			if (res.success) {
+				document.querySelector('.publish-result').style.display = 'block';
+			}
+		});
+	});
+})();
+{{end}}`
+
+// errorPageHTML 错误页和创作服务平台的登录页，只展示提示信息
+const errorPageHTML = `{{define "content"}}<div class="error-page"><p class="error-msg">{{.Message}}</p></div>{{end}}
+
+{{define "script"}}{{end}}`
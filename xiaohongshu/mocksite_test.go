@@ -0,0 +1,356 @@
+package xiaohongshu
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/stretchr/testify/require"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu/mocksite"
+)
+
+// newMockSitePage 启动模拟站点和本地无头浏览器，找不到浏览器时跳过测试
+func newMockSitePage(t *testing.T, loggedIn bool) (*mocksite.Site, *rod.Page) {
+	t.Helper()
+
+	site, b := mocksite.NewBrowser(t)
+	page := b.NewPage()
+	if loggedIn {
+		page.MustSetCookies(site.Login())
+	}
+	return site, page
+}
+
+func writeTestImage(t *testing.T, name string) string {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8))))
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+	return path
+}
+
+func TestMockSiteFeedsAndSearch(t *testing.T) {
+	site, page := newMockSitePage(t, true)
+	ctx := context.Background()
+
+	feeds, err := NewFeedsListAction(page).GetFeedsList(ctx)
+	require.NoError(t, err)
+	require.Len(t, feeds, len(site.Notes()))
+	require.Equal(t, site.Notes()[0].ID, feeds[0].ID)
+	require.NotEmpty(t, feeds[0].XsecToken)
+
+	feeds, err = NewSearchAction(page).Search(ctx, "咖啡")
+	require.NoError(t, err)
+	require.Len(t, feeds, 1)
+	require.Equal(t, "上海周末咖啡馆合集", feeds[0].NoteCard.DisplayTitle)
+
+	feeds, err = NewSearchAction(page).Search(ctx, "", FilterOption{SortBy: "最多点赞", NoteType: "视频"})
+	require.NoError(t, err)
+	require.Len(t, feeds, 1)
+	require.Equal(t, "video", feeds[0].NoteCard.Type)
+	require.NotNil(t, feeds[0].NoteCard.Video)
+}
+
+func TestMockSiteSearchWithCursor(t *testing.T) {
+	site, page := newMockSitePage(t, true)
+	ctx := context.Background()
+
+	for i := 0; i < 45; i++ {
+		site.AddNote(mocksite.Note{Title: fmt.Sprintf("分页笔记 %d", i)})
+	}
+
+	action := NewSearchAction(page)
+	first, err := action.SearchWithCursor(ctx, "分页笔记", 30, "")
+	require.NoError(t, err)
+	require.Len(t, first.Feeds, 30)
+	require.NotEmpty(t, first.NextCursor)
+
+	second, err := action.SearchWithCursor(ctx, "分页笔记", 30, first.NextCursor)
+	require.NoError(t, err)
+	require.Len(t, second.Feeds, 15)
+	require.Empty(t, second.NextCursor)
+
+	// 结果恰好在分页边界结束时不返回指向空页的游标
+	boundary, err := action.SearchWithCursor(ctx, "分页笔记", 15, first.NextCursor)
+	require.NoError(t, err)
+	require.Len(t, boundary.Feeds, 15)
+	require.Empty(t, boundary.NextCursor)
+
+	seen := make(map[string]bool)
+	for _, feed := range append(first.Feeds, second.Feeds...) {
+		require.False(t, seen[feed.ID], "duplicated feed %s", feed.ID)
+		seen[feed.ID] = true
+	}
+
+	_, err = action.SearchWithCursor(ctx, "咖啡", 30, first.NextCursor)
+	require.Equal(t, myerrors.CodeValidationFailed, myerrors.CodeOf(err))
+}
+
+func TestMockSiteAPICapture(t *testing.T) {
+	site, page := newMockSitePage(t, true)
+
+	for i := 0; i < 25; i++ {
+		site.AddNote(mocksite.Note{Title: fmt.Sprintf("分页笔记 %d", i)})
+	}
+
+	capture, err := captureAPI(page, apiSearchNotes)
+	require.NoError(t, err)
+	defer capture.stop()
+
+	page.MustNavigate(makeSearchURL("分页笔记")).MustWaitLoad()
+	page.MustEval(`() => window.scrollTo(0, document.documentElement.scrollHeight)`)
+
+	// 第二页只通过接口返回，转换后与页面状态中的数据一致
+	require.Eventually(t, func() bool { return len(capture.feeds(apiSearchNotes)) == 5 }, 5*time.Second, 100*time.Millisecond)
+	feeds := capture.feeds(apiSearchNotes)
+	require.NotEmpty(t, feeds[0].XsecToken)
+	require.NotEmpty(t, feeds[0].NoteCard.DisplayTitle)
+	require.NotEmpty(t, feeds[0].NoteCard.User.UserID)
+
+	state, err := readSearchFeeds(page)
+	require.NoError(t, err)
+	require.Len(t, state, 25)
+	require.Equal(t, state[20].ID, feeds[0].ID)
+	require.Equal(t, state[20].NoteCard, feeds[0].NoteCard)
+}
+
+func TestMockSiteHomefeedAndNoteAPI(t *testing.T) {
+	site, page := newMockSitePage(t, true)
+	ctx := context.Background()
+
+	for i := 0; i < 25; i++ {
+		site.AddNote(mocksite.Note{Title: fmt.Sprintf("推荐笔记 %d", i)})
+	}
+	notes := site.Notes()
+
+	// 首屏之后的推荐只通过接口返回，不在页面状态中
+	feeds, err := NewFeedsListAction(page).GetFeedsList(ctx)
+	require.NoError(t, err)
+	require.Len(t, feeds, len(notes))
+	require.Equal(t, notes[len(notes)-1].ID, feeds[len(feeds)-1].ID)
+	require.NotEmpty(t, feeds[len(feeds)-1].XsecToken)
+
+	// 详情接口返回的笔记转换后与页面状态中的一致
+	note := notes[len(notes)-1]
+	capture, err := captureAPI(page, apiFeed)
+	require.NoError(t, err)
+	defer capture.stop()
+
+	detail, err := NewFeedDetailAction(page).GetFeedDetail(ctx, note.ID, note.XsecToken)
+	require.NoError(t, err)
+	captured, ok := capture.noteDetail(note.ID)
+	require.True(t, ok)
+	require.Equal(t, detail.Note, captured)
+}
+
+func TestMockSiteFeedDetailAndInteract(t *testing.T) {
+	site, page := newMockSitePage(t, true)
+	ctx := context.Background()
+	note := site.Notes()[len(site.Notes())-1]
+
+	detail, err := NewFeedDetailAction(page).GetFeedDetail(ctx, note.ID, note.XsecToken)
+	require.NoError(t, err)
+	require.Equal(t, note.Title, detail.Note.Title)
+	require.Len(t, detail.Comments.List, len(note.Comments))
+
+	require.NoError(t, NewLikeAction(page).Like(ctx, note.ID, note.XsecToken))
+	require.NoError(t, NewFavoriteAction(page).Favorite(ctx, note.ID, note.XsecToken))
+	got, _ := site.Note(note.ID)
+	require.True(t, got.Liked)
+	require.True(t, got.Collected)
+
+	require.NoError(t, NewLikeAction(page).Unlike(ctx, note.ID, note.XsecToken))
+	got, _ = site.Note(note.ID)
+	require.False(t, got.Liked)
+
+	require.NoError(t, NewCommentFeedAction(page).PostComment(ctx, note.ID, note.XsecToken, "离线评论"))
+	got, _ = site.Note(note.ID)
+	require.Equal(t, "离线评论", got.Comments[0].Content)
+}
+
+func TestMockSiteUserProfile(t *testing.T) {
+	site, page := newMockSitePage(t, true)
+	ctx := context.Background()
+	me := site.Me()
+
+	profile, err := NewUserProfileAction(page).UserProfile(ctx, me.UserID, "", 0)
+	require.NoError(t, err)
+	require.Equal(t, me.Nickname, profile.UserBasicInfo.Nickname)
+	require.Len(t, profile.Interactions, 3)
+	require.NotEmpty(t, profile.Feeds)
+
+	profile, err = NewUserProfileAction(page).GetMyProfileViaSidebar(ctx)
+	require.NoError(t, err)
+	require.Equal(t, me.RedID, profile.UserBasicInfo.RedId)
+}
+
+func TestMockSiteScrollToLoad(t *testing.T) {
+	site, page := newMockSitePage(t, true)
+	ctx := context.Background()
+	me := site.Me()
+
+	// 评论超过一页，后续分页只能滚动评论区通过接口加载
+	var comments []mocksite.Comment
+	for i := 0; i < 25; i++ {
+		comments = append(comments, mocksite.Comment{ID: fmt.Sprintf("c%02d", i), UserID: me.UserID, Content: fmt.Sprintf("评论 %d", i)})
+	}
+	note := site.AddNote(mocksite.Note{Title: "评论很多的笔记", Comments: comments})
+
+	detail, err := NewFeedDetailAction(page).GetFeedDetail(ctx, note.ID, note.XsecToken)
+	require.NoError(t, err)
+	require.Len(t, detail.Comments.List, len(comments))
+	require.Equal(t, "c24", detail.Comments.List[24].ID)
+	require.False(t, detail.Comments.HasMore)
+
+	// 主页的笔记超过一页，后续分页只能滚动页面通过接口加载
+	for i := 0; i < 35; i++ {
+		site.AddNote(mocksite.Note{Title: fmt.Sprintf("主页笔记 %d", i)})
+	}
+	mine := 0
+	for _, n := range site.Notes() {
+		if n.AuthorID == me.UserID {
+			mine++
+		}
+	}
+
+	// 不指定 limit 时只返回首屏的笔记，不滚动主页
+	profile, err := NewUserProfileAction(page).UserProfile(ctx, me.UserID, "", 0)
+	require.NoError(t, err)
+	require.Len(t, profile.Feeds, 30)
+
+	profile, err = NewUserProfileAction(page).UserProfile(ctx, me.UserID, "", MaxUserNotesLimit)
+	require.NoError(t, err)
+	require.Len(t, profile.Feeds, mine)
+
+	profile, err = NewUserProfileAction(page).UserProfile(ctx, me.UserID, "", 31)
+	require.NoError(t, err)
+	require.Len(t, profile.Feeds, 31)
+
+	_, err = NewUserProfileAction(page).UserProfile(ctx, me.UserID, "", MaxUserNotesLimit+1)
+	require.Equal(t, myerrors.CodeValidationFailed, myerrors.CodeOf(err))
+}
+
+func TestMockSitePublish(t *testing.T) {
+	site, page := newMockSitePage(t, true)
+	ctx := context.Background()
+
+	action := NewPublishImageAction(page)
+	require.NoError(t, action.Publish(ctx, PublishImageContent{
+		Title:      "离线发布的图文",
+		Content:    "在模拟站点上发布",
+		Tags:       []string{"测试"},
+		ImagePaths: []string{writeTestImage(t, "1.png"), writeTestImage(t, "2.png")},
+	}))
+
+	video := filepath.Join(t.TempDir(), "video.mp4")
+	require.NoError(t, os.WriteFile(video, []byte("mock video"), 0644))
+
+	action = NewPublishVideoAction(page)
+	require.NoError(t, action.PublishVideo(ctx, PublishVideoContent{
+		Title:     "离线发布的视频",
+		Content:   "视频正文",
+		VideoPath: video,
+	}))
+
+	publications := site.Publications()
+	require.Len(t, publications, 2)
+
+	require.Equal(t, "normal", publications[0].Type)
+	require.Equal(t, "离线发布的图文", publications[0].Title)
+	require.Contains(t, publications[0].Content, "在模拟站点上发布")
+	require.Equal(t, []string{"测试"}, publications[0].Topics)
+	require.Equal(t, []string{"1.png", "2.png"}, publications[0].Files)
+
+	require.Equal(t, "video", publications[1].Type)
+	require.Equal(t, []string{"video.mp4"}, publications[1].Files)
+}
+
+func TestMockSiteLoginAndLogout(t *testing.T) {
+	site, page := newMockSitePage(t, false)
+	ctx := context.Background()
+	action := NewLogin(page)
+
+	status, err := action.CheckLoginStatus(ctx)
+	require.NoError(t, err)
+	require.False(t, status.IsLoggedIn)
+
+	img, loggedIn, err := action.FetchQrcodeImage(ctx)
+	require.NoError(t, err)
+	require.False(t, loggedIn)
+	require.Contains(t, img, "data:image/png;base64,")
+
+	site.SetQrcodeStatus(mocksite.QrcodeScanned)
+	require.Eventually(t, func() bool {
+		state, err := action.GetQrcodeState(ctx)
+		return err == nil && state == QrcodeScanned
+	}, 5*time.Second, 200*time.Millisecond)
+
+	site.SetQrcodeStatus(mocksite.QrcodeConfirmed)
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	require.True(t, action.WaitForLogin(waitCtx))
+
+	status, err = action.CheckLoginStatus(ctx)
+	require.NoError(t, err)
+	require.True(t, status.IsLoggedIn)
+	require.Equal(t, site.Me().UserID, status.User.UserID)
+
+	loggedOut, err := action.Logout(ctx)
+	require.NoError(t, err)
+	require.True(t, loggedOut)
+	require.False(t, site.LoggedIn())
+}
+
+func TestMockSiteErrorCodes(t *testing.T) {
+	site, page := newMockSitePage(t, false)
+	ctx := context.Background()
+	note := site.Notes()[0]
+
+	_, err := NewFeedDetailAction(page).GetFeedDetail(ctx, note.ID, "invalid")
+	require.ErrorIs(t, err, myerrors.ErrXsecTokenInvalid)
+
+	_, err = NewFeedDetailAction(page).GetFeedDetail(ctx, "000000000000000000000000", note.XsecToken)
+	require.ErrorIs(t, err, myerrors.ErrNoteNotFound)
+
+	err = NewLikeAction(page).Like(ctx, note.ID, note.XsecToken)
+	require.ErrorIs(t, err, myerrors.ErrNotLoggedIn)
+	require.Equal(t, "like/check_login", myerrors.StepOf(err))
+
+	err = NewPublishImageAction(page).Publish(ctx, PublishImageContent{Title: "未登录", ImagePaths: []string{writeTestImage(t, "1.png")}})
+	require.ErrorIs(t, err, myerrors.ErrNotLoggedIn)
+
+	site.SetRateLimited(true)
+	_, err = NewFeedsListAction(page).GetFeedsList(ctx)
+	require.ErrorIs(t, err, myerrors.ErrRateLimited)
+}
+
+func TestMockSiteCaptcha(t *testing.T) {
+	site, page := newMockSitePage(t, true)
+	ctx := context.Background()
+
+	site.SetCaptcha(true)
+	_, err := NewFeedsListAction(page).GetFeedsList(ctx)
+	require.ErrorIs(t, err, myerrors.ErrCaptchaRequired)
+
+	_, err = NewLogin(page).CheckLoginStatus(ctx)
+	require.ErrorIs(t, err, myerrors.ErrCaptchaRequired)
+	require.Equal(t, "check_login_status/navigate", myerrors.StepOf(err))
+
+	present, err := CaptchaPresent(page)
+	require.NoError(t, err)
+	require.True(t, present)
+
+	site.SetCaptcha(false)
+	feeds, err := NewFeedsListAction(page).GetFeedsList(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, feeds)
+}
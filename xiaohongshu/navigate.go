@@ -3,15 +3,20 @@ package xiaohongshu
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 )
 
 type NavigateAction struct {
 	page *rod.Page
 }
 
+const actionNavigate = "navigate"
+
 func NewNavigate(page *rod.Page) *NavigateAction {
 	return &NavigateAction{page: page}
 }
@@ -19,9 +24,12 @@ func NewNavigate(page *rod.Page) *NavigateAction {
 func (n *NavigateAction) ToExplorePage(ctx context.Context) error {
 	page := n.page.Context(ctx)
 
-	page.MustNavigate("https://www.xiaohongshu.com/explore").
-		MustWaitLoad().
-		MustElement(`div#app`)
+	if err := navigate(page, configs.SiteURL("/explore")); err != nil {
+		return stepErr(actionNavigate, "navigate_explore", err)
+	}
+	if _, err := page.Element(`div#app`); err != nil {
+		return stepErr(actionNavigate, "wait_app", err)
+	}
 
 	return nil
 }
@@ -34,14 +42,19 @@ func (n *NavigateAction) ToProfilePage(ctx context.Context) error {
 		return err
 	}
 
-	page.MustWaitStable()
+	if err := page.WaitStable(time.Second); err != nil {
+		return stepErr(actionNavigate, "wait_stable", err)
+	}
 
 	// Find and click the "我" channel link in sidebar
-	profileLink := page.MustElement(`div.main-container li.user.side-bar-component a.link-wrapper span.channel`)
-	profileLink.MustClick()
+	if err := click(page, `div.main-container li.user.side-bar-component a.link-wrapper span.channel`); err != nil {
+		return stepErr(actionNavigate, "click_profile_link", err)
+	}
 
 	// Wait for navigation to complete
-	page.MustWaitLoad()
+	if err := page.WaitLoad(); err != nil {
+		return stepErr(actionNavigate, "wait_load", err)
+	}
 
 	return nil
 }
@@ -54,13 +67,15 @@ func (n *NavigateAction) ToUserLikesPage(ctx context.Context) error {
 		return err
 	}
 
-	page.MustWaitStable()
+	if err := page.WaitStable(time.Second); err != nil {
+		return stepErr(actionNavigate, "wait_stable", err)
+	}
 
 	likesTab, err := page.Element(`div.reds-tab-item.sub-tab-list span:contains("点赞")`)
 	if err != nil {
 		// 如果直接找不到，尝试使用 JavaScript 查找
 		logrus.Info("Using JavaScript to find and click likes tab")
-		result := page.MustEval(`() => {
+		result, err := page.Eval(`() => {
 				const tabs = document.querySelectorAll('div.reds-tab-item.sub-tab-list span');
 				for (const tab of tabs) {
 					if (tab.textContent === '点赞') {
@@ -69,19 +84,26 @@ func (n *NavigateAction) ToUserLikesPage(ctx context.Context) error {
 					}
 				}
 				return false;
-			}`).Bool()
+			}`)
+		if err != nil {
+			return stepErr(actionNavigate, "click_likes_tab", err)
+		}
 
-		if !result {
-			return fmt.Errorf("could not find likes tab")
+		if !result.Value.Bool() {
+			return stepErr(actionNavigate, "find_likes_tab", fmt.Errorf("could not find likes tab"))
 		}
 		logrus.Info("Successfully clicked likes tab using JavaScript")
 	} else {
-		likesTab.MustClick()
+		if err := likesTab.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return stepErr(actionNavigate, "click_likes_tab", err)
+		}
 		logrus.Info("Successfully clicked likes tab")
 	}
 
 	// 等待导航完成
-	page.MustWaitLoad()
+	if err := page.WaitLoad(); err != nil {
+		return stepErr(actionNavigate, "wait_load", err)
+	}
 
 	return nil
 }
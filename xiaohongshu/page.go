@@ -0,0 +1,133 @@
+package xiaohongshu
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+// 各操作的默认超时时间，调用方的 ctx 更早结束时以 ctx 为准
+const (
+	defaultActionTimeout = 60 * time.Second
+	publishActionTimeout = 300 * time.Second
+)
+
+// stepErr 包装操作某一步的错误，err 为 nil 时返回 nil。
+// 没有错误码的错误按步骤归类，如 find_* 步骤超时归为 SELECTOR_NOT_FOUND。
+func stepErr(action, step string, err error) error {
+	return myerrors.NewActionError(action, step, classifyStepErr(step, err))
+}
+
+func classifyStepErr(step string, err error) error {
+	if err == nil || myerrors.CodeOf(err) != "" {
+		return err
+	}
+
+	var notFound *rod.ElementNotFoundError
+	timeout := errors.Is(err, context.DeadlineExceeded)
+
+	switch {
+	case step == "validate" || strings.HasPrefix(step, "validate_"):
+		return myerrors.WithCode(myerrors.CodeValidationFailed, err)
+	case errors.As(err, &notFound), timeout && strings.HasPrefix(step, "find_"):
+		return myerrors.WithCode(myerrors.CodeSelectorNotFound, err)
+	case step == "wait_upload" && !errors.Is(err, context.Canceled):
+		return myerrors.WithCode(myerrors.CodeUploadTimeout, err)
+	default:
+		return err
+	}
+}
+
+// 错误页 URL 中 error_code 参数的取值
+const (
+	errorCodeRateLimited      = "300013" // 访问频次异常，请勿频繁操作
+	errorCodeXsecTokenInvalid = "300031" // 当前笔记暂时无法浏览
+)
+
+// checkPage 检查页面是否被重定向到了错误页、验证码页或登录页，以及是否弹出了验证层
+func checkPage(page *rod.Page) error {
+	info, err := page.Info()
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(info.URL)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasPrefix(u.Path, "/404"), strings.HasPrefix(u.Path, "/website-login/error"):
+		switch u.Query().Get("error_code") {
+		case errorCodeRateLimited:
+			return myerrors.ErrRateLimited
+		case errorCodeXsecTokenInvalid:
+			return myerrors.ErrXsecTokenInvalid
+		default:
+			return myerrors.ErrNoteNotFound
+		}
+	case strings.HasPrefix(u.Path, "/login"):
+		return myerrors.ErrNotLoggedIn
+	}
+	return checkCaptcha(page)
+}
+
+// requireLogin 需要登录的操作在页面上确认已登录
+func requireLogin(page *rod.Page) error {
+	exists, _, err := page.Has(selectorLoggedUser)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return myerrors.ErrNotLoggedIn
+	}
+	return nil
+}
+
+// navigate 打开页面并等待加载完成，页面出现验证码时返回 CAPTCHA_REQUIRED
+func navigate(page *rod.Page, url string) error {
+	if err := page.Navigate(url); err != nil {
+		return err
+	}
+	if err := page.WaitLoad(); err != nil {
+		return err
+	}
+	return checkCaptcha(page)
+}
+
+// evalString 执行返回字符串的脚本
+func evalString(page *rod.Page, js string) (string, error) {
+	result, err := page.Eval(js)
+	if err != nil {
+		return "", err
+	}
+	return result.Value.String(), nil
+}
+
+// click 查找元素并点击
+func click(page *rod.Page, selector string) error {
+	el, err := page.Element(selector)
+	if err != nil {
+		return err
+	}
+	return el.Click(proto.InputMouseButtonLeft, 1)
+}
+
+// sleep 等待指定时长，ctx 结束时提前返回 ctx 的错误
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,37 @@
+package xiaohongshu
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-rod/rod"
+	"github.com/stretchr/testify/require"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+func TestStepErrCodes(t *testing.T) {
+	cases := []struct {
+		step string
+		err  error
+		want myerrors.Code
+	}{
+		{"validate", errors.New("图片不能为空"), myerrors.CodeValidationFailed},
+		{"validate_filters", errors.New("无效的排序依据"), myerrors.CodeValidationFailed},
+		{"find_upload_input", context.DeadlineExceeded, myerrors.CodeSelectorNotFound},
+		{"click_submit", &rod.ElementNotFoundError{}, myerrors.CodeSelectorNotFound},
+		{"wait_upload", errors.New("上传超时"), myerrors.CodeUploadTimeout},
+		{"wait_upload", context.Canceled, ""},
+		{"navigate", context.DeadlineExceeded, ""},
+		{"check_page", myerrors.ErrRateLimited, myerrors.CodeRateLimited},
+	}
+
+	for _, c := range cases {
+		err := stepErr("test", c.step, c.err)
+		require.Equal(t, c.want, myerrors.CodeOf(err), c.step)
+		require.ErrorIs(t, err, c.err, c.step)
+		require.Equal(t, "test/"+c.step, myerrors.StepOf(err))
+	}
+
+	require.NoError(t, stepErr("test", "navigate", nil))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 )
 
 // PublishImageContent 发布图文内容
@@ -27,38 +28,37 @@ type PublishAction struct {
 	page *rod.Page
 }
 
+// urlOfPublic 创作服务平台的发布页
+func urlOfPublic() string {
+	return configs.CreatorURL("/publish/publish?source=official")
+}
+
 const (
-	urlOfPublic = `https://creator.xiaohongshu.com/publish/publish?source=official`
+	actionPublishImage = "publish_image"
+	actionPublishVideo = "publish_video"
 )
 
-func NewPublishImageAction(page *rod.Page) (*PublishAction, error) {
-
-	pp := page.Timeout(300 * time.Second)
-
-	pp.MustNavigate(urlOfPublic).MustWaitIdle().MustWaitDOMStable()
-	time.Sleep(1 * time.Second)
-
-	if err := mustClickPublishTab(page, "上传图文"); err != nil {
-		logrus.Errorf("点击上传图文 TAB 失败: %v", err)
-		return nil, err
-	}
-
-	time.Sleep(1 * time.Second)
-
-	return &PublishAction{
-		page: pp,
-	}, nil
+// NewPublishImageAction 创建发布动作，发布时进入发布页并切换到对应的 TAB
+func NewPublishImageAction(page *rod.Page) *PublishAction {
+	return &PublishAction{page: page}
 }
 
 func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent) error {
 	if len(content.ImagePaths) == 0 {
-		return errors.New("图片不能为空")
+		return stepErr(actionPublishImage, "validate", errors.New("图片不能为空"))
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, publishActionTimeout)
+	defer cancel()
+
 	page := p.page.Context(ctx)
 
-	if err := uploadImages(page, content.ImagePaths); err != nil {
-		return errors.Wrap(err, "小红书上传图片失败")
+	if err := openPublishPage(ctx, page, actionPublishImage, "上传图文"); err != nil {
+		return err
+	}
+
+	if err := uploadImages(ctx, page, content.ImagePaths); err != nil {
+		return err
 	}
 
 	tags := content.Tags
@@ -69,10 +69,35 @@ func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent
 
 	logrus.Infof("发布内容: title=%s, images=%v, tags=%v", content.Title, len(content.ImagePaths), tags)
 
-	if err := submitPublish(page, content.Title, content.Content, tags); err != nil {
-		return errors.Wrap(err, "小红书发布失败")
+	return submitPublish(ctx, page, content.Title, content.Content, tags)
+}
+
+// openPublishPage 打开创作服务平台的发布页并切换到指定的发布 TAB
+func openPublishPage(ctx context.Context, page *rod.Page, action, tabname string) error {
+	if err := page.Navigate(urlOfPublic()); err != nil {
+		return stepErr(action, "navigate", err)
+	}
+	if err := page.WaitIdle(time.Minute); err != nil {
+		return stepErr(action, "wait_idle", err)
+	}
+	if err := page.WaitDOMStable(time.Second, 0); err != nil {
+		return stepErr(action, "wait_dom_stable", err)
+	}
+	if err := checkPage(page); err != nil {
+		return stepErr(action, "check_page", err)
+	}
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return stepErr(action, "wait_load", err)
 	}
 
+	if err := clickPublishTab(ctx, page, tabname); err != nil {
+		logrus.Errorf("点击%s TAB 失败: %v", tabname, err)
+		return stepErr(action, "click_publish_tab", err)
+	}
+
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return stepErr(action, "click_publish_tab", err)
+	}
 	return nil
 }
 
@@ -84,7 +109,9 @@ func removePopCover(page *rod.Page) {
 		return
 	}
 	if has {
-		elem.MustRemove()
+		if err := elem.Remove(); err != nil {
+			logrus.Warnf("移除弹窗失败: %v", err)
+		}
 	}
 
 	// 兜底：点击一下空位置吧
@@ -94,40 +121,43 @@ func removePopCover(page *rod.Page) {
 func clickEmptyPosition(page *rod.Page) {
 	x := 380 + rand.Intn(100)
 	y := 20 + rand.Intn(60)
-	page.Mouse.MustMoveTo(float64(x), float64(y)).MustClick(proto.InputMouseButtonLeft)
+	if err := page.Mouse.MoveTo(proto.Point{X: float64(x), Y: float64(y)}); err != nil {
+		logrus.Warnf("移动鼠标失败: %v", err)
+		return
+	}
+	if err := page.Mouse.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		logrus.Warnf("点击空白位置失败: %v", err)
+	}
 }
 
-func mustClickPublishTab(page *rod.Page, tabname string) error {
-	page.MustElement(`div.upload-content`).MustWaitVisible()
+func clickPublishTab(ctx context.Context, page *rod.Page, tabname string) error {
+	uploadContent, err := page.Element(`div.upload-content`)
+	if err != nil {
+		return err
+	}
+	if err := uploadContent.WaitVisible(); err != nil {
+		return err
+	}
 
 	deadline := time.Now().Add(15 * time.Second)
 	for time.Now().Before(deadline) {
 		tab, blocked, err := getTabElement(page, tabname)
 		if err != nil {
 			logrus.Warnf("获取发布 TAB 元素失败: %v", err)
-			time.Sleep(200 * time.Millisecond)
-			continue
-		}
-
-		if tab == nil {
-			time.Sleep(200 * time.Millisecond)
-			continue
-		}
-
-		if blocked {
+		} else if tab == nil {
+			// 还没有渲染出来，继续等待
+		} else if blocked {
 			logrus.Info("发布 TAB 被遮挡，尝试移除遮挡")
 			removePopCover(page)
-			time.Sleep(200 * time.Millisecond)
-			continue
-		}
-
-		if err := tab.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		} else if err := tab.Click(proto.InputMouseButtonLeft, 1); err != nil {
 			logrus.Warnf("点击发布 TAB 失败: %v", err)
-			time.Sleep(200 * time.Millisecond)
-			continue
+		} else {
+			return nil
 		}
 
-		return nil
+		if err := sleep(ctx, 200*time.Millisecond); err != nil {
+			return err
+		}
 	}
 
 	return errors.Errorf("没有找到发布 TAB - %s", tabname)
@@ -183,9 +213,7 @@ func isElementBlocked(elem *rod.Element) (bool, error) {
 	return result.Value.Bool(), nil
 }
 
-func uploadImages(page *rod.Page, imagesPaths []string) error {
-	pp := page.Timeout(30 * time.Second)
-
+func uploadImages(ctx context.Context, page *rod.Page, imagesPaths []string) error {
 	// 验证文件路径有效性
 	validPaths := make([]string, 0, len(imagesPaths))
 	for _, path := range imagesPaths {
@@ -199,17 +227,25 @@ func uploadImages(page *rod.Page, imagesPaths []string) error {
 	}
 
 	// 等待上传输入框出现
-	uploadInput := pp.MustElement(".upload-input")
+	uploadInput, err := page.Timeout(30 * time.Second).Element(".upload-input")
+	if err != nil {
+		return stepErr(actionPublishImage, "find_upload_input", err)
+	}
 
 	// 上传多个文件
-	uploadInput.MustSetFiles(validPaths...)
+	if err := uploadInput.SetFiles(validPaths); err != nil {
+		return stepErr(actionPublishImage, "upload_images", err)
+	}
 
 	// 等待并验证上传完成
-	return waitForUploadComplete(pp, len(validPaths))
+	if err := waitForUploadComplete(ctx, page, len(validPaths)); err != nil {
+		return stepErr(actionPublishImage, "wait_upload", err)
+	}
+	return nil
 }
 
 // waitForUploadComplete 等待并验证上传完成
-func waitForUploadComplete(page *rod.Page, expectedCount int) error {
+func waitForUploadComplete(ctx context.Context, page *rod.Page, expectedCount int) error {
 	maxWaitTime := 60 * time.Second
 	checkInterval := 500 * time.Millisecond
 	start := time.Now()
@@ -233,126 +269,179 @@ func waitForUploadComplete(page *rod.Page, expectedCount int) error {
 			slog.Debug("未找到已上传图片元素")
 		}
 
-		time.Sleep(checkInterval)
+		if err := sleep(ctx, checkInterval); err != nil {
+			return err
+		}
 	}
 
 	return errors.New("上传超时，请检查网络连接和图片大小")
 }
 
-func submitPublish(page *rod.Page, title, content string, tags []string) error {
+func submitPublish(ctx context.Context, page *rod.Page, title, content string, tags []string) error {
+	if err := inputTitleAndContent(ctx, page, actionPublishImage, title, content, tags); err != nil {
+		return err
+	}
 
-	titleElem := page.MustElement("div.d-input input")
-	titleElem.MustInput(title)
+	if err := click(page, "div.submit div.d-button-content"); err != nil {
+		return stepErr(actionPublishImage, "click_publish", err)
+	}
 
-	time.Sleep(1 * time.Second)
+	if err := sleep(ctx, 3*time.Second); err != nil {
+		return stepErr(actionPublishImage, "wait_publish", err)
+	}
 
-	if contentElem, ok := getContentElement(page); ok {
-		contentElem.MustInput(content)
+	return nil
+}
 
-		inputTags(contentElem, tags)
+// inputTitleAndContent 填写标题、正文和标签，图文和视频发布共用
+func inputTitleAndContent(ctx context.Context, page *rod.Page, action, title, content string, tags []string) error {
+	titleElem, err := page.Element("div.d-input input")
+	if err != nil {
+		return stepErr(action, "find_title_input", err)
+	}
+	if err := titleElem.Input(title); err != nil {
+		return stepErr(action, "input_title", err)
+	}
 
-	} else {
-		return errors.New("没有找到内容输入框")
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return stepErr(action, "input_title", err)
 	}
 
-	time.Sleep(1 * time.Second)
+	contentElem, err := getContentElement(page)
+	if err != nil {
+		return stepErr(action, "find_content_input", err)
+	}
+	if err := contentElem.Input(content); err != nil {
+		return stepErr(action, "input_content", err)
+	}
 
-	submitButton := page.MustElement("div.submit div.d-button-content")
-	submitButton.MustClick()
+	if err := inputTags(ctx, contentElem, tags); err != nil {
+		return stepErr(action, "input_tags", err)
+	}
 
-	time.Sleep(3 * time.Second)
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return stepErr(action, "input_tags", err)
+	}
 
 	return nil
 }
 
 // 查找内容输入框 - 使用Race方法处理两种样式
-func getContentElement(page *rod.Page) (*rod.Element, bool) {
-	var foundElement *rod.Element
-	var found bool
-
-	page.Race().
-		Element("div.ql-editor").MustHandle(func(e *rod.Element) {
-		foundElement = e
-		found = true
-	}).
+func getContentElement(page *rod.Page) (*rod.Element, error) {
+	elem, err := page.Race().
+		Element("div.ql-editor").
 		ElementFunc(func(page *rod.Page) (*rod.Element, error) {
 			return findTextboxByPlaceholder(page)
-		}).MustHandle(func(e *rod.Element) {
-		foundElement = e
-		found = true
-	}).
-		MustDo()
-
-	if found {
-		return foundElement, true
+		}).
+		Do()
+	if err != nil {
+		slog.Warn("no content element found by any method")
+		return nil, errors.Wrap(err, "没有找到内容输入框")
 	}
 
-	slog.Warn("no content element found by any method")
-	return nil, false
+	return elem, nil
 }
 
-func inputTags(contentElem *rod.Element, tags []string) {
+func inputTags(ctx context.Context, contentElem *rod.Element, tags []string) error {
 	if len(tags) == 0 {
-		return
+		return nil
 	}
 
-	time.Sleep(1 * time.Second)
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return err
+	}
 
 	for i := 0; i < 20; i++ {
-		contentElem.MustKeyActions().
-			Type(input.ArrowDown).
-			MustDo()
-		time.Sleep(10 * time.Millisecond)
+		if err := pressKeys(contentElem, input.ArrowDown); err != nil {
+			return err
+		}
+		if err := sleep(ctx, 10*time.Millisecond); err != nil {
+			return err
+		}
 	}
 
-	contentElem.MustKeyActions().
-		Press(input.Enter).
-		Press(input.Enter).
-		MustDo()
+	if err := pressKeys(contentElem, input.Enter, input.Enter); err != nil {
+		return err
+	}
 
-	time.Sleep(1 * time.Second)
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return err
+	}
 
 	for _, tag := range tags {
 		tag = strings.TrimLeft(tag, "#")
-		inputTag(contentElem, tag)
+		if err := inputTag(ctx, contentElem, tag); err != nil {
+			return errors.Wrapf(err, "输入标签 %s 失败", tag)
+		}
 	}
+	return nil
 }
 
-func inputTag(contentElem *rod.Element, tag string) {
-	contentElem.MustInput("#")
-	time.Sleep(200 * time.Millisecond)
+// pressKeys 在元素上依次按下按键
+func pressKeys(elem *rod.Element, keys ...input.Key) error {
+	ka, err := elem.KeyActions()
+	if err != nil {
+		return err
+	}
+	return ka.Press(keys...).Do()
+}
+
+func inputTag(ctx context.Context, contentElem *rod.Element, tag string) error {
+	if err := contentElem.Input("#"); err != nil {
+		return err
+	}
+	if err := sleep(ctx, 200*time.Millisecond); err != nil {
+		return err
+	}
 
 	for _, char := range tag {
-		contentElem.MustInput(string(char))
-		time.Sleep(50 * time.Millisecond)
+		if err := contentElem.Input(string(char)); err != nil {
+			return err
+		}
+		if err := sleep(ctx, 50*time.Millisecond); err != nil {
+			return err
+		}
 	}
 
-	time.Sleep(1 * time.Second)
+	if err := sleep(ctx, 1*time.Second); err != nil {
+		return err
+	}
 
 	page := contentElem.Page()
 	topicContainer, err := page.Element("#creator-editor-topic-container")
 	if err == nil && topicContainer != nil {
 		firstItem, err := topicContainer.Element(".item")
 		if err == nil && firstItem != nil {
-			firstItem.MustClick()
+			if err := firstItem.Click(proto.InputMouseButtonLeft, 1); err != nil {
+				return err
+			}
 			slog.Info("成功点击标签联想选项", "tag", tag)
-			time.Sleep(200 * time.Millisecond)
+			if err := sleep(ctx, 200*time.Millisecond); err != nil {
+				return err
+			}
 		} else {
 			slog.Warn("未找到标签联想选项，直接输入空格", "tag", tag)
 			// 如果没有找到联想选项，输入空格结束
-			contentElem.MustInput(" ")
+			if err := contentElem.Input(" "); err != nil {
+				return err
+			}
 		}
 	} else {
 		slog.Warn("未找到标签联想下拉框，直接输入空格", "tag", tag)
 		// 如果没有找到下拉框，输入空格结束
-		contentElem.MustInput(" ")
+		if err := contentElem.Input(" "); err != nil {
+			return err
+		}
 	}
 
-	time.Sleep(500 * time.Millisecond) // 等待标签处理完成
+	return sleep(ctx, 500*time.Millisecond) // 等待标签处理完成
 }
 
 func findTextboxByPlaceholder(page *rod.Page) (*rod.Element, error) {
-	elements := page.MustElements("p")
+	elements, err := page.Elements("p")
+	if err != nil {
+		return nil, err
+	}
 	if elements == nil {
 		return nil, errors.New("no p elements found")
 	}
@@ -7,7 +7,6 @@ import (
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
 
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
 )
 
 func TestPublish(t *testing.T) {
@@ -20,10 +19,9 @@ func TestPublish(t *testing.T) {
 	page := b.NewPage()
 	defer page.Close()
 
-	action, err := NewPublishImageAction(page)
-	require.NoError(t, err)
+	action := NewPublishImageAction(page)
 
-	err = action.Publish(context.Background(), PublishImageContent{
+	err := action.Publish(context.Background(), PublishImageContent{
 		Title:      "Hello World",
 		Content:    "Hello World",
 		ImagePaths: []string{"/tmp/1.jpg"},
@@ -20,72 +20,65 @@ type PublishVideoContent struct {
 	VideoPath string
 }
 
-// NewPublishVideoAction 进入发布页并切换到“上传视频”
-func NewPublishVideoAction(page *rod.Page) (*PublishAction, error) {
-	pp := page.Timeout(300 * time.Second)
-
-	pp.MustNavigate(urlOfPublic).MustWaitIdle().MustWaitDOMStable()
-	time.Sleep(1 * time.Second)
-
-	if err := mustClickPublishTab(page, "上传视频"); err != nil {
-		return nil, errors.Wrap(err, "切换到上传视频失败")
-	}
-
-	time.Sleep(1 * time.Second)
-
-	return &PublishAction{page: pp}, nil
+// NewPublishVideoAction 创建视频发布动作，发布时进入发布页并切换到“上传视频”
+func NewPublishVideoAction(page *rod.Page) *PublishAction {
+	return &PublishAction{page: page}
 }
 
 // PublishVideo 上传视频并提交
 func (p *PublishAction) PublishVideo(ctx context.Context, content PublishVideoContent) error {
 	if content.VideoPath == "" {
-		return errors.New("视频不能为空")
+		return stepErr(actionPublishVideo, "validate", errors.New("视频不能为空"))
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, publishActionTimeout)
+	defer cancel()
+
 	page := p.page.Context(ctx)
 
-	if err := uploadVideo(page, content.VideoPath); err != nil {
-		return errors.Wrap(err, "小红书上传视频失败")
+	if err := openPublishPage(ctx, page, actionPublishVideo, "上传视频"); err != nil {
+		return err
 	}
 
-	if err := submitPublishVideo(page, content.Title, content.Content, content.Tags); err != nil {
-		return errors.Wrap(err, "小红书发布失败")
+	if err := uploadVideo(ctx, page, content.VideoPath); err != nil {
+		return err
 	}
-	return nil
+
+	return submitPublishVideo(ctx, page, content.Title, content.Content, content.Tags)
 }
 
 // uploadVideo 上传单个本地视频
-func uploadVideo(page *rod.Page, videoPath string) error {
-	pp := page.Timeout(5 * time.Minute) // 视频处理耗时更长
-
+func uploadVideo(ctx context.Context, page *rod.Page, videoPath string) error {
 	if _, err := os.Stat(videoPath); os.IsNotExist(err) {
-		return errors.Wrapf(err, "视频文件不存在: %s", videoPath)
+		return stepErr(actionPublishVideo, "validate", errors.Wrapf(err, "视频文件不存在: %s", videoPath))
 	}
 
 	// 寻找文件上传输入框（与图文一致的 class，或退回到 input[type=file]）
 	var fileInput *rod.Element
 	var err error
-	fileInput, err = pp.Element(".upload-input")
+	fileInput, err = page.Element(".upload-input")
 	if err != nil || fileInput == nil {
-		fileInput, err = pp.Element("input[type='file']")
+		fileInput, err = page.Element("input[type='file']")
 		if err != nil || fileInput == nil {
-			return errors.New("未找到视频上传输入框")
+			return stepErr(actionPublishVideo, "find_upload_input", errors.New("未找到视频上传输入框"))
 		}
 	}
 
-	fileInput.MustSetFiles(videoPath)
+	if err := fileInput.SetFiles([]string{videoPath}); err != nil {
+		return stepErr(actionPublishVideo, "upload_video", err)
+	}
 
 	// 对于视频，等待发布按钮变为可点击即表示处理完成
-	btn, err := waitForPublishButtonClickable(pp)
+	btn, err := waitForPublishButtonClickable(ctx, page)
 	if err != nil {
-		return err
+		return stepErr(actionPublishVideo, "wait_upload", err)
 	}
 	slog.Info("视频上传/处理完成，发布按钮可点击", "btn", btn)
 	return nil
 }
 
 // waitForPublishButtonClickable 等待发布按钮可点击
-func waitForPublishButtonClickable(page *rod.Page) (*rod.Element, error) {
+func waitForPublishButtonClickable(ctx context.Context, page *rod.Page) (*rod.Element, error) {
 	maxWait := 10 * time.Minute
 	interval := 1 * time.Second
 	start := time.Now()
@@ -110,39 +103,33 @@ func waitForPublishButtonClickable(page *rod.Page) (*rod.Element, error) {
 				}
 			}
 		}
-		time.Sleep(interval)
+		if err := sleep(ctx, interval); err != nil {
+			return nil, err
+		}
 	}
 	return nil, errors.New("等待发布按钮可点击超时")
 }
 
 // submitPublishVideo 填写标题、正文、标签并点击发布（等待按钮可点击后再提交）
-func submitPublishVideo(page *rod.Page, title, content string, tags []string) error {
-	// 标题
-	titleElem := page.MustElement("div.d-input input")
-	titleElem.MustInput(title)
-	time.Sleep(1 * time.Second)
-
-	// 正文 + 标签
-	if contentElem, ok := getContentElement(page); ok {
-		contentElem.MustInput(content)
-		inputTags(contentElem, tags)
-	} else {
-		return errors.New("没有找到内容输入框")
+func submitPublishVideo(ctx context.Context, page *rod.Page, title, content string, tags []string) error {
+	// 标题、正文 + 标签
+	if err := inputTitleAndContent(ctx, page, actionPublishVideo, title, content, tags); err != nil {
+		return err
 	}
 
-	time.Sleep(1 * time.Second)
-
 	// 等待发布按钮可点击
-	btn, err := waitForPublishButtonClickable(page)
+	btn, err := waitForPublishButtonClickable(ctx, page)
 	if err != nil {
-		return err
+		return stepErr(actionPublishVideo, "wait_publish_button", err)
 	}
 
 	// 点击发布
 	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
-		return errors.Wrap(err, "点击发布按钮失败")
+		return stepErr(actionPublishVideo, "click_publish", err)
 	}
 
-	time.Sleep(3 * time.Second)
+	if err := sleep(ctx, 3*time.Second); err != nil {
+		return stepErr(actionPublishVideo, "wait_publish", err)
+	}
 	return nil
 }
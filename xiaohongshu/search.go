@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
@@ -159,62 +160,164 @@ type SearchAction struct {
 	page *rod.Page
 }
 
-func NewSearchAction(page *rod.Page) *SearchAction {
-	pp := page.Timeout(60 * time.Second)
+const actionSearch = "search"
 
-	return &SearchAction{page: pp}
+func NewSearchAction(page *rod.Page) *SearchAction {
+	return &SearchAction{page: page}
 }
 
 func (s *SearchAction) Search(ctx context.Context, keyword string, filters ...FilterOption) ([]Feed, error) {
+	// 将所有 FilterOption 转换为内部筛选选项，先校验再打开页面
+	allInternalFilters, err := convertFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
+
 	page := s.page.Context(ctx)
 
-	searchURL := makeSearchURL(keyword)
-	page.MustNavigate(searchURL)
-	page.MustWaitStable()
+	capture, err := captureAPI(page, apiSearchNotes)
+	if err != nil {
+		return nil, stepErr(actionSearch, "capture_api", err)
+	}
+	defer capture.stop()
 
-	page.MustWait(`() => window.__INITIAL_STATE__ !== undefined`)
+	if err := openSearch(page, capture, keyword, allInternalFilters); err != nil {
+		return nil, err
+	}
 
-	// 如果有筛选条件，则应用筛选
-	if len(filters) > 0 {
-		// 将所有 FilterOption 转换为内部筛选选项
-		var allInternalFilters []internalFilterOption
-		for _, filter := range filters {
-			internalFilters, err := convertToInternalFilters(filter)
-			if err != nil {
-				return nil, fmt.Errorf("筛选选项转换失败: %w", err)
-			}
-			allInternalFilters = append(allInternalFilters, internalFilters...)
+	feeds, err := searchFeeds(page, capture)
+	if err != nil {
+		return nil, err
+	}
+	if feeds == nil {
+		return nil, stepErr(actionSearch, "read_state", errors.ErrNoFeeds)
+	}
+
+	return feeds, nil
+}
+
+// SearchWithCursor 分页搜索：滚动结果页加载更多，按笔记 ID 去重，直到收集到 limit 条或没有更多结果。
+// cursor 为上一次返回的 NextCursor，为空时从第一条开始，续页时需要使用相同的关键词和筛选条件。
+// 游标记录的是已返回的条数，续页时会重新打开结果页并滚动到该位置，期间结果有变化时可能重复或遗漏少量笔记；
+// 游标最多翻到 MaxSearchOffset 条，超时前没有收集够时返回已收集的部分和继续的游标。
+func (s *SearchAction) SearchWithCursor(ctx context.Context, keyword string, limit int, cursor string, filters ...FilterOption) (*SearchPage, error) {
+	if limit < 0 || limit > MaxSearchLimit {
+		return nil, errors.Validation(fmt.Sprintf("limit 需要在 0 到 %d 之间", MaxSearchLimit))
+	}
+	if limit == 0 {
+		limit = DefaultSearchLimit
+	}
+
+	offset, err := decodeSearchCursor(cursor, keyword, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	allInternalFilters, err := convertFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
+
+	page := s.page.Context(ctx)
+
+	capture, err := captureAPI(page, apiSearchNotes)
+	if err != nil {
+		return nil, stepErr(actionSearch, "capture_api", err)
+	}
+	defer capture.stop()
+
+	if err := openSearch(page, capture, keyword, allInternalFilters); err != nil {
+		return nil, err
+	}
+
+	// 多收集一条用于判断后面是否还有结果，避免结果恰好在分页边界结束时返回指向空页的游标
+	feeds, truncated, err := collectSearchFeeds(ctx, page, capture, offset+limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if truncated && len(feeds) <= offset {
+		return nil, stepErr(actionSearch, "scroll", fmt.Errorf("在超时前只加载到 %d 条结果，未到达游标位置 %d: %w",
+			len(feeds), offset, context.DeadlineExceeded))
+	}
+
+	result := &SearchPage{Feeds: []Feed{}}
+	if offset < len(feeds) {
+		result.Feeds = feeds[offset:min(offset+limit, len(feeds))]
+	}
+
+	// 超时前没有滚动到底时返回已收集的结果，下一页从这里继续
+	next := offset + len(result.Feeds)
+	if (truncated || len(feeds) > offset+limit) && next <= MaxSearchOffset {
+		result.NextCursor = encodeSearchCursor(keyword, filters, next)
+	}
+	return result, nil
+}
+
+// convertFilters 将 FilterOption 转换为内部筛选选项并校验
+func convertFilters(filters []FilterOption) ([]internalFilterOption, error) {
+	var allInternalFilters []internalFilterOption
+	for _, filter := range filters {
+		internalFilters, err := convertToInternalFilters(filter)
+		if err != nil {
+			return nil, stepErr(actionSearch, "validate_filters", fmt.Errorf("筛选选项转换失败: %w", err))
 		}
+		allInternalFilters = append(allInternalFilters, internalFilters...)
+	}
 
-		// 验证所有内部筛选选项
-		for _, filter := range allInternalFilters {
-			if err := validateInternalFilterOption(filter); err != nil {
-				return nil, fmt.Errorf("筛选选项验证失败: %w", err)
-			}
+	// 验证所有内部筛选选项
+	for _, filter := range allInternalFilters {
+		if err := validateInternalFilterOption(filter); err != nil {
+			return nil, stepErr(actionSearch, "validate_filters", fmt.Errorf("筛选选项验证失败: %w", err))
 		}
+	}
 
-		// 悬停在筛选按钮上
-		filterButton := page.MustElement(`div.filter`)
-		filterButton.MustHover()
+	return allInternalFilters, nil
+}
 
-		// 等待筛选面板出现
-		page.MustWait(`() => document.querySelector('div.filter-panel') !== null`)
+// openSearch 打开搜索结果页，有筛选条件时应用筛选
+func openSearch(page *rod.Page, capture *apiCapture, keyword string, filters []internalFilterOption) error {
+	if err := page.Navigate(makeSearchURL(keyword)); err != nil {
+		return stepErr(actionSearch, "navigate", err)
+	}
+	if err := page.WaitStable(time.Second); err != nil {
+		return stepErr(actionSearch, "wait_stable", err)
+	}
+	if err := checkPage(page); err != nil {
+		return stepErr(actionSearch, "check_page", err)
+	}
+	if err := page.Wait(rod.Eval(`() => window.__INITIAL_STATE__ !== undefined`)); err != nil {
+		return stepErr(actionSearch, "wait_state", err)
+	}
 
-		// 应用所有筛选条件
-		for _, filter := range allInternalFilters {
-			selector := fmt.Sprintf(`div.filter-panel div.filters:nth-child(%d) div.tags:nth-child(%d)`,
-				filter.FiltersIndex, filter.TagsIndex)
-			option := page.MustElement(selector)
-			option.MustClick()
+	// 如果有筛选条件，则应用筛选，丢弃筛选前收到的接口响应
+	if len(filters) > 0 {
+		capture.reset()
+		if err := applySearchFilters(page, filters); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
 
-		// 等待页面更新
-		page.MustWaitStable()
-		// 重新等待 __INITIAL_STATE__ 更新
-		page.MustWait(`() => window.__INITIAL_STATE__ !== undefined`)
+// searchFeeds 页面状态中的搜索结果，再按顺序追加接口响应中状态里还没有的笔记
+func searchFeeds(page *rod.Page, capture *apiCapture) ([]Feed, error) {
+	feeds, err := readSearchFeeds(page)
+	if err != nil {
+		return nil, err
 	}
+	return appendNewFeeds(feeds, capture.feeds(apiSearchNotes)), nil
+}
 
-	result := page.MustEval(`() => {
+// readSearchFeeds 读取 __INITIAL_STATE__ 中当前已加载的搜索结果，页面没有结果时返回空
+func readSearchFeeds(page *rod.Page) ([]Feed, error) {
+	result, err := evalString(page, `() => {
 		if (window.__INITIAL_STATE__ &&
 		    window.__INITIAL_STATE__.search &&
 		    window.__INITIAL_STATE__.search.feeds) {
@@ -225,20 +328,60 @@ func (s *SearchAction) Search(ctx context.Context, keyword string, filters ...Fi
 			}
 		}
 		return "";
-	}`).String()
+	}`)
+	if err != nil {
+		return nil, stepErr(actionSearch, "read_state", err)
+	}
 
 	if result == "" {
-		return nil, errors.ErrNoFeeds
+		return nil, nil
 	}
 
 	var feeds []Feed
 	if err := json.Unmarshal([]byte(result), &feeds); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal feeds: %w", err)
+		return nil, stepErr(actionSearch, "parse_state", fmt.Errorf("failed to unmarshal feeds: %w", err))
 	}
 
 	return feeds, nil
 }
 
+// applySearchFilters 打开筛选面板并依次点击筛选条件
+func applySearchFilters(page *rod.Page, filters []internalFilterOption) error {
+	// 悬停在筛选按钮上
+	filterButton, err := page.Element(`div.filter`)
+	if err != nil {
+		return stepErr(actionSearch, "find_filter_button", err)
+	}
+	if err := filterButton.Hover(); err != nil {
+		return stepErr(actionSearch, "hover_filter_button", err)
+	}
+
+	// 等待筛选面板出现
+	if err := page.Wait(rod.Eval(`() => document.querySelector('div.filter-panel') !== null`)); err != nil {
+		return stepErr(actionSearch, "wait_filter_panel", err)
+	}
+
+	// 应用所有筛选条件
+	for _, filter := range filters {
+		selector := fmt.Sprintf(`div.filter-panel div.filters:nth-child(%d) div.tags:nth-child(%d)`,
+			filter.FiltersIndex, filter.TagsIndex)
+		if err := click(page, selector); err != nil {
+			return stepErr(actionSearch, "click_filter", fmt.Errorf("%s: %w", filter.Text, err))
+		}
+	}
+
+	// 等待页面更新
+	if err := page.WaitStable(time.Second); err != nil {
+		return stepErr(actionSearch, "wait_stable", err)
+	}
+	// 重新等待 __INITIAL_STATE__ 更新
+	if err := page.Wait(rod.Eval(`() => window.__INITIAL_STATE__ !== undefined`)); err != nil {
+		return stepErr(actionSearch, "wait_state", err)
+	}
+
+	return nil
+}
+
 func makeSearchURL(keyword string) string {
 
 	values := url.Values{}
@@ -247,5 +390,5 @@ func makeSearchURL(keyword string) string {
 
 	//https://www.xiaohongshu.com/search_result?keyword=%25E7%258E%258B%25E5%25AD%2590&source=web_search_result_notes
 	//https://www.xiaohongshu.com/search_result?keyword=%25E7%258E%258B%25E5%25AD%2590&source=web_explore_feed
-	return fmt.Sprintf("https://www.xiaohongshu.com/search_result?%s", values.Encode())
+	return configs.SiteURL("/search_result?" + values.Encode())
 }
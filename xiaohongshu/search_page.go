@@ -0,0 +1,160 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/go-rod/rod"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+const (
+	// DefaultSearchLimit 分页搜索默认返回的笔记数，与结果页首屏数量相当
+	DefaultSearchLimit = 20
+	// MaxSearchLimit 分页搜索单次最多返回的笔记数
+	MaxSearchLimit = 100
+	// MaxSearchOffset 游标最多可以翻到的位置。续页会从头重新滚动，位置过深时无法在超时前到达
+	MaxSearchOffset = 500
+
+	// scrollWait 每次滚动后等待新结果加载的时间
+	scrollWait = 3 * time.Second
+	// scrollPoll 等待新结果时检查的间隔
+	scrollPoll = 300 * time.Millisecond
+	// maxStalledScrolls 连续滚动多少次没有新结果时认为已经到底
+	maxStalledScrolls = 2
+	// scrollReserve 滚动时为读取结果预留的时间，操作剩余时间不足时停止滚动并返回已收集的结果
+	scrollReserve = 5 * time.Second
+)
+
+// SearchPage 分页搜索的结果
+type SearchPage struct {
+	Feeds      []Feed
+	NextCursor string // 继续获取下一页的游标，为空表示没有更多结果
+}
+
+// searchCursor 游标的内容，编码后对调用方不透明
+type searchCursor struct {
+	Keyword string         `json:"k"`
+	Filters []FilterOption `json:"f,omitempty"`
+	Offset  int            `json:"o"`
+}
+
+func encodeSearchCursor(keyword string, filters []FilterOption, offset int) string {
+	data, _ := json.Marshal(searchCursor{
+		Keyword: keyword,
+		Filters: nonEmptyFilters(filters),
+		Offset:  offset,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSearchCursor 解析游标并返回已返回的条数，游标为空时返回 0。
+// 游标只能用于生成它的关键词和筛选条件。
+func decodeSearchCursor(cursor, keyword string, filters []FilterOption) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	var c searchCursor
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil || c.Offset < 0 || c.Offset > MaxSearchOffset {
+		return 0, myerrors.Validation("无效的 cursor")
+	}
+
+	if c.Keyword != keyword || !reflect.DeepEqual(c.Filters, nonEmptyFilters(filters)) {
+		return 0, myerrors.Validation("cursor 与本次搜索的关键词或筛选条件不一致")
+	}
+	return c.Offset, nil
+}
+
+// nonEmptyFilters 去掉未设置任何条件的筛选项，没有剩余时返回 nil
+func nonEmptyFilters(filters []FilterOption) []FilterOption {
+	var result []FilterOption
+	for _, f := range filters {
+		if f != (FilterOption{}) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// collectSearchFeeds 滚动结果页直到按 ID 去重后的笔记数达到 need 或没有更多结果。
+// 操作剩余时间不足时停止滚动，truncated 为 true，表示后面可能还有结果。
+func collectSearchFeeds(ctx context.Context, page *rod.Page, capture *apiCapture, need int) (feeds []Feed, truncated bool, err error) {
+	stalled := 0
+	for {
+		loaded, err := searchFeeds(page, capture)
+		if err != nil {
+			return nil, false, err
+		}
+
+		feeds := uniqueNoteFeeds(loaded)
+		if len(feeds) >= need || stalled >= maxStalledScrolls {
+			return feeds, false, nil
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < scrollWait+scrollReserve {
+			return feeds, true, nil
+		}
+
+		ended, _, err := page.Has(`.end-container`)
+		if err != nil {
+			return nil, false, stepErr(actionSearch, "check_end", err)
+		}
+		if ended {
+			return feeds, false, nil
+		}
+
+		if _, err := page.Eval(`() => window.scrollTo(0, document.documentElement.scrollHeight)`); err != nil {
+			return nil, false, stepErr(actionSearch, "scroll", err)
+		}
+
+		grown, err := waitMoreSearchFeeds(ctx, page, capture, len(loaded))
+		if err != nil {
+			return nil, false, err
+		}
+		if grown {
+			stalled = 0
+		} else {
+			stalled++
+		}
+	}
+}
+
+// waitMoreSearchFeeds 等待页面状态或接口响应中的结果多于 n 条，超过 scrollWait 仍没有新结果时返回 false
+func waitMoreSearchFeeds(ctx context.Context, page *rod.Page, capture *apiCapture, n int) (bool, error) {
+	deadline := time.Now().Add(scrollWait)
+	for time.Now().Before(deadline) {
+		if err := sleep(ctx, scrollPoll); err != nil {
+			return false, stepErr(actionSearch, "wait_more", err)
+		}
+
+		loaded, err := searchFeeds(page, capture)
+		if err != nil {
+			return false, err
+		}
+		if len(loaded) > n {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// uniqueNoteFeeds 按 ID 去重并去掉非笔记的卡片（如相关搜索词），保持原有顺序
+func uniqueNoteFeeds(feeds []Feed) []Feed {
+	seen := make(map[string]bool, len(feeds))
+	result := make([]Feed, 0, len(feeds))
+	for _, f := range feeds {
+		if f.ID == "" || (f.ModelType != "" && f.ModelType != "note") || seen[f.ID] {
+			continue
+		}
+		seen[f.ID] = true
+		result = append(result, f)
+	}
+	return result
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
 func TestSearch(t *testing.T) {
@@ -103,3 +104,46 @@ func TestFilterValidation(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, internalFilters, 5)
 }
+
+func TestSearchCursor(t *testing.T) {
+	filters := []FilterOption{{}, {NoteType: "图文"}}
+
+	offset, err := decodeSearchCursor("", "咖啡", filters)
+	require.NoError(t, err)
+	require.Equal(t, 0, offset)
+
+	cursor := encodeSearchCursor("咖啡", filters, 40)
+	offset, err = decodeSearchCursor(cursor, "咖啡", []FilterOption{{NoteType: "图文"}})
+	require.NoError(t, err)
+	require.Equal(t, 40, offset)
+
+	_, err = decodeSearchCursor(cursor, "奶茶", filters)
+	require.Equal(t, myerrors.CodeValidationFailed, myerrors.CodeOf(err))
+	_, err = decodeSearchCursor(cursor, "咖啡", nil)
+	require.Equal(t, myerrors.CodeValidationFailed, myerrors.CodeOf(err))
+	_, err = decodeSearchCursor("not a cursor", "咖啡", filters)
+	require.Equal(t, myerrors.CodeValidationFailed, myerrors.CodeOf(err))
+	_, err = decodeSearchCursor(encodeSearchCursor("咖啡", nil, -1), "咖啡", nil)
+	require.Equal(t, myerrors.CodeValidationFailed, myerrors.CodeOf(err))
+	_, err = decodeSearchCursor(encodeSearchCursor("咖啡", nil, MaxSearchOffset+1), "咖啡", nil)
+	require.Equal(t, myerrors.CodeValidationFailed, myerrors.CodeOf(err))
+
+	// limit 在打开页面之前校验
+	_, err = NewSearchAction(nil).SearchWithCursor(context.Background(), "咖啡", -1, "")
+	require.Equal(t, myerrors.CodeValidationFailed, myerrors.CodeOf(err))
+	_, err = NewSearchAction(nil).SearchWithCursor(context.Background(), "咖啡", MaxSearchLimit+1, "")
+	require.Equal(t, myerrors.CodeValidationFailed, myerrors.CodeOf(err))
+}
+
+func TestUniqueNoteFeeds(t *testing.T) {
+	feeds := uniqueNoteFeeds([]Feed{
+		{ID: "a", ModelType: "note"},
+		{ID: "hot", ModelType: "hot_query"},
+		{ID: "b"},
+		{ID: "a", ModelType: "note"},
+		{ModelType: "note"},
+	})
+	require.Len(t, feeds, 2)
+	require.Equal(t, "a", feeds[0].ID)
+	require.Equal(t, "b", feeds[1].ID)
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/go-rod/rod"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
@@ -33,59 +35,63 @@ type UserLikesAction struct {
 	page *rod.Page
 }
 
+const (
+	actionUserLikes = "user_likes"
+
+	defaultTimeout = 60 * time.Second
+)
+
 // NewUserLikesAction 创建 UserLikesAction 实例
 func NewUserLikesAction(page *rod.Page) *UserLikesAction {
-	action := &UserLikesAction{
+	return &UserLikesAction{
 		page: page,
 	}
-	// 只有当 page 不为 nil 时才设置 timeout
-	if page != nil {
-		action.page = page.Timeout(60 * time.Second)
-	}
-	return action
 }
 
 // GetUserLikedNotes 获取用户点赞的所有笔记
 func (u *UserLikesAction) GetUserLikedNotes(ctx context.Context) (*UserLikesResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
 	page := u.page.Context(ctx)
 
 	// 1. 首先导航到个人主页的点赞标签页
 	if err := u.navigateToLikesPage(page); err != nil {
-		return nil, fmt.Errorf("failed to navigate to likes page: %w", err)
+		return nil, err
 	}
 
 	// 2. 等待页面加载完成
-	page.MustWaitStable()
-	time.Sleep(2 * time.Second)
-
-	// 3. 获取点赞的笔记数据
-	likedNotes, err := u.extractLikedNotes(page)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract liked notes: %w", err)
+	if err := page.WaitStable(time.Second); err != nil {
+		return nil, myerrors.NewActionError(actionUserLikes, "wait_stable", err)
+	}
+	select {
+	case <-ctx.Done():
+		return nil, myerrors.NewActionError(actionUserLikes, "wait_state", ctx.Err())
+	case <-time.After(2 * time.Second):
 	}
 
-	return likedNotes, nil
+	// 3. 获取点赞的笔记数据
+	return u.extractLikedNotes(page)
 }
 
 // navigateToLikesPage 导航到用户个人主页的点赞标签页
 func (u *UserLikesAction) navigateToLikesPage(page *rod.Page) error {
-	// 首先访问用户个人主页
-
 	navigation := xiaohongshu.NewNavigate(page)
-	navigation.ToUserLikesPage(page.GetContext())
-
-	return nil
+	return navigation.ToUserLikesPage(page.GetContext())
 }
 
 // extractLikedNotes 从页面中提取点赞的笔记数据
 func (u *UserLikesAction) extractLikedNotes(page *rod.Page) (*UserLikesResponse, error) {
 	// 等待页面数据加载
-	page.MustWait(`() => {
+	err := page.Wait(rod.Eval(`() => {
 		// 等待 __INITIAL_STATE__ 或者页面内容加载
 		return window.__INITIAL_STATE__ !== undefined ||
 		       document.querySelector('.feeds-container') !== null ||
 		       document.querySelector('.note-item') !== null;
-	}`)
+	}`))
+	if err != nil {
+		return nil, myerrors.NewActionError(actionUserLikes, "wait_state", err)
+	}
 
 	// 尝试从 __INITIAL_STATE__ 获取数据
 	if likedFeeds, err := u.extractFromInitialState(page); err == nil {
@@ -100,7 +106,7 @@ func (u *UserLikesAction) extractLikedNotes(page *rod.Page) (*UserLikesResponse,
 
 // extractFromInitialState 从 __INITIAL_STATE__ 提取数据
 func (u *UserLikesAction) extractFromInitialState(page *rod.Page) (*UserLikesResponse, error) {
-	result := page.MustEval(`() => {
+	res, err := page.Eval(`() => {
 		try {
 			if (window.__INITIAL_STATE__) {
 				// 尝试多个可能的数据路径
@@ -142,8 +148,12 @@ func (u *UserLikesAction) extractFromInitialState(page *rod.Page) (*UserLikesRes
 			console.error('Error extracting from __INITIAL_STATE__:', error);
 			return null;
 		}
-	}`).String()
+	}`)
+	if err != nil {
+		return nil, err
+	}
 
+	result := res.Value.String()
 	if result == "" || result == "null" {
 		return nil, fmt.Errorf("no liked notes data found in __INITIAL_STATE__")
 	}
@@ -179,7 +189,7 @@ func (u *UserLikesAction) extractFromInitialState(page *rod.Page) (*UserLikesRes
 
 // extractFromDOM 从 DOM 元素提取数据
 func (u *UserLikesAction) extractFromDOM(page *rod.Page) (*UserLikesResponse, error) {
-	result := page.MustEval(`() => {
+	res, err := page.Eval(`() => {
 		const likedFeeds = [];
 
 		// 查找笔记项的多种可能选择器
@@ -242,11 +252,14 @@ func (u *UserLikesAction) extractFromDOM(page *rod.Page) (*UserLikesResponse, er
 		}
 
 		return JSON.stringify(likedFeeds);
-	}`).String()
+	}`)
+	if err != nil {
+		return nil, myerrors.NewActionError(actionUserLikes, "read_dom", err)
+	}
 
 	var likedFeeds []LikedFeed
-	if err := json.Unmarshal([]byte(result), &likedFeeds); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal DOM parsed data: %w", err)
+	if err := json.Unmarshal([]byte(res.Value.String()), &likedFeeds); err != nil {
+		return nil, myerrors.NewActionError(actionUserLikes, "parse_dom", fmt.Errorf("failed to unmarshal DOM parsed data: %w", err))
 	}
 
 	return &UserLikesResponse{
@@ -313,7 +326,7 @@ func (u *UserLikesAction) parseFeedItem(feed map[string]interface{}) LikedFeed {
 
 	// 构建 URL
 	if likedFeed.FeedID != "" {
-		likedFeed.URL = fmt.Sprintf("https://www.xiaohongshu.com/explore/%s", likedFeed.FeedID)
+		likedFeed.URL = configs.SiteURL("/explore/" + likedFeed.FeedID)
 	}
 
 	// 设置点赞时间
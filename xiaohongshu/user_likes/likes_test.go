@@ -1,10 +1,13 @@
 package user_likes
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu/mocksite"
 )
 
 // TestNewUserLikesAction tests the constructor
@@ -37,3 +40,24 @@ func TestGotoProfilePage(t *testing.T) {
 
 	action.navigateToLikesPage(page)
 }
+
+func TestGetUserLikedNotesWithMockSite(t *testing.T) {
+	site, b := mocksite.NewBrowser(t)
+	page := b.NewPage()
+	defer page.Close()
+	page.MustSetCookies(site.Login())
+
+	var liked []string
+	for _, n := range site.Notes() {
+		if n.Liked {
+			liked = append(liked, n.ID)
+		}
+	}
+
+	resp, err := NewUserLikesAction(page).GetUserLikedNotes(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, len(liked), resp.Count)
+	for i, feed := range resp.LikedFeeds {
+		require.Equal(t, liked[i], feed.FeedID)
+	}
+}
@@ -7,33 +7,66 @@ import (
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
 type UserProfileAction struct {
 	page *rod.Page
 }
 
+const (
+	actionUserProfile = "user_profile"
+	actionMyProfile   = "my_profile"
+)
+
 func NewUserProfileAction(page *rod.Page) *UserProfileAction {
-	pp := page.Timeout(60 * time.Second)
-	return &UserProfileAction{page: pp}
+	return &UserProfileAction{page: page}
 }
 
-// UserProfile 获取用户基本信息及帖子
-func (u *UserProfileAction) UserProfile(ctx context.Context, userID, xsecToken string) (*UserProfileResponse, error) {
+// MaxUserNotesLimit 获取用户主页时最多返回的笔记数
+const MaxUserNotesLimit = 300
+
+// UserProfile 获取用户基本信息及帖子。
+// limit 为最多返回的笔记数，0 时只返回主页首屏的笔记；超过首屏数量且还有更多时会滚动主页加载后续分页。
+func (u *UserProfileAction) UserProfile(ctx context.Context, userID, xsecToken string, limit int) (*UserProfileResponse, error) {
+	if limit < 0 || limit > MaxUserNotesLimit {
+		return nil, errors.Validation(fmt.Sprintf("limit 需要在 0 到 %d 之间", MaxUserNotesLimit))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
+
 	page := u.page.Context(ctx)
 
+	capture, err := captureAPI(page, apiUserPosted)
+	if err != nil {
+		return nil, stepErr(actionUserProfile, "capture_api", err)
+	}
+	defer capture.stop()
+
 	searchURL := makeUserProfileURL(userID, xsecToken)
-	page.MustNavigate(searchURL)
-	page.MustWaitStable()
+	if err := page.Navigate(searchURL); err != nil {
+		return nil, stepErr(actionUserProfile, "navigate", err)
+	}
+	if err := page.WaitStable(time.Second); err != nil {
+		return nil, stepErr(actionUserProfile, "wait_stable", err)
+	}
+	if err := checkPage(page); err != nil {
+		return nil, stepErr(actionUserProfile, "check_page", err)
+	}
 
-	return u.extractUserProfileData(page)
+	return u.extractUserProfileData(ctx, page, capture, actionUserProfile, limit)
 }
 
-// extractUserProfileData 从页面中提取用户资料数据的通用方法
-func (u *UserProfileAction) extractUserProfileData(page *rod.Page) (*UserProfileResponse, error) {
-	page.MustWait(`() => window.__INITIAL_STATE__ !== undefined`)
+// extractUserProfileData 从页面中提取用户资料数据的通用方法。
+// 页面状态中只有首屏的笔记，limit 超过已有的笔记数且主页还有更多时才滚动笔记列表，追加用户笔记接口返回的后续分页。
+func (u *UserProfileAction) extractUserProfileData(ctx context.Context, page *rod.Page, capture *apiCapture, action string, limit int) (*UserProfileResponse, error) {
+	if err := page.Wait(rod.Eval(`() => window.__INITIAL_STATE__ !== undefined`)); err != nil {
+		return nil, stepErr(action, "wait_state", err)
+	}
 
-	userDataResult := page.MustEval(`() => {
+	userDataResult, err := evalString(page, `() => {
 		if (window.__INITIAL_STATE__ &&
 		    window.__INITIAL_STATE__.user &&
 		    window.__INITIAL_STATE__.user.userPageData) {
@@ -44,29 +77,13 @@ func (u *UserProfileAction) extractUserProfileData(page *rod.Page) (*UserProfile
 			}
 		}
 		return "";
-	}`).String()
-
-	if userDataResult == "" {
-		return nil, fmt.Errorf("user.userPageData.value not found in __INITIAL_STATE__")
+	}`)
+	if err != nil {
+		return nil, stepErr(action, "read_state", err)
 	}
 
-	// 2. 获取用户帖子：window.__INITIAL_STATE__.user.notes.value
-	notesResult := page.MustEval(`() => {
-		if (window.__INITIAL_STATE__ &&
-		    window.__INITIAL_STATE__.user &&
-		    window.__INITIAL_STATE__.user.notes) {
-			const notes = window.__INITIAL_STATE__.user.notes;
-			// 优先使用 value（getter），如果不存在则使用 _value（内部字段）
-			const data = notes.value !== undefined ? notes.value : notes._value;
-			if (data) {
-				return JSON.stringify(data);
-			}
-		}
-		return "";
-	}`).String()
-
-	if notesResult == "" {
-		return nil, fmt.Errorf("user.notes.value not found in __INITIAL_STATE__")
+	if userDataResult == "" {
+		return nil, stepErr(action, "read_state", fmt.Errorf("user.userPageData.value not found in __INITIAL_STATE__"))
 	}
 
 	// 解析用户信息
@@ -75,38 +92,107 @@ func (u *UserProfileAction) extractUserProfileData(page *rod.Page) (*UserProfile
 		BasicInfo    UserBasicInfo      `json:"basicInfo"`
 	}
 	if err := json.Unmarshal([]byte(userDataResult), &userPageData); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal userPageData: %w", err)
+		return nil, stepErr(action, "parse_state", fmt.Errorf("failed to unmarshal userPageData: %w", err))
 	}
 
-	// 解析帖子数据（帖子为双重数组）
-	var notesFeeds [][]Feed
-	if err := json.Unmarshal([]byte(notesResult), &notesFeeds); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal notes: %w", err)
+	notes, err := readUserNotes(page, action)
+	if err != nil {
+		return nil, err
 	}
+	feeds := appendNewFeeds(notes.feeds, capture.userNotes())
 
-	// 组装响应
-	response := &UserProfileResponse{
+	if limit > len(feeds) && notes.hasMore {
+		loaded := func() bool {
+			return len(appendNewFeeds(notes.feeds, capture.userNotes())) >= limit
+		}
+		if err := scrollToLoad(ctx, page, capture, apiUserPosted, "", ".end-container", loaded); err != nil {
+			return nil, stepErr(action, "scroll_notes", err)
+		}
+		if notes, err = readUserNotes(page, action); err != nil {
+			return nil, err
+		}
+		feeds = appendNewFeeds(notes.feeds, capture.userNotes())
+	}
+
+	if !notes.found && len(feeds) == 0 {
+		return nil, stepErr(action, "read_state", fmt.Errorf("user.notes.value not found in __INITIAL_STATE__"))
+	}
+	if limit > 0 && len(feeds) > limit {
+		feeds = feeds[:limit]
+	}
+
+	return &UserProfileResponse{
 		UserBasicInfo: userPageData.BasicInfo,
 		Interactions:  userPageData.Interactions,
-	}
+		Feeds:         feeds,
+	}, nil
+}
+
+// userNotes 页面状态中的用户笔记，found 表示页面状态中有笔记列表，hasMore 表示主页还有下一页
+type userNotes struct {
+	feeds   []Feed
+	found   bool
+	hasMore bool
+}
 
-	// 添加用户帖子（展平双重数组）
-	for _, feeds := range notesFeeds {
-		if len(feeds) != 0 {
-			response.Feeds = append(response.Feeds, feeds...)
+// readUserNotes 读取 window.__INITIAL_STATE__.user.notes 中的笔记和 noteQueries 中的分页状态
+func readUserNotes(page *rod.Page, action string) (userNotes, error) {
+	result, err := evalString(page, `() => {
+		const user = window.__INITIAL_STATE__ && window.__INITIAL_STATE__.user;
+		if (!user || !user.notes) {
+			return "";
 		}
+		// 优先使用 value（getter），如果不存在则使用 _value（内部字段）
+		const notes = user.notes.value !== undefined ? user.notes.value : user.notes._value;
+		if (!notes) {
+			return "";
+		}
+		let queries = user.noteQueries;
+		if (queries && !Array.isArray(queries)) {
+			queries = queries.value !== undefined ? queries.value : queries._value;
+		}
+		const hasMore = !!(queries && queries[0] && queries[0].hasMore);
+		return JSON.stringify({notes: notes, hasMore: hasMore});
+	}`)
+	if err != nil {
+		return userNotes{}, stepErr(action, "read_state", err)
+	}
+	if result == "" {
+		return userNotes{}, nil
+	}
+
+	// 帖子为双重数组，按标签页分组
+	var state struct {
+		Notes   [][]Feed `json:"notes"`
+		HasMore bool     `json:"hasMore"`
+	}
+	if err := json.Unmarshal([]byte(result), &state); err != nil {
+		return userNotes{}, stepErr(action, "parse_state", fmt.Errorf("failed to unmarshal notes: %w", err))
 	}
 
-	return response, nil
+	notes := userNotes{found: true, hasMore: state.HasMore}
+	for _, feeds := range state.Notes {
+		notes.feeds = append(notes.feeds, feeds...)
+	}
+	return notes, nil
 }
 
 func makeUserProfileURL(userID, xsecToken string) string {
-	return fmt.Sprintf("https://www.xiaohongshu.com/user/profile/%s?xsec_token=%s&xsec_source=pc_note", userID, xsecToken)
+	return configs.SiteURL(fmt.Sprintf("/user/profile/%s?xsec_token=%s&xsec_source=pc_note", userID, xsecToken))
 }
 
 func (u *UserProfileAction) GetMyProfileViaSidebar(ctx context.Context) (*UserProfileResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultActionTimeout)
+	defer cancel()
+
 	page := u.page.Context(ctx)
 
+	capture, err := captureAPI(page, apiUserPosted)
+	if err != nil {
+		return nil, stepErr(actionMyProfile, "capture_api", err)
+	}
+	defer capture.stop()
+
 	// 创建导航动作
 	navigate := NewNavigate(page)
 
@@ -116,7 +202,9 @@ func (u *UserProfileAction) GetMyProfileViaSidebar(ctx context.Context) (*UserPr
 	}
 
 	// 等待页面加载完成并获取 __INITIAL_STATE__
-	page.MustWaitStable()
+	if err := page.WaitStable(time.Second); err != nil {
+		return nil, stepErr(actionMyProfile, "wait_stable", err)
+	}
 
-	return u.extractUserProfileData(page)
+	return u.extractUserProfileData(ctx, page, capture, actionMyProfile, 0)
 }